@@ -23,6 +23,7 @@ import (
 
 	"github.com/vulcanize/vulcanizedb/pkg/crypto"
 	"github.com/vulcanize/vulcanizedb/pkg/datastore/ethereum"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
 	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
 	"github.com/vulcanize/vulcanizedb/pkg/fs"
 	"github.com/vulcanize/vulcanizedb/pkg/geth/cold_import"
@@ -31,6 +32,11 @@ import (
 	"github.com/vulcanize/vulcanizedb/utils"
 )
 
+var (
+	coldImportConfig cold_import.ColdImportConfig
+	headersOnly      bool
+)
+
 var coldImportCmd = &cobra.Command{
 	Use:   "coldImport",
 	Short: "Sync vulcanize from a cold instance of LevelDB.",
@@ -38,7 +44,12 @@ var coldImportCmd = &cobra.Command{
 
 ./vulcanizedb coldImport -s 0 -e 5000000
 
-Geth must be synced over all of the desired blocks and must not be running in order to execute this command.`,
+Geth must be synced over all of the desired blocks and must not be running in order to execute this command.
+Blocks geth has moved into its ancient store are read from chaindata/ancient, or from --client-ancientPath if set.
+The network id is taken from the chain config geth stored alongside the genesis block.
+Progress is checkpointed, so an interrupted import run again with the same starting block resumes where it stopped.
+
+With --headers-only, only the headers used by lightSync and the light transformers are imported.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		coldImport()
 	},
@@ -49,11 +60,14 @@ func init() {
 	coldImportCmd.Flags().Int64VarP(&startingBlockNumber, "starting-block-number", "s", 0, "BlockNumber for first block to cold import.")
 	coldImportCmd.Flags().Int64VarP(&endingBlockNumber, "ending-block-number", "e", 5500000, "BlockNumber for last block to cold import.")
 	coldImportCmd.Flags().BoolVarP(&syncAll, "all", "a", false, "Option to sync all missing blocks.")
+	coldImportCmd.Flags().BoolVar(&headersOnly, "headers-only", false, "only import headers, for light sync")
+	coldImportCmd.Flags().IntVar(&coldImportConfig.Workers, "workers", cold_import.DefaultColdImportConfig.Workers, "number of concurrent LevelDB readers and converters")
+	coldImportCmd.Flags().IntVar(&coldImportConfig.BatchSize, "batch-size", cold_import.DefaultColdImportConfig.BatchSize, "number of blocks imported between checkpoints")
 }
 
 func coldImport() {
 	// init eth db
-	ethDBConfig := ethereum.CreateDatabaseConfig(ethereum.Level, levelDbPath)
+	ethDBConfig := ethereum.CreateDatabaseConfig(ethereum.Level, levelDbPath, ancientPath)
 	ethDB, err := ethereum.CreateDatabase(ethDBConfig)
 	if err != nil {
 		log.Fatal("Error connecting to ethereum db: ", err)
@@ -75,20 +89,32 @@ func coldImport() {
 	reader := fs.FsReader{}
 	parser := crypto.EthPublicKeyParser{}
 	nodeBuilder := cold_import.NewColdImportNodeBuilder(reader, parser)
-	coldNode, err := nodeBuilder.GetNode(genesisBlock, levelDbPath)
+	coldNode, err := nodeBuilder.GetNode(genesisBlock, ethDB.GetChainConfig(genesisBlock), levelDbPath)
 	if err != nil {
 		log.Fatal("Error getting node: ", err)
 	}
 	pgDB := utils.LoadPostgres(databaseConfig, coldNode)
 
+	if headersOnly {
+		headerRepository := repositories.NewHeaderRepository(&pgDB)
+		headerWriter := postgres.NewBulkHeaderWriter(&pgDB)
+		headerImporter := cold_import.NewColdHeaderImporter(ethDB, headerRepository, headerWriter, finalityConfig.DepthForNetwork(coldNode.NetworkID), coldImportConfig)
+		err = headerImporter.Execute(startingBlockNumber, endingBlockNumber, coldNode.ID)
+		if err != nil {
+			log.Fatal("Error executing cold header import: ", err)
+		}
+		return
+	}
+
 	// init cold importer deps
 	blockRepository := repositories.NewBlockRepository(&pgDB)
-	receiptRepository := repositories.ReceiptRepository{DB: &pgDB}
+	blockWriter := postgres.NewBulkBlockWriter(&pgDB)
+	checkpointRepository := repositories.NewCheckpointRepository(&pgDB)
 	transactionConverter := cold_db.NewColdDbTransactionConverter()
-	blockConverter := vulcCommon.NewBlockConverter(transactionConverter)
+	blockConverter := vulcCommon.NewBlockConverter(transactionConverter, chainConfig.RewardScheduleForNetwork(coldNode.NetworkID))
 
 	// init and execute cold importer
-	coldImporter := cold_import.NewColdImporter(ethDB, blockRepository, receiptRepository, blockConverter)
+	coldImporter := cold_import.NewColdImporter(ethDB, blockRepository, blockWriter, checkpointRepository, blockConverter, finalityConfig.DepthForNetwork(coldNode.NetworkID), coldImportConfig)
 	err = coldImporter.Execute(startingBlockNumber, endingBlockNumber, coldNode.ID)
 	if err != nil {
 		log.Fatal("Error executing cold import: ", err)
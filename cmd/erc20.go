@@ -18,7 +18,6 @@ package cmd
 
 import (
 	"log"
-	"time"
 
 	"github.com/spf13/cobra"
 
@@ -26,6 +25,7 @@ import (
 	"github.com/vulcanize/vulcanizedb/examples/erc20_watcher/every_block"
 	"github.com/vulcanize/vulcanizedb/examples/generic"
 	"github.com/vulcanize/vulcanizedb/libraries/shared"
+	"github.com/vulcanize/vulcanizedb/pkg/history"
 	"github.com/vulcanize/vulcanizedb/pkg/omni/shared/constants"
 	"github.com/vulcanize/vulcanizedb/utils"
 )
@@ -54,9 +54,6 @@ Expects an ethereum node to be running and requires a .toml config file:
 }
 
 func watchERC20s() {
-	ticker := time.NewTicker(5 * time.Second)
-	defer ticker.Stop()
-
 	blockChain := getBlockChain()
 	db := utils.LoadPostgres(databaseConfig, blockChain.Node())
 
@@ -77,7 +74,7 @@ func watchERC20s() {
 		log.Fatal(err)
 	}
 
-	for range ticker.C {
+	for range history.NewHeads(blockChain, watcherPollingInterval) {
 		watcher.Execute()
 	}
 }
@@ -19,11 +19,11 @@ package cmd
 import (
 	"fmt"
 	"log"
-	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/vulcanize/vulcanizedb/libraries/shared"
+	"github.com/vulcanize/vulcanizedb/pkg/history"
 	"github.com/vulcanize/vulcanizedb/pkg/omni/light/transformer"
 	"github.com/vulcanize/vulcanizedb/utils"
 )
@@ -45,6 +45,35 @@ Requires a .toml config file:
 
   [client]
   ipcPath = "/Users/user/Library/Ethereum/geth.ipc"
+
+Contract ABIs are read from --abi-file, --abi-dir or the contract_abis table,
+falling back to etherscan unless --abi-etherscan=false is passed.
+
+Instead of the contract flags, each contract can be configured in its own
+[[contract]] section; omitted fields take the same defaults as the flags:
+
+  [[contract]]
+  address = "0x8dd5fbCe2F6a956C3022bA3663759011Dd51e73E"
+  abiFile = "abis/tusd.json"
+  events = ["Transfer", "Approval"]
+  methods = ["balanceOf", "allowance"]
+  methodFilterAddresses = ["0x8dd5fbCe2F6a956C3022bA3663759011Dd51e73E"]
+  startingBlock = 5197514
+
+Methods taking a single address are polled for the token holders seen in events;
+other methods are polled with argument sets given directly or as fields of one
+event, whose distinct logged values are each polled with:
+
+  [[contract.methodArgs]]
+  method = "allowance"
+  args = [["0x8dd5fbCe2F6a956C3022bA3663759011Dd51e73E", "0x3bc6fE8B2E43e3CFdd8AE6c8E1A1AB1Ec6C7E0f2"]]
+  eventFields = ["Approval.owner", "Approval.spender"]
+
+  [[contract]]
+  address = "0x89d24A6b4CcB1B6fAA2625fE562bDD9a23260359"
+  network = "kovan"
+  eventFilterAddresses = ["0x3bc6fE8B2E43e3CFdd8AE6c8E1A1AB1Ec6C7E0f2"]
+  endingBlock = 6000000
 `,
 	Run: func(cmd *cobra.Command, args []string) {
 		lightOmniWatcher()
@@ -52,25 +81,14 @@ Requires a .toml config file:
 }
 
 func lightOmniWatcher() {
-	if contractAddress == "" && len(contractAddresses) == 0 {
-		log.Fatal("Contract address required")
-	}
-
-	ticker := time.NewTicker(5 * time.Second)
-	defer ticker.Stop()
+	contracts := loadContracts()
 
 	blockChain := getBlockChain()
 	db := utils.LoadPostgres(databaseConfig, blockChain.Node())
 	t := transformer.NewTransformer(network, blockChain, &db)
-
-	contractAddresses = append(contractAddresses, contractAddress)
-	for _, addr := range contractAddresses {
-		t.SetEvents(addr, contractEvents)
-		t.SetMethods(addr, contractMethods)
-		t.SetEventAddrs(addr, eventAddrs)
-		t.SetMethodAddrs(addr, methodAddrs)
-		t.SetRange(addr, [2]int64{startingBlockNumber, endingBlockNumber})
-	}
+	t.Parser = newAbiParser(&db, contracts)
+	t.FinalOnly = finalOnly
+	watchContracts(t, contracts)
 
 	err := t.Init()
 	if err != nil {
@@ -80,7 +98,7 @@ func lightOmniWatcher() {
 	w := shared.Watcher{}
 	w.AddTransformer(t)
 
-	for range ticker.C {
+	for range history.NewHeads(blockChain, watcherPollingInterval) {
 		w.Execute()
 	}
 }
@@ -94,7 +112,11 @@ func init() {
 	lightOmniWatcherCmd.Flags().StringArrayVarP(&contractMethods, "contract-methods", "m", nil, "Subset of methods to poll; by default no methods are polled")
 	lightOmniWatcherCmd.Flags().StringArrayVarP(&eventAddrs, "event-filter-addresses", "f", []string{}, "Account addresses to persist event data for; default is to persist for all found token holder addresses")
 	lightOmniWatcherCmd.Flags().StringArrayVarP(&methodAddrs, "method-filter-addresses", "g", []string{}, "Account addresses to poll methods with; default is to poll with all found token holder addresses")
+	lightOmniWatcherCmd.Flags().StringVar(&abiFile, "abi-file", "", "ABI file used for every watched contract")
+	lightOmniWatcherCmd.Flags().StringVar(&abiDir, "abi-dir", "", "Directory of ABI files named by contract address, e.g. 0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359.json")
+	lightOmniWatcherCmd.Flags().BoolVar(&abiEtherscan, "abi-etherscan", true, "Fetch ABIs not found locally from etherscan, caching them in the contract_abis table")
 	lightOmniWatcherCmd.Flags().StringVarP(&network, "network", "n", "", `Network the contract is deployed on; options: "ropsten", "kovan", and "rinkeby"; default is mainnet"`)
 	lightOmniWatcherCmd.Flags().Int64VarP(&startingBlockNumber, "starting-block-number", "s", 0, "Block to begin watching- default is first block the contract exists")
 	lightOmniWatcherCmd.Flags().Int64VarP(&endingBlockNumber, "ending-block-number", "d", -1, "Block to end watching- default is most recent block")
+	lightOmniWatcherCmd.Flags().BoolVar(&finalOnly, "final-only", false, "Only watch headers that lightSync has marked final; trades latency for safety from reorgs")
 }
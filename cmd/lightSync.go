@@ -33,7 +33,6 @@ package cmd
 import (
 	"log"
 	"os"
-	"time"
 
 	"github.com/spf13/cobra"
 
@@ -70,33 +69,41 @@ func init() {
 	lightSyncCmd.Flags().Int64VarP(&startingBlockNumber, "starting-block-number", "s", 0, "Block number to start syncing from")
 }
 
-func backFillAllHeaders(blockchain core.BlockChain, headerRepository datastore.HeaderRepository, missingBlocksPopulated chan int, startingBlockNumber int64) {
-	populated, err := history.PopulateMissingHeaders(blockchain, headerRepository, startingBlockNumber)
+func backFillAllHeaders(blockchain core.BlockChain, headerRepository datastore.HeaderRepository, chainValidator *history.HeaderChainValidator, missingBlocksPopulated chan int, startingBlockNumber int64) {
+	startingBlockNumber = retentionConfig.StartingBlockNumber(startingBlockNumber, blockchain.LastBlock().Int64())
+	populated, err := history.PopulateMissingHeaders(blockchain, headerRepository, startingBlockNumber, backFillConfig)
 	if err != nil {
-		log.Fatal("Error populating headers: ", err)
+		log.Println("Error populating headers: ", err)
+	}
+	report, err := chainValidator.ValidateHeaderChain(startingBlockNumber, blockchain.LastBlock().Int64())
+	if err != nil {
+		log.Println("Error validating header chain: ", err)
+	} else if len(report.MissingBlockNumbers) > 0 || len(report.ReplacedBlockNumbers) > 0 {
+		report.Log(os.Stdout)
 	}
 	missingBlocksPopulated <- populated
 }
 
 func lightSync() {
-	ticker := time.NewTicker(pollingInterval)
-	defer ticker.Stop()
 	blockChain := getBlockChain()
 	validateArgs(blockChain)
 	db := utils.LoadPostgres(databaseConfig, blockChain.Node())
 
 	headerRepository := repositories.NewHeaderRepository(&db)
-	validator := history.NewHeaderValidator(blockChain, headerRepository, validationWindow)
+	validator := history.NewHeaderValidator(blockChain, headerRepository, validationWindow, finalityConfig.DepthForNetwork(blockChain.Node().NetworkID))
+	chainValidator := history.NewHeaderChainValidator(blockChain, headerRepository)
 	missingBlocksPopulated := make(chan int)
-	go backFillAllHeaders(blockChain, headerRepository, missingBlocksPopulated, startingBlockNumber)
+	newHeads := history.NewHeads(blockChain, pollingInterval)
+	go backFillAllHeaders(blockChain, headerRepository, chainValidator, missingBlocksPopulated, startingBlockNumber)
+	go retainRecent(retentionConfig, repositories.NewPruneRepository(&db).PruneHeaders, "headers")
 
 	for {
 		select {
-		case <-ticker.C:
+		case <-newHeads:
 			window := validator.ValidateHeaders()
 			window.Log(os.Stdout)
 		case <-missingBlocksPopulated:
-			go backFillAllHeaders(blockChain, headerRepository, missingBlocksPopulated, startingBlockNumber)
+			go backFillAllHeaders(blockChain, headerRepository, chainValidator, missingBlocksPopulated, startingBlockNumber)
 		}
 	}
 }
@@ -0,0 +1,151 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/spf13/cobra"
+
+	"github.com/vulcanize/vulcanizedb/pkg/config"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/migrations"
+)
+
+var (
+	migrateSteps  int
+	migratePlugin string
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate up|down|status|version",
+	Short: "Applies or rolls back database migrations",
+	Long: `Runs the schema migrations compiled into this binary, followed by those
+registered by transformer plugins, against the configured database.
+
+  up       applies all pending migrations
+  down     rolls back the last --steps migrations (default 1) of the core
+           schema, or of the plugin named by --plugin
+  status   lists each migration and whether it has been applied
+  version  prints the current schema version
+
+./vulcanizedb migrate up --config environments/public.toml
+
+Other commands refuse to start until all migrations have been applied.
+Requires a .toml config file:
+
+  [database]
+  name = "vulcanize_public"
+  hostname = "localhost"
+  port = 5432
+`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"up", "down", "status", "version"},
+	Run: func(cmd *cobra.Command, args []string) {
+		migrate(args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.Flags().IntVar(&migrateSteps, "steps", 1, "number of migrations rolled back by down")
+	migrateCmd.Flags().StringVar(&migratePlugin, "plugin", "", "only migrate the named plugin, or the core schema when set to vulcanizedb")
+}
+
+func migrate(command string) {
+	db, err := sqlx.Connect("postgres", config.DbConnectionString(databaseConfig))
+	if err != nil {
+		log.Fatal("Error connecting to postgres: ", err)
+	}
+	defer db.Close()
+
+	for _, source := range migrationSources(command) {
+		migrator, err := migrations.NewMigrator(db, source)
+		if err != nil {
+			log.Fatalf("Error loading %s migrations: %v", source.Name, err)
+		}
+		switch command {
+		case "up":
+			applied, err := migrator.Up()
+			if err != nil {
+				log.Fatalf("Error migrating %s: %v", source.Name, err)
+			}
+			log.Printf("%s: applied %d migrations\n", source.Name, applied)
+		case "down":
+			rolledBack, err := migrator.Down(migrateSteps)
+			if err != nil {
+				log.Fatalf("Error rolling back %s: %v", source.Name, err)
+			}
+			log.Printf("%s: rolled back %d migrations\n", source.Name, rolledBack)
+		case "status":
+			printMigrationStatus(source, migrator)
+		case "version":
+			version, dirty, err := migrator.Version()
+			if err != nil {
+				log.Fatalf("Error reading %s version: %v", source.Name, err)
+			}
+			fmt.Printf("%s: %d (latest %d)%s\n", source.Name, version, migrator.LatestVersion(), dirtySuffix(dirty))
+		default:
+			log.Fatalf("Unknown migrate command %q, expected up, down, status or version", command)
+		}
+	}
+}
+
+// migrationSources returns the sources selected by --plugin. Unless a plugin is
+// named, down only rolls back the core schema.
+func migrationSources(command string) []migrations.Source {
+	if command == "down" && migratePlugin == "" {
+		return []migrations.Source{migrations.Core}
+	}
+	var sources []migrations.Source
+	for _, source := range migrations.Sources() {
+		if migratePlugin == "" || source.Name == migratePlugin {
+			sources = append(sources, source)
+		}
+	}
+	if len(sources) == 0 {
+		log.Fatalf("No migrations registered for plugin %q", migratePlugin)
+	}
+	return sources
+}
+
+func printMigrationStatus(source migrations.Source, migrator *migrations.Migrator) {
+	statuses, err := migrator.Status()
+	if err != nil {
+		log.Fatalf("Error reading %s status: %v", source.Name, err)
+	}
+	fmt.Printf("%s:\n", source.Name)
+	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	for _, status := range statuses {
+		state := "pending"
+		if status.Applied {
+			state = "applied"
+		}
+		fmt.Fprintf(writer, "  %d\t%s\t%s\n", status.Version, status.Name, state)
+	}
+	writer.Flush()
+}
+
+func dirtySuffix(dirty bool) string {
+	if dirty {
+		return ", dirty"
+	}
+	return ""
+}
@@ -19,12 +19,19 @@ package cmd
 import (
 	"fmt"
 	"log"
-	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"github.com/vulcanize/vulcanizedb/libraries/shared"
+	"github.com/vulcanize/vulcanizedb/pkg/config"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/pkg/history"
 	"github.com/vulcanize/vulcanizedb/pkg/omni/full/transformer"
+	"github.com/vulcanize/vulcanizedb/pkg/omni/shared/parser"
+	"github.com/vulcanize/vulcanizedb/pkg/omni/shared/types"
 	"github.com/vulcanize/vulcanizedb/utils"
 )
 
@@ -45,6 +52,35 @@ Requires a .toml config file:
 
   [client]
   ipcPath = "/Users/user/Library/Ethereum/geth.ipc"
+
+Contract ABIs are read from --abi-file, --abi-dir or the contract_abis table,
+falling back to etherscan unless --abi-etherscan=false is passed.
+
+Instead of the contract flags, each contract can be configured in its own
+[[contract]] section; omitted fields take the same defaults as the flags:
+
+  [[contract]]
+  address = "0x8dd5fbCe2F6a956C3022bA3663759011Dd51e73E"
+  abiFile = "abis/tusd.json"
+  events = ["Transfer", "Approval"]
+  methods = ["balanceOf", "allowance"]
+  methodFilterAddresses = ["0x8dd5fbCe2F6a956C3022bA3663759011Dd51e73E"]
+  startingBlock = 5197514
+
+Methods taking a single address are polled for the token holders seen in events;
+other methods are polled with argument sets given directly or as fields of one
+event, whose distinct logged values are each polled with:
+
+  [[contract.methodArgs]]
+  method = "allowance"
+  args = [["0x8dd5fbCe2F6a956C3022bA3663759011Dd51e73E", "0x3bc6fE8B2E43e3CFdd8AE6c8E1A1AB1Ec6C7E0f2"]]
+  eventFields = ["Approval.owner", "Approval.spender"]
+
+  [[contract]]
+  address = "0x89d24A6b4CcB1B6fAA2625fE562bDD9a23260359"
+  network = "kovan"
+  eventFilterAddresses = ["0x3bc6fE8B2E43e3CFdd8AE6c8E1A1AB1Ec6C7E0f2"]
+  endingBlock = 6000000
 `,
 	Run: func(cmd *cobra.Command, args []string) {
 		omniWatcher()
@@ -52,25 +88,13 @@ Requires a .toml config file:
 }
 
 func omniWatcher() {
-	if contractAddress == "" && len(contractAddresses) == 0 {
-		log.Fatal("Contract address required")
-	}
-
-	ticker := time.NewTicker(5 * time.Second)
-	defer ticker.Stop()
+	contracts := loadContracts()
 
 	blockChain := getBlockChain()
 	db := utils.LoadPostgres(databaseConfig, blockChain.Node())
 	t := transformer.NewTransformer(network, blockChain, &db)
-
-	contractAddresses = append(contractAddresses, contractAddress)
-	for _, addr := range contractAddresses {
-		t.SetEvents(addr, contractEvents)
-		t.SetMethods(addr, contractMethods)
-		t.SetEventAddrs(addr, eventAddrs)
-		t.SetMethodAddrs(addr, methodAddrs)
-		t.SetRange(addr, [2]int64{startingBlockNumber, endingBlockNumber})
-	}
+	t.Parser = newAbiParser(&db, contracts)
+	watchContracts(t, contracts)
 
 	err := t.Init()
 	if err != nil {
@@ -80,11 +104,107 @@ func omniWatcher() {
 	w := shared.Watcher{}
 	w.AddTransformer(t)
 
-	for range ticker.C {
+	for range history.NewHeads(blockChain, watcherPollingInterval) {
 		w.Execute()
 	}
 }
 
+type contractWatcher interface {
+	SetEvents(contractAddr string, filterSet []string)
+	SetEventAddrs(contractAddr string, filterSet []string)
+	SetMethods(contractAddr string, filterSet []string)
+	SetMethodAddrs(contractAddr string, filterSet []string)
+	SetMethodArgs(contractAddr string, methodArgs map[string]types.MethodArgs)
+	SetRange(contractAddr string, rng [2]int64)
+}
+
+// loadContracts reads the [[contract]] sections of the config, or builds them from the
+// contract flags when there are none
+func loadContracts() []config.Contract {
+	var contracts []config.Contract
+	err := viper.UnmarshalKey("contract", &contracts)
+	if err != nil {
+		log.Fatal("Invalid contract config: ", err)
+	}
+	flagAddresses := contractAddresses
+	if contractAddress != "" {
+		flagAddresses = append(flagAddresses, contractAddress)
+	}
+	if len(contracts) > 0 && len(flagAddresses) > 0 {
+		log.Fatal("Contract addresses given by both flags and [[contract]] config")
+	}
+	for _, addr := range flagAddresses {
+		contracts = append(contracts, config.Contract{
+			Address:               addr,
+			AbiNetwork:            network,
+			Events:                contractEvents,
+			Methods:               contractMethods,
+			EventFilterAddresses:  eventAddrs,
+			MethodFilterAddresses: methodAddrs,
+			StartingBlock:         startingBlockNumber,
+			EndingBlock:           endingBlockNumber,
+		})
+	}
+	if len(contracts) == 0 {
+		log.Fatal("Contract address required")
+	}
+	err = config.ValidateContracts(contracts)
+	if err != nil {
+		log.Fatal("Invalid contract config: ", err)
+	}
+	return contracts
+}
+
+func watchContracts(t contractWatcher, contracts []config.Contract) {
+	for _, contract := range contracts {
+		t.SetEvents(contract.Address, contract.WatchedEvents())
+		t.SetMethods(contract.Address, contract.Methods)
+		t.SetEventAddrs(contract.Address, contract.EventFilterAddresses)
+		t.SetMethodAddrs(contract.Address, contract.MethodFilterAddresses)
+		methodArgs := map[string]types.MethodArgs{}
+		for _, args := range contract.MethodArgs {
+			methodArgs[args.Method] = types.MethodArgs{Sets: args.Args, EventFields: args.EventFields}
+		}
+		t.SetMethodArgs(contract.Address, methodArgs)
+		t.SetRange(contract.Address, contract.Range())
+	}
+}
+
+// newAbiParser uses a contract's configured abi or abiFile, otherwise looks up ABIs in
+// --abi-file, --abi-dir, the contract_abis table and the known contracts, then on etherscan
+// for the contract's ABI network unless disabled
+func newAbiParser(db *postgres.DB, contracts []config.Contract) parser.Parser {
+	abiRepository := repositories.NewAbiRepository(db)
+	contractProviders := make(map[string]parser.AbiProvider)
+	for _, contract := range contracts {
+		switch {
+		case contract.Abi != "":
+			contractProviders[contract.Address] = parser.NewStaticAbiProvider(contract.Abi)
+		case contract.AbiFile != "":
+			contractProviders[contract.Address] = parser.NewFileAbiProvider(contract.AbiFile)
+		case contract.AbiNetwork != "" && contract.AbiNetwork != network:
+			contractProviders[contract.Address] = newAbiProvider(abiRepository, contract.AbiNetwork)
+		}
+	}
+	provider := parser.NewContractAbiProviders(contractProviders, newAbiProvider(abiRepository, network))
+	return parser.NewParserWithProvider(provider)
+}
+
+func newAbiProvider(abiRepository datastore.AbiRepository, network string) parser.AbiProvider {
+	var providers []parser.AbiProvider
+	if abiFile != "" {
+		providers = append(providers, parser.NewFileAbiProvider(abiFile))
+	}
+	if abiDir != "" {
+		providers = append(providers, parser.NewDirectoryAbiProvider(abiDir))
+	}
+	providers = append(providers, abiRepository, parser.NewKnownAbiProvider())
+	if abiEtherscan {
+		providers = append(providers, parser.NewCachingAbiProvider(abiRepository, parser.NewEtherscanAbiProvider(network), "etherscan"))
+	}
+	return parser.NewAbiProviders(providers...)
+}
+
 func init() {
 	rootCmd.AddCommand(omniWatcherCmd)
 
@@ -94,6 +214,9 @@ func init() {
 	omniWatcherCmd.Flags().StringArrayVarP(&contractMethods, "contract-methods", "m", nil, "Subset of methods to poll; by default no methods are polled")
 	omniWatcherCmd.Flags().StringArrayVarP(&eventAddrs, "event-filter-addresses", "f", []string{}, "Account addresses to persist event data for; default is to persist for all found token holder addresses")
 	omniWatcherCmd.Flags().StringArrayVarP(&methodAddrs, "method-filter-addresses", "g", []string{}, "Account addresses to poll methods with; default is to poll with all found token holder addresses")
+	omniWatcherCmd.Flags().StringVar(&abiFile, "abi-file", "", "ABI file used for every watched contract")
+	omniWatcherCmd.Flags().StringVar(&abiDir, "abi-dir", "", "Directory of ABI files named by contract address, e.g. 0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359.json")
+	omniWatcherCmd.Flags().BoolVar(&abiEtherscan, "abi-etherscan", true, "Fetch ABIs not found locally from etherscan, caching them in the contract_abis table")
 	omniWatcherCmd.Flags().StringVarP(&network, "network", "n", "", `Network the contract is deployed on; options: "ropsten", "kovan", and "rinkeby"; default is mainnet"`)
 	omniWatcherCmd.Flags().Int64VarP(&startingBlockNumber, "starting-block-number", "s", 0, "Block to begin watching- default is first block the contract exists")
 	omniWatcherCmd.Flags().Int64VarP(&endingBlockNumber, "ending-block-number", "d", -1, "Block to end watching- default is most recent block")
@@ -0,0 +1,97 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/vulcanize/vulcanizedb/pkg/config"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/utils"
+)
+
+var pruneHeaders bool
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Deletes raw chain data older than the retention policy",
+	Long: `Deletes blocks, with their transactions, receipts and logs, more than
+retention.keepBlocks blocks behind the highest stored block, along with rows
+transformers derived from them. With --headers, deletes headers instead.
+
+./vulcanizedb prune --retention-keepBlocks 100000 --config public.toml
+
+Set retention.keepReferenced to keep old blocks and headers whose logs are
+referenced by transformer output. Partitions emptied by pruning are dropped.
+
+sync and lightSync prune on the same policy while running when keepBlocks is set:
+
+  [retention]
+  keepBlocks = 100000
+  keepReferenced = true
+  interval = "10m"
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		prune()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pruneCmd)
+	pruneCmd.Flags().BoolVar(&pruneHeaders, "headers", false, "prune headers synced by lightSync instead of blocks")
+}
+
+func prune() {
+	if !retentionConfig.Enabled() {
+		log.Fatal("retention.keepBlocks must be set to prune")
+	}
+	db := utils.LoadPostgresWithoutNode(databaseConfig)
+	pruneRepository := repositories.NewPruneRepository(&db)
+	pruneTable, table := pruneRepository.PruneBlocks, "blocks"
+	if pruneHeaders {
+		pruneTable, table = pruneRepository.PruneHeaders, "headers"
+	}
+	pruned, err := pruneTable(retentionConfig.KeepBlocks, retentionConfig.KeepReferenced)
+	if err != nil {
+		log.Fatalf("Error pruning %s: %v", table, err)
+	}
+	log.Printf("Pruned %d %s\n", pruned, table)
+}
+
+type pruneFunc func(keepBlocks int64, keepReferenced bool) (int64, error)
+
+// retainRecent prunes on the retention policy every interval until the process exits
+func retainRecent(retention config.Retention, pruneTable pruneFunc, table string) {
+	if !retention.Enabled() {
+		return
+	}
+	ticker := time.NewTicker(retention.PruneInterval())
+	defer ticker.Stop()
+	for range ticker.C {
+		pruned, err := pruneTable(retention.KeepBlocks, retention.KeepReferenced)
+		if err != nil {
+			log.Printf("Error pruning %s: %v\n", table, err)
+			continue
+		}
+		if pruned > 0 {
+			log.Printf("Pruned %d %s\n", pruned, table)
+		}
+	}
+}
@@ -22,26 +22,34 @@ import (
 	"os"
 
 	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/mitchellh/go-homedir"
+	"github.com/spf13/cast"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	"github.com/vulcanize/vulcanizedb/pkg/config"
+	"github.com/vulcanize/vulcanizedb/pkg/core"
 	"github.com/vulcanize/vulcanizedb/pkg/geth"
 	"github.com/vulcanize/vulcanizedb/pkg/geth/client"
 	vRpc "github.com/vulcanize/vulcanizedb/pkg/geth/converters/rpc"
 	"github.com/vulcanize/vulcanizedb/pkg/geth/node"
+	"github.com/vulcanize/vulcanizedb/pkg/history"
 )
 
 var (
+	backFillConfig      history.BackFillConfig
 	cfgFile             string
+	clientConfig        config.Client
 	databaseConfig      config.Database
-	ipc                 string
 	levelDbPath         string
+	ancientPath         string
 	startingBlockNumber int64
 	syncAll             bool
 	endingBlockNumber   int64
+	finalityConfig      config.Finality
+	retentionConfig     config.Retention
+	chainConfig         config.Chain
+	finalOnly           bool
 	network             string
 	contractAddress     string
 	contractAddresses   []string
@@ -49,6 +57,9 @@ var (
 	contractMethods     []string
 	eventAddrs          []string
 	methodAddrs         []string
+	abiFile             string
+	abiDir              string
+	abiEtherscan        bool
 )
 
 var rootCmd = &cobra.Command{
@@ -64,8 +75,19 @@ func Execute() {
 }
 
 func database(cmd *cobra.Command, args []string) {
-	ipc = viper.GetString("client.ipcpath")
+	clientConfig = config.Client{
+		IPCPath:    viper.GetString("client.ipcpath"),
+		RPCURL:     viper.GetString("client.rpcurl"),
+		Headers:    viper.GetStringMapString("client.headers"),
+		NodeType:   viper.GetString("client.nodetype"),
+		RateLimit:  viper.GetFloat64("client.ratelimit"),
+		MaxHeadLag: viper.GetInt64("client.maxheadlag"),
+	}
+	if err := viper.UnmarshalKey("client.fallbacks", &clientConfig.Fallbacks); err != nil {
+		log.Fatal("Invalid client fallbacks: ", err)
+	}
 	levelDbPath = viper.GetString("client.leveldbpath")
+	ancientPath = viper.GetString("client.ancientpath")
 	databaseConfig = config.Database{
 		Name:     viper.GetString("database.name"),
 		Hostname: viper.GetString("database.hostname"),
@@ -74,6 +96,23 @@ func database(cmd *cobra.Command, args []string) {
 		Password: viper.GetString("database.password"),
 	}
 	viper.Set("database.config", databaseConfig)
+	finalityDepth := viper.GetInt64("finality.depth")
+	finalityConfig = config.Finality{
+		Depth:    &finalityDepth,
+		Networks: finalityNetworkDepths(),
+	}
+	retentionConfig = config.Retention{
+		KeepBlocks:     viper.GetInt64("retention.keepBlocks"),
+		KeepReferenced: viper.GetBool("retention.keepReferenced"),
+		Interval:       viper.GetDuration("retention.interval"),
+	}
+	chainConfig = loadChainConfig()
+	backFillConfig = history.BackFillConfig{
+		Workers:    viper.GetInt("backfill.workers"),
+		BatchSize:  viper.GetInt("backfill.batchSize"),
+		MaxRetries: viper.GetInt("backfill.maxRetries"),
+		RetryDelay: viper.GetDuration("backfill.retryDelay"),
+	}
 }
 
 func init() {
@@ -86,7 +125,20 @@ func init() {
 	rootCmd.PersistentFlags().String("database-user", "", "database user")
 	rootCmd.PersistentFlags().String("database-password", "", "database password")
 	rootCmd.PersistentFlags().String("client-ipcPath", "", "location of geth.ipc file")
+	rootCmd.PersistentFlags().String("client-rpcUrl", "", "http(s):// or ws(s):// rpc endpoint, used instead of client-ipcPath when set")
+	rootCmd.PersistentFlags().String("client-nodeType", "", "type of node: geth, parity, infura or ganache (detected when empty)")
+	rootCmd.PersistentFlags().Float64("client-rateLimit", 0, "maximum number of requests per second sent to the node, unlimited when 0")
+	rootCmd.PersistentFlags().Int64("client-maxHeadLag", config.DefaultMaxHeadLag, "number of blocks a node may lag behind its fallbacks before failing over")
 	rootCmd.PersistentFlags().String("client-levelDbPath", "", "location of levelDb chaindata")
+	rootCmd.PersistentFlags().String("client-ancientPath", "", "location of geth's ancient store, defaults to chaindata/ancient")
+	rootCmd.PersistentFlags().Int64("finality-depth", config.DefaultFinalityDepth, "number of blocks behind the chain head after which data is considered final")
+	rootCmd.PersistentFlags().Int64("retention-keepBlocks", 0, "number of most recent blocks or headers kept when pruning, nothing is pruned when 0")
+	rootCmd.PersistentFlags().Bool("retention-keepReferenced", false, "keep old blocks and headers referenced by transformer output when pruning")
+	rootCmd.PersistentFlags().Duration("retention-interval", config.DefaultRetentionInterval, "delay between prunes while syncing")
+	rootCmd.PersistentFlags().Int("backfill-workers", history.DefaultBackFillConfig.Workers, "number of concurrent requests made while backfilling")
+	rootCmd.PersistentFlags().Int("backfill-batchSize", history.DefaultBackFillConfig.BatchSize, "number of blocks retrieved before each batch is persisted")
+	rootCmd.PersistentFlags().Int("backfill-maxRetries", history.DefaultBackFillConfig.MaxRetries, "number of times a failed block retrieval is retried")
+	rootCmd.PersistentFlags().Duration("backfill-retryDelay", history.DefaultBackFillConfig.RetryDelay, "delay before the first retry, doubled on each subsequent retry")
 
 	viper.BindPFlag("database.name", rootCmd.PersistentFlags().Lookup("database-name"))
 	viper.BindPFlag("database.port", rootCmd.PersistentFlags().Lookup("database-port"))
@@ -94,7 +146,44 @@ func init() {
 	viper.BindPFlag("database.user", rootCmd.PersistentFlags().Lookup("database-user"))
 	viper.BindPFlag("database.password", rootCmd.PersistentFlags().Lookup("database-password"))
 	viper.BindPFlag("client.ipcPath", rootCmd.PersistentFlags().Lookup("client-ipcPath"))
+	viper.BindPFlag("client.rpcUrl", rootCmd.PersistentFlags().Lookup("client-rpcUrl"))
+	viper.BindPFlag("client.nodeType", rootCmd.PersistentFlags().Lookup("client-nodeType"))
+	viper.BindPFlag("client.rateLimit", rootCmd.PersistentFlags().Lookup("client-rateLimit"))
+	viper.BindPFlag("client.maxHeadLag", rootCmd.PersistentFlags().Lookup("client-maxHeadLag"))
 	viper.BindPFlag("client.levelDbPath", rootCmd.PersistentFlags().Lookup("client-levelDbPath"))
+	viper.BindPFlag("client.ancientPath", rootCmd.PersistentFlags().Lookup("client-ancientPath"))
+	viper.BindPFlag("finality.depth", rootCmd.PersistentFlags().Lookup("finality-depth"))
+	viper.BindPFlag("retention.keepBlocks", rootCmd.PersistentFlags().Lookup("retention-keepBlocks"))
+	viper.BindPFlag("retention.keepReferenced", rootCmd.PersistentFlags().Lookup("retention-keepReferenced"))
+	viper.BindPFlag("retention.interval", rootCmd.PersistentFlags().Lookup("retention-interval"))
+	viper.BindPFlag("backfill.workers", rootCmd.PersistentFlags().Lookup("backfill-workers"))
+	viper.BindPFlag("backfill.batchSize", rootCmd.PersistentFlags().Lookup("backfill-batchSize"))
+	viper.BindPFlag("backfill.maxRetries", rootCmd.PersistentFlags().Lookup("backfill-maxRetries"))
+	viper.BindPFlag("backfill.retryDelay", rootCmd.PersistentFlags().Lookup("backfill-retryDelay"))
+}
+
+func finalityNetworkDepths() map[string]int64 {
+	depths := make(map[string]int64)
+	for networkID, depth := range viper.GetStringMap("finality.networks") {
+		depths[networkID] = cast.ToInt64(depth)
+	}
+	return depths
+}
+
+func loadChainConfig() config.Chain {
+	rewards, err := config.ParseRewardSchedule(viper.GetStringMapString("chain.rewards"))
+	if err != nil {
+		log.Fatal("Invalid chain rewards: ", err)
+	}
+	networks := make(map[string]core.RewardSchedule)
+	for networkID, networkRewards := range viper.GetStringMap("chain.networks") {
+		schedule, err := config.ParseRewardSchedule(cast.ToStringMapString(networkRewards))
+		if err != nil {
+			log.Fatalf("Invalid chain rewards for network %s: %v", networkID, err)
+		}
+		networks[networkID] = schedule
+	}
+	return config.Chain{Rewards: rewards, Networks: networks}
 }
 
 func initConfig() {
@@ -119,15 +208,67 @@ func initConfig() {
 }
 
 func getBlockChain() *geth.BlockChain {
-	rawRpcClient, err := rpc.Dial(ipc)
+	var endpoints []client.Endpoint
+	for _, endpointConfig := range append([]config.Client{clientConfig}, clientConfig.Fallbacks...) {
+		endpoint, err := dialEndpoint(endpointConfig)
+		if err != nil {
+			log.Printf("Error connecting to %s: %v\n", endpointConfig.Endpoint(), err)
+			continue
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	if len(endpoints) == 0 {
+		log.Fatal("Unable to connect to any ethereum node")
+	}
+	vdbNode := makeNode(endpoints[0].RpcClient)
+	endpoints = append(endpoints[:1], fallbacksOnChain(endpoints[1:], vdbNode)...)
+	failoverClient := client.NewFailoverClient(endpoints, clientConfig.MaxHeadLag)
+	failoverClient.MonitorHealth(healthCheckInterval)
+	networkChainConfig := core.ChainConfigForNetwork(vdbNode.NetworkID)
+	if networkChainConfig == nil {
+		log.Printf("Unknown network %v, transaction senders will be fetched from the node\n", vdbNode.NetworkID)
+	}
+	transactionConverter := vRpc.NewRpcTransactionConverter(failoverClient, networkChainConfig)
+	blockChain := geth.NewBlockChain(failoverClient, failoverClient, vdbNode, transactionConverter)
+	blockChain.SetRewardSchedule(chainConfig.RewardScheduleForNetwork(vdbNode.NetworkID))
+	return blockChain
+}
 
+// fallbacksOnChain drops fallbacks serving a different chain than the first endpoint. Data
+// fetched from any endpoint is stored under the first endpoint's node fingerprint.
+func fallbacksOnChain(fallbacks []client.Endpoint, vdbNode core.Node) []client.Endpoint {
+	var onChain []client.Endpoint
+	for _, fallback := range fallbacks {
+		err := node.CheckChain(fallback.RpcClient, vdbNode)
+		if err != nil {
+			log.Printf("Not using %s as a fallback: %v\n", fallback.Name, err)
+			continue
+		}
+		onChain = append(onChain, fallback)
+	}
+	return onChain
+}
+
+func dialEndpoint(endpointConfig config.Client) (client.Endpoint, error) {
+	rawRpcClient, err := client.Dial(endpointConfig.Endpoint(), endpointConfig.Headers)
+	if err != nil {
+		return client.Endpoint{}, err
+	}
+	return client.Endpoint{
+		Name:      endpointConfig.Endpoint(),
+		EthClient: client.NewEthClient(ethclient.NewClient(rawRpcClient)),
+		RpcClient: client.NewRpcClient(rawRpcClient, endpointConfig.Endpoint()),
+		RateLimit: endpointConfig.RateLimit,
+	}, nil
+}
+
+func makeNode(rpcClient core.RpcClient) core.Node {
+	if clientConfig.NodeType == "" {
+		return node.MakeNode(rpcClient)
+	}
+	nodeType, err := core.ParseNodeType(clientConfig.NodeType)
 	if err != nil {
 		log.Fatal(err)
 	}
-	rpcClient := client.NewRpcClient(rawRpcClient, ipc)
-	ethClient := ethclient.NewClient(rawRpcClient)
-	vdbEthClient := client.NewEthClient(ethClient)
-	vdbNode := node.MakeNode(rpcClient)
-	transactionConverter := vRpc.NewRpcTransactionConverter(ethClient)
-	return geth.NewBlockChain(vdbEthClient, rpcClient, vdbNode, transactionConverter)
+	return node.MakeNodeOfType(rpcClient, nodeType)
 }
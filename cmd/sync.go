@@ -25,6 +25,7 @@ import (
 
 	"github.com/vulcanize/vulcanizedb/pkg/core"
 	"github.com/vulcanize/vulcanizedb/pkg/datastore"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
 	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
 	"github.com/vulcanize/vulcanizedb/pkg/history"
 	"github.com/vulcanize/vulcanizedb/utils"
@@ -48,6 +49,26 @@ Expects ethereum node to be running and requires a .toml config:
 
   [client]
   ipcPath = "/Users/user/Library/Ethereum/geth.ipc"
+
+Backfilling can be tuned with an optional section:
+
+  [backfill]
+  workers = 8
+  batchSize = 100
+  maxRetries = 3
+  retryDelay = "1s"
+
+Blocks are marked final once they are 20 blocks behind the chain head by default.
+The depth can be configured, including per network id:
+
+  [finality]
+  depth = 20
+
+  [finality.networks]
+  42 = 5
+
+Blocks more than retention.keepBlocks behind the chain head are pruned
+periodically and not backfilled, see prune.
 `,
 	Run: func(cmd *cobra.Command, args []string) {
 		sync()
@@ -55,8 +76,10 @@ Expects ethereum node to be running and requires a .toml config:
 }
 
 const (
-	pollingInterval  = 7 * time.Second
-	validationWindow = 15
+	pollingInterval        = 7 * time.Second
+	watcherPollingInterval = 5 * time.Second
+	healthCheckInterval    = 15 * time.Second
+	validationWindow       = 15
 )
 
 func init() {
@@ -65,14 +88,20 @@ func init() {
 	syncCmd.Flags().Int64VarP(&startingBlockNumber, "starting-block-number", "s", 0, "Block number to start syncing from")
 }
 
-func backFillAllBlocks(blockchain core.BlockChain, blockRepository datastore.BlockRepository, missingBlocksPopulated chan int, startingBlockNumber int64) {
-	missingBlocksPopulated <- history.PopulateMissingBlocks(blockchain, blockRepository, startingBlockNumber)
+func backFillAllBlocks(blockchain core.BlockChain, blockRepository datastore.BlockRepository, blockWriter datastore.BlockWriter, missingBlocksPopulated chan int, startingBlockNumber int64) {
+	_, err := history.RefreshFlaggedBlocks(blockchain, blockRepository, backFillConfig)
+	if err != nil {
+		log.Println("Error refreshing blocks: ", err)
+	}
+	startingBlockNumber = retentionConfig.StartingBlockNumber(startingBlockNumber, blockchain.LastBlock().Int64())
+	populated, err := history.PopulateMissingBlocks(blockchain, blockRepository, blockWriter, startingBlockNumber, backFillConfig)
+	if err != nil {
+		log.Println("Error populating blocks: ", err)
+	}
+	missingBlocksPopulated <- populated
 }
 
 func sync() {
-	ticker := time.NewTicker(pollingInterval)
-	defer ticker.Stop()
-
 	blockChain := getBlockChain()
 
 	lastBlock := blockChain.LastBlock().Int64()
@@ -85,17 +114,21 @@ func sync() {
 
 	db := utils.LoadPostgres(databaseConfig, blockChain.Node())
 	blockRepository := repositories.NewBlockRepository(&db)
-	validator := history.NewBlockValidator(blockChain, blockRepository, validationWindow)
+	blockWriter := postgres.NewBulkBlockWriter(&db)
+	reorgRepository := repositories.NewReorgRepository(&db)
+	validator := history.NewBlockValidator(blockChain, blockRepository, reorgRepository, validationWindow, finalityConfig.DepthForNetwork(blockChain.Node().NetworkID))
 	missingBlocksPopulated := make(chan int)
-	go backFillAllBlocks(blockChain, blockRepository, missingBlocksPopulated, startingBlockNumber)
+	newHeads := history.NewHeads(blockChain, pollingInterval)
+	go backFillAllBlocks(blockChain, blockRepository, blockWriter, missingBlocksPopulated, startingBlockNumber)
+	go retainRecent(retentionConfig, repositories.NewPruneRepository(&db).PruneBlocks, "blocks")
 
 	for {
 		select {
-		case <-ticker.C:
+		case <-newHeads:
 			window := validator.ValidateBlocks()
 			window.Log(os.Stdout)
 		case <-missingBlocksPopulated:
-			go backFillAllBlocks(blockChain, blockRepository, missingBlocksPopulated, startingBlockNumber)
+			go backFillAllBlocks(blockChain, blockRepository, blockWriter, missingBlocksPopulated, startingBlockNumber)
 		}
 	}
 }
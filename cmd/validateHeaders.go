@@ -0,0 +1,77 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/pkg/history"
+	"github.com/vulcanize/vulcanizedb/utils"
+)
+
+var validateHeadersCmd = &cobra.Command{
+	Use:   "validateHeaders",
+	Short: "Validates that stored block headers form a chain",
+	Long: `Walks the stored block headers from the chain head down through their
+parent hashes. Missing headers are fetched from the node and headers that are
+not ancestors of the head are replaced.
+
+./vulcanizedb validateHeaders --starting-block-number 0 --config public.toml
+
+Expects ethereum node to be running and requires a .toml config:
+
+  [database]
+  name = "vulcanize_public"
+  hostname = "localhost"
+  port = 5432
+
+  [client]
+  ipcPath = "/Users/user/Library/Ethereum/geth.ipc"
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		validateHeaders()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(validateHeadersCmd)
+	validateHeadersCmd.Flags().Int64VarP(&startingBlockNumber, "starting-block-number", "s", 0, "Block number to start validating from")
+	validateHeadersCmd.Flags().Int64VarP(&endingBlockNumber, "ending-block-number", "e", -1, "Block number to validate to, defaults to the chain head")
+}
+
+func validateHeaders() {
+	blockChain := getBlockChain()
+	validateArgs(blockChain)
+	if endingBlockNumber == -1 {
+		endingBlockNumber = blockChain.LastBlock().Int64()
+	}
+	if endingBlockNumber < startingBlockNumber {
+		log.Fatal("Ending block number must be greater than starting block number.")
+	}
+	db := utils.LoadPostgres(databaseConfig, blockChain.Node())
+	headerRepository := repositories.NewHeaderRepository(&db)
+	chainValidator := history.NewHeaderChainValidator(blockChain, headerRepository)
+
+	report, err := chainValidator.ValidateHeaderChain(startingBlockNumber, endingBlockNumber)
+	if err != nil {
+		log.Fatal("Error validating header chain: ", err)
+	}
+	report.Log(os.Stdout)
+}
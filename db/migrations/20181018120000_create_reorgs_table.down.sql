@@ -0,0 +1 @@
+DROP TABLE public.reorgs;
\ No newline at end of file
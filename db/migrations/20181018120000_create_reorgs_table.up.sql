@@ -0,0 +1,14 @@
+CREATE TABLE public.reorgs (
+  id                    SERIAL PRIMARY KEY,
+  block_number          BIGINT NOT NULL,
+  depth                 BIGINT NOT NULL,
+  old_hash              VARCHAR(66) NOT NULL,
+  new_hash              VARCHAR(66) NOT NULL,
+  eth_node_id           INTEGER NOT NULL,
+  eth_node_fingerprint  VARCHAR(128) NOT NULL,
+  CONSTRAINT eth_nodes_fk FOREIGN KEY (eth_node_id)
+  REFERENCES eth_nodes (id)
+  ON DELETE CASCADE
+);
+
+CREATE INDEX reorgs_block_number_index ON public.reorgs USING btree (block_number);
@@ -0,0 +1,2 @@
+ALTER TABLE public.headers
+  DROP COLUMN is_final;
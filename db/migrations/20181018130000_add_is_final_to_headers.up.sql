@@ -0,0 +1,2 @@
+ALTER TABLE public.headers
+  ADD COLUMN is_final BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,9 @@
+ALTER TABLE public.receipts
+  DROP COLUMN bloom;
+
+ALTER TABLE public.logs
+  DROP COLUMN block_hash,
+  DROP COLUMN removed;
+
+ALTER TABLE public.transactions
+  DROP COLUMN tx_index;
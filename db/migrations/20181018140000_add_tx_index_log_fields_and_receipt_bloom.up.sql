@@ -0,0 +1,9 @@
+ALTER TABLE public.transactions
+  ADD COLUMN tx_index INTEGER;
+
+ALTER TABLE public.logs
+  ADD COLUMN block_hash VARCHAR(66),
+  ADD COLUMN removed BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE public.receipts
+  ADD COLUMN bloom VARCHAR(514);
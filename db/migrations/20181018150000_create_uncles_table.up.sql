@@ -0,0 +1,14 @@
+CREATE TABLE public.uncles (
+  id        SERIAL PRIMARY KEY,
+  block_id  INTEGER NOT NULL,
+  hash      VARCHAR(66) NOT NULL,
+  number    BIGINT NOT NULL,
+  miner     VARCHAR(42) NOT NULL,
+  reward    DOUBLE PRECISION NOT NULL,
+  CONSTRAINT blocks_fk FOREIGN KEY (block_id)
+  REFERENCES blocks (id)
+  ON DELETE CASCADE,
+  UNIQUE (block_id, hash)
+);
+
+CREATE INDEX uncles_miner_index ON public.uncles USING btree (miner);
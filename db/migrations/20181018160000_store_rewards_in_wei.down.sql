@@ -0,0 +1,6 @@
+ALTER TABLE blocks
+  ALTER COLUMN reward TYPE DOUBLE PRECISION USING (reward / 1000000000000000000)::DOUBLE PRECISION,
+  ALTER COLUMN uncles_reward TYPE DOUBLE PRECISION USING (uncles_reward / 1000000000000000000)::DOUBLE PRECISION;
+
+ALTER TABLE uncles
+  ALTER COLUMN reward TYPE DOUBLE PRECISION USING (reward / 1000000000000000000)::DOUBLE PRECISION;
@@ -0,0 +1,6 @@
+ALTER TABLE blocks
+  ALTER COLUMN reward TYPE NUMERIC USING ROUND(reward::NUMERIC * 1000000000000000000),
+  ALTER COLUMN uncles_reward TYPE NUMERIC USING ROUND(uncles_reward::NUMERIC * 1000000000000000000);
+
+ALTER TABLE uncles
+  ALTER COLUMN reward TYPE NUMERIC USING ROUND(reward::NUMERIC * 1000000000000000000);
@@ -0,0 +1,13 @@
+DO $$
+BEGIN
+  IF EXISTS (SELECT 1 FROM blocks WHERE difficulty NOT BETWEEN -9223372036854775808 AND 9223372036854775807) THEN
+    RAISE EXCEPTION 'blocks have difficulties that do not fit in BIGINT, delete them before rolling back';
+  END IF;
+END
+$$;
+
+DROP INDEX blocks_needs_refresh_index;
+ALTER TABLE blocks
+  DROP COLUMN needs_refresh;
+ALTER TABLE blocks
+  ALTER COLUMN difficulty TYPE BIGINT;
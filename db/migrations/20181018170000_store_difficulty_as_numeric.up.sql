@@ -0,0 +1,15 @@
+ALTER TABLE blocks
+  ALTER COLUMN difficulty TYPE NUMERIC;
+ALTER TABLE blocks
+  ADD COLUMN needs_refresh BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Difficulties and gas prices that did not fit in an int64 were stored wrapped around to
+-- negative values, and rewards were calculated from the same wrapped gas prices. These blocks
+-- are flagged so that sync re-fetches them and corrects the values in place. Values that
+-- wrapped around to a positive number cannot be told apart from valid ones and are not flagged.
+UPDATE blocks
+SET needs_refresh = TRUE
+WHERE difficulty < 0
+   OR id IN (SELECT block_id FROM transactions WHERE gasprice < 0);
+
+CREATE INDEX blocks_needs_refresh_index ON blocks (number) WHERE needs_refresh;
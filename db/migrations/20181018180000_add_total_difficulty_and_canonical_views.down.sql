@@ -0,0 +1,11 @@
+DROP VIEW public.canonical_headers;
+DROP VIEW public.canonical_blocks;
+
+DROP INDEX public.headers_hash_index;
+DROP INDEX public.blocks_hash_index;
+
+ALTER TABLE public.headers
+  DROP COLUMN total_difficulty;
+
+ALTER TABLE public.blocks
+  DROP COLUMN total_difficulty;
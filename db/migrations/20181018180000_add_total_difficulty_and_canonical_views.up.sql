@@ -0,0 +1,22 @@
+ALTER TABLE public.blocks
+  ADD COLUMN total_difficulty NUMERIC;
+
+ALTER TABLE public.headers
+  ADD COLUMN total_difficulty NUMERIC;
+
+CREATE INDEX blocks_hash_index ON public.blocks USING btree (hash);
+CREATE INDEX headers_hash_index ON public.headers USING btree (hash);
+
+-- The canonical chain across every ingested node: at each height, the stored block with the
+-- highest total difficulty. Heights are resolved independently, so gaps in the stored chain do
+-- not cut it short, rows stored before total difficulty was recorded are still included, and
+-- filters on the number reach the underlying index.
+CREATE VIEW public.canonical_blocks AS
+  SELECT DISTINCT ON (number) *
+  FROM public.blocks
+  ORDER BY number, total_difficulty DESC NULLS LAST, id;
+
+CREATE VIEW public.canonical_headers AS
+  SELECT DISTINCT ON (block_number) *
+  FROM public.headers
+  ORDER BY block_number, total_difficulty DESC NULLS LAST, id;
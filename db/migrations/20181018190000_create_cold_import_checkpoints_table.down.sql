@@ -0,0 +1 @@
+DROP TABLE public.cold_import_checkpoints;
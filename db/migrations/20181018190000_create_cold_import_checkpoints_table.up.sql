@@ -0,0 +1,7 @@
+CREATE TABLE public.cold_import_checkpoints (
+  eth_node_fingerprint   VARCHAR(128) NOT NULL,
+  starting_block_number  BIGINT NOT NULL,
+  last_block_number      BIGINT NOT NULL,
+  updated_at             TIMESTAMP NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (eth_node_fingerprint, starting_block_number)
+);
@@ -0,0 +1,106 @@
+BEGIN;
+
+DROP VIEW public.watched_event_logs;
+DROP VIEW public.block_stats;
+
+DROP TRIGGER logs_delete_references ON public.logs;
+DROP FUNCTION public.delete_log_references();
+DROP TRIGGER receipts_delete_logs ON public.receipts;
+DROP FUNCTION public.delete_receipt_logs();
+
+CREATE FUNCTION public.unpartition_by_block_number(parent_table TEXT) RETURNS VOID AS $$
+DECLARE
+  partitioned TEXT := parent_table || '_partitioned';
+  id_sequence TEXT := pg_get_serial_sequence('public.' || parent_table, 'id');
+BEGIN
+  EXECUTE format('ALTER TABLE public.%I RENAME TO %I', parent_table, partitioned);
+  EXECUTE format('CREATE TABLE public.%I (LIKE public.%I INCLUDING DEFAULTS)', parent_table, partitioned);
+  EXECUTE format('ALTER SEQUENCE %s OWNED BY public.%I.id', id_sequence, parent_table);
+  EXECUTE format('INSERT INTO public.%I SELECT * FROM public.%I', parent_table, partitioned);
+  EXECUTE format('DROP TABLE public.%I', partitioned);
+  EXECUTE format('ALTER TABLE public.%I ADD PRIMARY KEY (id)', parent_table);
+END
+$$ LANGUAGE plpgsql;
+
+SELECT public.unpartition_by_block_number('transactions');
+SELECT public.unpartition_by_block_number('receipts');
+SELECT public.unpartition_by_block_number('logs');
+DROP FUNCTION public.unpartition_by_block_number(TEXT);
+
+DROP FUNCTION public.ensure_block_partitions(BIGINT, BIGINT);
+DROP FUNCTION public.ensure_block_partition(TEXT, BIGINT);
+DROP FUNCTION public.block_partition_size();
+
+ALTER TABLE public.transactions
+  DROP COLUMN block_number;
+ALTER TABLE public.transactions
+  ADD CONSTRAINT blocks_fk
+FOREIGN KEY (block_id)
+REFERENCES public.blocks (id)
+ON DELETE CASCADE;
+CREATE INDEX block_id_index ON public.transactions (block_id);
+CREATE INDEX tx_to_index ON public.transactions (tx_to);
+CREATE INDEX tx_from_index ON public.transactions (tx_from);
+
+ALTER TABLE public.receipts
+  DROP COLUMN block_number;
+ALTER TABLE public.receipts
+  ADD CONSTRAINT blocks_fk
+FOREIGN KEY (block_id)
+REFERENCES public.blocks (id)
+ON DELETE CASCADE;
+
+ALTER TABLE public.logs
+  ALTER COLUMN block_number DROP NOT NULL;
+ALTER TABLE public.logs
+  ADD CONSTRAINT receipts_fk
+FOREIGN KEY (receipt_id)
+REFERENCES public.receipts (id)
+ON DELETE CASCADE;
+
+DO $$
+DECLARE
+  reference RECORD;
+BEGIN
+  FOR reference IN SELECT table_name, column_name FROM public.log_references LOOP
+    IF to_regclass(reference.table_name) IS NOT NULL THEN
+      EXECUTE format('ALTER TABLE %s ADD CONSTRAINT log_index_fk FOREIGN KEY (%I) REFERENCES public.logs (id) ON DELETE CASCADE',
+        reference.table_name, reference.column_name);
+    END IF;
+  END LOOP;
+END
+$$;
+
+DROP TABLE public.log_references;
+
+CREATE VIEW block_stats AS
+  SELECT
+    max(block_number) AS max_block,
+    min(block_number) AS min_block
+  FROM logs;
+
+CREATE VIEW watched_event_logs AS
+  SELECT
+    log_filters.name,
+    logs.id,
+    block_number,
+    logs.address,
+    tx_hash,
+    index,
+    logs.topic0,
+    logs.topic1,
+    logs.topic2,
+    logs.topic3,
+    data,
+    receipt_id
+  FROM log_filters
+    CROSS JOIN block_stats
+    JOIN logs ON logs.address = log_filters.address
+                 AND logs.block_number >= coalesce(log_filters.from_block, block_stats.min_block)
+                 AND logs.block_number <= coalesce(log_filters.to_block, block_stats.max_block)
+  WHERE (log_filters.topic0 = logs.topic0 OR log_filters.topic0 ISNULL)
+        AND (log_filters.topic1 = logs.topic1 OR log_filters.topic1 ISNULL)
+        AND (log_filters.topic2 = logs.topic2 OR log_filters.topic2 ISNULL)
+        AND (log_filters.topic3 = logs.topic3 OR log_filters.topic3 ISNULL);
+
+COMMIT;
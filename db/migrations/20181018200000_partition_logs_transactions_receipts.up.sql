@@ -0,0 +1,224 @@
+BEGIN;
+
+DROP VIEW public.watched_event_logs;
+DROP VIEW public.block_stats;
+
+-- Partitioned tables cannot be referenced by foreign keys, so tables pointing at logs
+-- are registered here and cleaned up by a trigger when their log is deleted.
+CREATE TABLE public.log_references (
+  table_name  TEXT PRIMARY KEY,
+  column_name TEXT NOT NULL
+);
+
+DO $$
+DECLARE
+  reference RECORD;
+BEGIN
+  FOR reference IN
+    SELECT con.conname, con.conrelid::regclass::text AS table_name, att.attname AS column_name
+    FROM pg_constraint con
+      JOIN pg_attribute att ON att.attrelid = con.conrelid AND att.attnum = con.conkey[1]
+    WHERE con.contype = 'f' AND con.confrelid = 'public.logs'::regclass
+  LOOP
+    INSERT INTO public.log_references (table_name, column_name)
+    VALUES (reference.table_name, reference.column_name)
+    ON CONFLICT DO NOTHING;
+    EXECUTE format('ALTER TABLE %s DROP CONSTRAINT %I', reference.table_name, reference.conname);
+  END LOOP;
+END
+$$;
+
+ALTER TABLE public.logs
+  DROP CONSTRAINT receipts_fk;
+
+-- Rows without a block cannot be placed in a partition and are unreachable anyway.
+ALTER TABLE public.transactions
+  ADD COLUMN block_number BIGINT;
+UPDATE public.transactions
+  SET block_number = blocks.number
+  FROM public.blocks
+  WHERE blocks.id = transactions.block_id;
+DELETE FROM public.transactions WHERE block_number IS NULL;
+ALTER TABLE public.transactions
+  ALTER COLUMN block_number SET NOT NULL;
+
+ALTER TABLE public.receipts
+  ADD COLUMN block_number BIGINT;
+UPDATE public.receipts
+  SET block_number = blocks.number
+  FROM public.blocks
+  WHERE blocks.id = receipts.block_id;
+DELETE FROM public.receipts WHERE block_number IS NULL;
+ALTER TABLE public.receipts
+  ALTER COLUMN block_number SET NOT NULL;
+
+ALTER TABLE public.logs
+  ALTER COLUMN block_number SET NOT NULL;
+
+CREATE FUNCTION public.block_partition_size() RETURNS BIGINT AS $$
+  SELECT 1000000::BIGINT
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- Creates the partition of parent_table holding for_block if it does not exist yet,
+-- moving any rows for its range out of the default partition.
+CREATE FUNCTION public.ensure_block_partition(parent_table TEXT, for_block BIGINT) RETURNS VOID AS $$
+DECLARE
+  range_start    BIGINT := (for_block / public.block_partition_size()) * public.block_partition_size();
+  range_end      BIGINT := range_start + public.block_partition_size();
+  partition_name TEXT   := parent_table || '_' || range_start;
+BEGIN
+  IF for_block < 0 OR to_regclass('public.' || partition_name) IS NOT NULL THEN
+    RETURN;
+  END IF;
+  PERFORM pg_advisory_xact_lock(hashtext(partition_name));
+  IF to_regclass('public.' || partition_name) IS NOT NULL THEN
+    RETURN;
+  END IF;
+  EXECUTE format('CREATE TABLE public.%I (LIKE public.%I INCLUDING DEFAULTS)', partition_name, parent_table);
+  PERFORM set_config('vulcanize.moving_partition_rows', 'on', true);
+  EXECUTE format(
+    'WITH moved AS (DELETE FROM public.%I WHERE block_number >= %s AND block_number < %s RETURNING *) '
+    'INSERT INTO public.%I SELECT * FROM moved',
+    parent_table || '_default', range_start, range_end, partition_name);
+  PERFORM set_config('vulcanize.moving_partition_rows', 'off', true);
+  EXECUTE format('ALTER TABLE public.%I ATTACH PARTITION public.%I FOR VALUES FROM (%s) TO (%s)',
+    parent_table, partition_name, range_start, range_end);
+END
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION public.ensure_block_partitions(starting_block BIGINT, ending_block BIGINT) RETURNS VOID AS $$
+DECLARE
+  parent_table TEXT;
+  range_start  BIGINT;
+BEGIN
+  FOREACH parent_table IN ARRAY ARRAY['transactions', 'receipts', 'logs'] LOOP
+    range_start := (GREATEST(starting_block, 0) / public.block_partition_size()) * public.block_partition_size();
+    WHILE range_start <= ending_block LOOP
+      PERFORM public.ensure_block_partition(parent_table, range_start);
+      range_start := range_start + public.block_partition_size();
+    END LOOP;
+  END LOOP;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION public.partition_by_block_number(parent_table TEXT) RETURNS VOID AS $$
+DECLARE
+  unpartitioned TEXT := parent_table || '_unpartitioned';
+  id_sequence   TEXT := pg_get_serial_sequence('public.' || parent_table, 'id');
+  first_block   BIGINT;
+  last_block    BIGINT;
+BEGIN
+  EXECUTE format('ALTER TABLE public.%I RENAME TO %I', parent_table, unpartitioned);
+  EXECUTE format('CREATE TABLE public.%I (LIKE public.%I INCLUDING DEFAULTS) PARTITION BY RANGE (block_number)',
+    parent_table, unpartitioned);
+  EXECUTE format('ALTER SEQUENCE %s OWNED BY public.%I.id', id_sequence, parent_table);
+  EXECUTE format('CREATE TABLE public.%I PARTITION OF public.%I DEFAULT', parent_table || '_default', parent_table);
+  EXECUTE format('SELECT min(block_number), max(block_number) FROM public.%I', unpartitioned)
+    INTO first_block, last_block;
+  WHILE first_block <= last_block LOOP
+    PERFORM public.ensure_block_partition(parent_table, first_block);
+    first_block := first_block + public.block_partition_size();
+  END LOOP;
+  EXECUTE format('INSERT INTO public.%I SELECT * FROM public.%I', parent_table, unpartitioned);
+  EXECUTE format('DROP TABLE public.%I', unpartitioned);
+  EXECUTE format('ALTER TABLE public.%I ADD PRIMARY KEY (id, block_number)', parent_table);
+END
+$$ LANGUAGE plpgsql;
+
+SELECT public.partition_by_block_number('transactions');
+SELECT public.partition_by_block_number('receipts');
+SELECT public.partition_by_block_number('logs');
+DROP FUNCTION public.partition_by_block_number(TEXT);
+
+ALTER TABLE public.transactions
+  ADD CONSTRAINT blocks_fk
+FOREIGN KEY (block_id)
+REFERENCES public.blocks (id)
+ON DELETE CASCADE;
+CREATE INDEX block_id_index ON public.transactions (block_id);
+CREATE INDEX tx_to_index ON public.transactions (tx_to);
+CREATE INDEX tx_from_index ON public.transactions (tx_from);
+
+ALTER TABLE public.receipts
+  ADD CONSTRAINT blocks_fk
+FOREIGN KEY (block_id)
+REFERENCES public.blocks (id)
+ON DELETE CASCADE;
+CREATE INDEX receipts_block_id_index ON public.receipts (block_id);
+
+CREATE INDEX logs_receipt_id_index ON public.logs (receipt_id);
+CREATE INDEX logs_address_block_number_index ON public.logs (address, block_number);
+CREATE INDEX logs_block_number_index ON public.logs (block_number);
+
+-- Replace the cascades that foreign keys into logs used to provide. The triggers run once per statement
+-- on receipts and logs, so deleting a range of receipts issues one DELETE per referencing table rather
+-- than one per row. Deleting blocks cascades to receipts once per block, so bulk delete paths delete the
+-- blocks' receipts first.
+CREATE FUNCTION public.delete_receipt_logs() RETURNS TRIGGER AS $$
+BEGIN
+  IF current_setting('vulcanize.moving_partition_rows', true) = 'on' THEN
+    RETURN NULL;
+  END IF;
+  DELETE FROM public.logs
+  USING deleted_receipts
+  WHERE logs.receipt_id = deleted_receipts.id AND logs.block_number = deleted_receipts.block_number;
+  RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER receipts_delete_logs
+  AFTER DELETE ON public.receipts
+  REFERENCING OLD TABLE AS deleted_receipts
+  FOR EACH STATEMENT EXECUTE PROCEDURE public.delete_receipt_logs();
+
+CREATE FUNCTION public.delete_log_references() RETURNS TRIGGER AS $$
+DECLARE
+  reference RECORD;
+BEGIN
+  IF current_setting('vulcanize.moving_partition_rows', true) = 'on' THEN
+    RETURN NULL;
+  END IF;
+  FOR reference IN SELECT table_name, column_name FROM public.log_references LOOP
+    IF to_regclass(reference.table_name) IS NOT NULL THEN
+      EXECUTE format('DELETE FROM %s WHERE %I IN (SELECT id FROM deleted_logs)', reference.table_name, reference.column_name);
+    END IF;
+  END LOOP;
+  RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER logs_delete_references
+  AFTER DELETE ON public.logs
+  REFERENCING OLD TABLE AS deleted_logs
+  FOR EACH STATEMENT EXECUTE PROCEDURE public.delete_log_references();
+
+-- Ordered limits and direct bounds on block_number let the planner prune partitions.
+CREATE VIEW public.block_stats AS
+  SELECT
+    (SELECT block_number FROM public.logs ORDER BY block_number DESC LIMIT 1) AS max_block,
+    (SELECT block_number FROM public.logs ORDER BY block_number ASC LIMIT 1)  AS min_block;
+
+CREATE VIEW public.watched_event_logs AS
+  SELECT
+    log_filters.name,
+    logs.id,
+    logs.block_number,
+    logs.address,
+    logs.tx_hash,
+    logs.index,
+    logs.topic0,
+    logs.topic1,
+    logs.topic2,
+    logs.topic3,
+    logs.data,
+    logs.receipt_id
+  FROM public.log_filters
+    JOIN public.logs ON logs.address = log_filters.address
+                        AND logs.block_number >= coalesce(log_filters.from_block, 0)
+                        AND logs.block_number <= coalesce(log_filters.to_block, 9223372036854775807)
+  WHERE (log_filters.topic0 = logs.topic0 OR log_filters.topic0 ISNULL)
+        AND (log_filters.topic1 = logs.topic1 OR log_filters.topic1 ISNULL)
+        AND (log_filters.topic2 = logs.topic2 OR log_filters.topic2 ISNULL)
+        AND (log_filters.topic3 = logs.topic3 OR log_filters.topic3 ISNULL);
+
+COMMIT;
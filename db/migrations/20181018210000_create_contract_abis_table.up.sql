@@ -0,0 +1,6 @@
+CREATE TABLE public.contract_abis (
+  contract_address VARCHAR(42) PRIMARY KEY,
+  abi              TEXT NOT NULL,
+  source           VARCHAR(32) NOT NULL DEFAULT 'manual',
+  created_at       TIMESTAMP NOT NULL DEFAULT NOW()
+);
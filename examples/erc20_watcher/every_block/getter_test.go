@@ -21,11 +21,13 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	"github.com/vulcanize/vulcanizedb/examples/erc20_watcher/every_block"
+	"github.com/vulcanize/vulcanizedb/pkg/core"
 	"github.com/vulcanize/vulcanizedb/pkg/fakes"
 	"github.com/vulcanize/vulcanizedb/pkg/geth"
 	"github.com/vulcanize/vulcanizedb/pkg/geth/client"
@@ -59,8 +61,8 @@ var _ = Describe("ERC20 Getter", func() {
 			rpcClient := client.NewRpcClient(rawRpcClient, infuraIPC)
 			ethClient := ethclient.NewClient(rawRpcClient)
 			blockChainClient := client.NewEthClient(ethClient)
-			node := node.MakeNode(rpcClient)
-			transactionConverter := rpc2.NewRpcTransactionConverter(ethClient)
+			node := node.MakeNodeOfType(rpcClient, core.INFURA)
+			transactionConverter := rpc2.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
 			blockChain := geth.NewBlockChain(blockChainClient, rpcClient, node, transactionConverter)
 			realGetter := every_block.NewGetter(blockChain)
 			result, err := realGetter.GetTotalSupply(constants.DaiAbiString, constants.DaiContractAddress, blockNumber)
@@ -113,8 +115,8 @@ var _ = Describe("ERC20 Getter", func() {
 			rpcClient := client.NewRpcClient(rawRpcClient, infuraIPC)
 			ethClient := ethclient.NewClient(rawRpcClient)
 			blockChainClient := client.NewEthClient(ethClient)
-			node := node.MakeNode(rpcClient)
-			transactionConverter := rpc2.NewRpcTransactionConverter(ethClient)
+			node := node.MakeNodeOfType(rpcClient, core.INFURA)
+			transactionConverter := rpc2.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
 			blockChain := geth.NewBlockChain(blockChainClient, rpcClient, node, transactionConverter)
 			realGetter := every_block.NewGetter(blockChain)
 
@@ -177,8 +179,8 @@ var _ = Describe("ERC20 Getter", func() {
 			rpcClient := client.NewRpcClient(rawRpcClient, infuraIPC)
 			ethClient := ethclient.NewClient(rawRpcClient)
 			blockChainClient := client.NewEthClient(ethClient)
-			node := node.MakeNode(rpcClient)
-			transactionConverter := rpc2.NewRpcTransactionConverter(ethClient)
+			node := node.MakeNodeOfType(rpcClient, core.INFURA)
+			transactionConverter := rpc2.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
 			blockChain := geth.NewBlockChain(blockChainClient, rpcClient, node, transactionConverter)
 			realGetter := every_block.NewGetter(blockChain)
 
@@ -21,11 +21,13 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	"github.com/vulcanize/vulcanizedb/examples/generic/every_block"
+	"github.com/vulcanize/vulcanizedb/pkg/core"
 	"github.com/vulcanize/vulcanizedb/pkg/fakes"
 	"github.com/vulcanize/vulcanizedb/pkg/geth"
 	"github.com/vulcanize/vulcanizedb/pkg/geth/client"
@@ -59,8 +61,8 @@ var _ = Describe("every_block Getter", func() {
 			rpcClient := client.NewRpcClient(rawRpcClient, infuraIPC)
 			ethClient := ethclient.NewClient(rawRpcClient)
 			blockChainClient := client.NewEthClient(ethClient)
-			node := node.MakeNode(rpcClient)
-			transactionConverter := rpc2.NewRpcTransactionConverter(ethClient)
+			node := node.MakeNodeOfType(rpcClient, core.INFURA)
+			transactionConverter := rpc2.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
 			blockChain := geth.NewBlockChain(blockChainClient, rpcClient, node, transactionConverter)
 			realGetter := every_block.NewGetter(blockChain)
 			result, err := realGetter.GetStoppedStatus(constants.DaiAbiString, constants.DaiContractAddress, blockNumber)
@@ -104,8 +106,8 @@ var _ = Describe("every_block Getter", func() {
 			rpcClient := client.NewRpcClient(rawRpcClient, infuraIPC)
 			ethClient := ethclient.NewClient(rawRpcClient)
 			blockChainClient := client.NewEthClient(ethClient)
-			node := node.MakeNode(rpcClient)
-			transactionConverter := rpc2.NewRpcTransactionConverter(ethClient)
+			node := node.MakeNodeOfType(rpcClient, core.INFURA)
+			transactionConverter := rpc2.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
 			blockChain := geth.NewBlockChain(blockChainClient, rpcClient, node, transactionConverter)
 			realGetter := every_block.NewGetter(blockChain)
 			result, err := realGetter.GetOwner(constants.DaiAbiString, constants.DaiContractAddress, blockNumber)
@@ -151,8 +153,8 @@ var _ = Describe("every_block Getter", func() {
 			rpcClient := client.NewRpcClient(rawRpcClient, infuraIPC)
 			ethClient := ethclient.NewClient(rawRpcClient)
 			blockChainClient := client.NewEthClient(ethClient)
-			node := node.MakeNode(rpcClient)
-			transactionConverter := rpc2.NewRpcTransactionConverter(ethClient)
+			node := node.MakeNodeOfType(rpcClient, core.INFURA)
+			transactionConverter := rpc2.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
 			blockChain := geth.NewBlockChain(blockChainClient, rpcClient, node, transactionConverter)
 			realGetter := every_block.NewGetter(blockChain)
 			result, err := realGetter.GetHashName(constants.DaiAbiString, constants.DaiContractAddress, blockNumber)
@@ -198,8 +200,8 @@ var _ = Describe("every_block Getter", func() {
 			rpcClient := client.NewRpcClient(rawRpcClient, infuraIPC)
 			ethClient := ethclient.NewClient(rawRpcClient)
 			blockChainClient := client.NewEthClient(ethClient)
-			node := node.MakeNode(rpcClient)
-			transactionConverter := rpc2.NewRpcTransactionConverter(ethClient)
+			node := node.MakeNodeOfType(rpcClient, core.INFURA)
+			transactionConverter := rpc2.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
 			blockChain := geth.NewBlockChain(blockChainClient, rpcClient, node, transactionConverter)
 			realGetter := every_block.NewGetter(blockChain)
 			result, err := realGetter.GetHashSymbol(constants.DaiAbiString, constants.DaiContractAddress, blockNumber)
@@ -245,8 +247,8 @@ var _ = Describe("every_block Getter", func() {
 			rpcClient := client.NewRpcClient(rawRpcClient, infuraIPC)
 			ethClient := ethclient.NewClient(rawRpcClient)
 			blockChainClient := client.NewEthClient(ethClient)
-			node := node.MakeNode(rpcClient)
-			transactionConverter := rpc2.NewRpcTransactionConverter(ethClient)
+			node := node.MakeNodeOfType(rpcClient, core.INFURA)
+			transactionConverter := rpc2.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
 			blockChain := geth.NewBlockChain(blockChainClient, rpcClient, node, transactionConverter)
 			realGetter := every_block.NewGetter(blockChain)
 			result, err := realGetter.GetDecimals(constants.DaiAbiString, constants.DaiContractAddress, blockNumber)
@@ -293,8 +295,8 @@ var _ = Describe("every_block Getter", func() {
 			rpcClient := client.NewRpcClient(rawRpcClient, infuraIPC)
 			ethClient := ethclient.NewClient(rawRpcClient)
 			blockChainClient := client.NewEthClient(ethClient)
-			node := node.MakeNode(rpcClient)
-			transactionConverter := rpc2.NewRpcTransactionConverter(ethClient)
+			node := node.MakeNodeOfType(rpcClient, core.INFURA)
+			transactionConverter := rpc2.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
 			blockChain := geth.NewBlockChain(blockChainClient, rpcClient, node, transactionConverter)
 			realGetter := every_block.NewGetter(blockChain)
 			result, err := realGetter.GetStringName(constants.TusdAbiString, constants.TusdContractAddress, blockNumber)
@@ -340,8 +342,8 @@ var _ = Describe("every_block Getter", func() {
 			rpcClient := client.NewRpcClient(rawRpcClient, infuraIPC)
 			ethClient := ethclient.NewClient(rawRpcClient)
 			blockChainClient := client.NewEthClient(ethClient)
-			node := node.MakeNode(rpcClient)
-			transactionConverter := rpc2.NewRpcTransactionConverter(ethClient)
+			node := node.MakeNodeOfType(rpcClient, core.INFURA)
+			transactionConverter := rpc2.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
 			blockChain := geth.NewBlockChain(blockChainClient, rpcClient, node, transactionConverter)
 			realGetter := every_block.NewGetter(blockChain)
 			result, err := realGetter.GetStringName(constants.TusdAbiString, constants.TusdContractAddress, blockNumber)
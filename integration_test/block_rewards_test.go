@@ -18,10 +18,12 @@ package integration
 
 import (
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"github.com/vulcanize/vulcanizedb/pkg/core"
 	"github.com/vulcanize/vulcanizedb/pkg/geth"
 	"github.com/vulcanize/vulcanizedb/pkg/geth/client"
 	vRpc "github.com/vulcanize/vulcanizedb/pkg/geth/converters/rpc"
@@ -32,31 +34,31 @@ import (
 var _ = Describe("Rewards calculations", func() {
 
 	It("calculates a block reward for a real block", func() {
-		rawRpcClient, err := rpc.Dial(test_config.InfuraClient.IPCPath)
+		rawRpcClient, err := rpc.Dial(test_config.InfuraClient.Endpoint())
 		Expect(err).NotTo(HaveOccurred())
-		rpcClient := client.NewRpcClient(rawRpcClient, test_config.InfuraClient.IPCPath)
+		rpcClient := client.NewRpcClient(rawRpcClient, test_config.InfuraClient.Endpoint())
 		ethClient := ethclient.NewClient(rawRpcClient)
 		blockChainClient := client.NewEthClient(ethClient)
-		node := node.MakeNode(rpcClient)
-		transactionConverter := vRpc.NewRpcTransactionConverter(ethClient)
+		node := node.MakeNodeOfType(rpcClient, core.INFURA)
+		transactionConverter := vRpc.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
 		blockChain := geth.NewBlockChain(blockChainClient, rpcClient, node, transactionConverter)
 		block, err := blockChain.GetBlockByNumber(1071819)
 		Expect(err).ToNot(HaveOccurred())
-		Expect(block.Reward).To(Equal(5.31355))
+		Expect(block.Reward).To(Equal("5313550000000000000"))
 	})
 
 	It("calculates an uncle reward for a real block", func() {
-		rawRpcClient, err := rpc.Dial(test_config.InfuraClient.IPCPath)
+		rawRpcClient, err := rpc.Dial(test_config.InfuraClient.Endpoint())
 		Expect(err).NotTo(HaveOccurred())
-		rpcClient := client.NewRpcClient(rawRpcClient, test_config.InfuraClient.IPCPath)
+		rpcClient := client.NewRpcClient(rawRpcClient, test_config.InfuraClient.Endpoint())
 		ethClient := ethclient.NewClient(rawRpcClient)
 		blockChainClient := client.NewEthClient(ethClient)
-		node := node.MakeNode(rpcClient)
-		transactionConverter := vRpc.NewRpcTransactionConverter(ethClient)
+		node := node.MakeNodeOfType(rpcClient, core.INFURA)
+		transactionConverter := vRpc.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
 		blockChain := geth.NewBlockChain(blockChainClient, rpcClient, node, transactionConverter)
 		block, err := blockChain.GetBlockByNumber(1071819)
 		Expect(err).ToNot(HaveOccurred())
-		Expect(block.UnclesReward).To(Equal(6.875))
+		Expect(block.UnclesReward).To(Equal("6875000000000000000"))
 	})
 
 })
@@ -21,6 +21,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -49,13 +50,13 @@ var _ = Describe("Reading contracts", func() {
 				},
 				Index: 19,
 				Data:  "0x0000000000000000000000000000000000000000000000000c7d713b49da0000"}
-			rawRpcClient, err := rpc.Dial(test_config.InfuraClient.IPCPath)
+			rawRpcClient, err := rpc.Dial(test_config.InfuraClient.Endpoint())
 			Expect(err).NotTo(HaveOccurred())
-			rpcClient := client.NewRpcClient(rawRpcClient, test_config.InfuraClient.IPCPath)
+			rpcClient := client.NewRpcClient(rawRpcClient, test_config.InfuraClient.Endpoint())
 			ethClient := ethclient.NewClient(rawRpcClient)
 			blockChainClient := client.NewEthClient(ethClient)
-			node := node.MakeNode(rpcClient)
-			transactionConverter := rpc2.NewRpcTransactionConverter(ethClient)
+			node := node.MakeNodeOfType(rpcClient, core.INFURA)
+			transactionConverter := rpc2.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
 			blockChain := geth.NewBlockChain(blockChainClient, rpcClient, node, transactionConverter)
 			contract := testing.SampleContract()
 
@@ -67,13 +68,13 @@ var _ = Describe("Reading contracts", func() {
 		})
 
 		It("returns and empty log array when no events for a given block / contract combo", func() {
-			rawRpcClient, err := rpc.Dial(test_config.InfuraClient.IPCPath)
+			rawRpcClient, err := rpc.Dial(test_config.InfuraClient.Endpoint())
 			Expect(err).NotTo(HaveOccurred())
-			rpcClient := client.NewRpcClient(rawRpcClient, test_config.InfuraClient.IPCPath)
+			rpcClient := client.NewRpcClient(rawRpcClient, test_config.InfuraClient.Endpoint())
 			ethClient := ethclient.NewClient(rawRpcClient)
 			blockChainClient := client.NewEthClient(ethClient)
-			node := node.MakeNode(rpcClient)
-			transactionConverter := rpc2.NewRpcTransactionConverter(ethClient)
+			node := node.MakeNodeOfType(rpcClient, core.INFURA)
+			transactionConverter := rpc2.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
 			blockChain := geth.NewBlockChain(blockChainClient, rpcClient, node, transactionConverter)
 
 			logs, err := blockChain.GetLogs(core.Contract{Hash: "x123"}, big.NewInt(4703824), nil)
@@ -86,13 +87,13 @@ var _ = Describe("Reading contracts", func() {
 
 	Describe("Fetching Contract data", func() {
 		It("returns the correct attribute for a real contract", func() {
-			rawRpcClient, err := rpc.Dial(test_config.InfuraClient.IPCPath)
+			rawRpcClient, err := rpc.Dial(test_config.InfuraClient.Endpoint())
 			Expect(err).NotTo(HaveOccurred())
-			rpcClient := client.NewRpcClient(rawRpcClient, test_config.InfuraClient.IPCPath)
+			rpcClient := client.NewRpcClient(rawRpcClient, test_config.InfuraClient.Endpoint())
 			ethClient := ethclient.NewClient(rawRpcClient)
 			blockChainClient := client.NewEthClient(ethClient)
-			node := node.MakeNode(rpcClient)
-			transactionConverter := rpc2.NewRpcTransactionConverter(ethClient)
+			node := node.MakeNodeOfType(rpcClient, core.INFURA)
+			transactionConverter := rpc2.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
 			blockChain := geth.NewBlockChain(blockChainClient, rpcClient, node, transactionConverter)
 
 			contract := testing.SampleContract()
@@ -18,6 +18,7 @@ package integration_test
 
 import (
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -36,13 +37,13 @@ var _ = Describe("Reading from the Geth blockchain", func() {
 	var blockChain *geth.BlockChain
 
 	BeforeEach(func() {
-		rawRpcClient, err := rpc.Dial(test_config.InfuraClient.IPCPath)
+		rawRpcClient, err := rpc.Dial(test_config.InfuraClient.Endpoint())
 		Expect(err).NotTo(HaveOccurred())
-		rpcClient := client.NewRpcClient(rawRpcClient, test_config.InfuraClient.IPCPath)
+		rpcClient := client.NewRpcClient(rawRpcClient, test_config.InfuraClient.Endpoint())
 		ethClient := ethclient.NewClient(rawRpcClient)
 		blockChainClient := client.NewEthClient(ethClient)
-		node := node.MakeNode(rpcClient)
-		transactionConverter := rpc2.NewRpcTransactionConverter(ethClient)
+		node := node.MakeNodeOfType(rpcClient, core.INFURA)
+		transactionConverter := rpc2.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
 		blockChain = geth.NewBlockChain(blockChainClient, rpcClient, node, transactionConverter)
 	})
 
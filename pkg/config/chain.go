@@ -0,0 +1,68 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package config
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+)
+
+// Chain holds block reward schedules for networks vulcanizedb does not know about, such as
+// private devnets (Rewards), and overrides keyed by network id (Networks)
+type Chain struct {
+	Rewards  core.RewardSchedule
+	Networks map[string]core.RewardSchedule
+}
+
+// RewardScheduleForNetwork prefers a schedule configured for the network, then the built in
+// schedule of a known public network, then the configured default; unknown networks without
+// a configured schedule are assumed to follow mainnet
+func (chain Chain) RewardScheduleForNetwork(networkID float64) core.RewardSchedule {
+	if schedule, ok := chain.Networks[strconv.FormatFloat(networkID, 'f', -1, 64)]; ok {
+		return schedule
+	}
+	if schedule, ok := core.RewardScheduleForNetwork(networkID); ok {
+		return schedule
+	}
+	if chain.Rewards != nil {
+		return chain.Rewards
+	}
+	return core.MainnetRewardSchedule
+}
+
+// ParseRewardSchedule builds a schedule from block numbers mapped to static rewards in wei.
+// An empty map yields a nil schedule so that callers can tell it was not configured.
+func ParseRewardSchedule(rewards map[string]string) (core.RewardSchedule, error) {
+	if len(rewards) == 0 {
+		return nil, nil
+	}
+	var blockRewards []core.BlockReward
+	for block, wei := range rewards {
+		blockNumber, err := strconv.ParseInt(block, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reward block number %q", block)
+		}
+		reward, ok := new(big.Int).SetString(wei, 10)
+		if !ok || reward.Sign() < 0 {
+			return nil, fmt.Errorf("invalid reward %q for block %d, expected an amount in wei", wei, blockNumber)
+		}
+		blockRewards = append(blockRewards, core.BlockReward{Block: blockNumber, Reward: reward})
+	}
+	return core.NewRewardSchedule(blockRewards...), nil
+}
@@ -18,4 +18,25 @@ package config
 
 type Client struct {
 	IPCPath string
+	// http(s):// or ws(s):// endpoint, takes precedence over IPCPath when set
+	RPCURL string
+	// Headers sent with every request to RPCURL, e.g. Authorization
+	Headers map[string]string
+	// One of geth, parity, infura or ganache; detected from the node when empty
+	NodeType string
+	// Maximum number of requests per second sent to the endpoint, unlimited when 0
+	RateLimit float64
+	// Endpoints on the same network to fail over to, in order of preference
+	Fallbacks []Client
+	// Number of blocks an endpoint's head may lag behind the others before failing over
+	MaxHeadLag int64
+}
+
+const DefaultMaxHeadLag = 5
+
+func (client Client) Endpoint() string {
+	if client.RPCURL != "" {
+		return client.RPCURL
+	}
+	return client.IPCPath
 }
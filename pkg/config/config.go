@@ -19,4 +19,5 @@ package config
 type Config struct {
 	Database Database
 	Client   Client
+	Finality Finality
 }
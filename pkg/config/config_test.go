@@ -18,10 +18,14 @@ package config_test
 
 import (
 	"bytes"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/spf13/viper"
+
+	"github.com/vulcanize/vulcanizedb/pkg/config"
+	"github.com/vulcanize/vulcanizedb/pkg/core"
 )
 
 var vulcanizeConfig = []byte(`
@@ -52,3 +56,210 @@ var _ = Describe("Loading the config", func() {
 	})
 
 })
+
+var _ = Describe("Client fallbacks", func() {
+	It("reads fallback endpoints from the config", func() {
+		testConfig := viper.New()
+		testConfig.SetConfigType("toml")
+		err := testConfig.ReadConfig(bytes.NewBufferString(`
+[client]
+ipcPath = "IPCPATH/geth.ipc"
+
+[[client.fallbacks]]
+rpcUrl = "https://node.example.com"
+rateLimit = 10
+  [client.fallbacks.headers]
+  Authorization = "Bearer token"
+
+[[client.fallbacks]]
+rpcUrl = "wss://other.example.com"
+`))
+		Expect(err).NotTo(HaveOccurred())
+
+		var fallbacks []config.Client
+		err = testConfig.UnmarshalKey("client.fallbacks", &fallbacks)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fallbacks).To(Equal([]config.Client{
+			{RPCURL: "https://node.example.com", RateLimit: 10, Headers: map[string]string{"Authorization": "Bearer token"}},
+			{RPCURL: "wss://other.example.com"},
+		}))
+	})
+})
+
+var _ = Describe("Finality depth", func() {
+	It("defaults when no depth is configured", func() {
+		Expect(config.Finality{}.DepthForNetwork(1)).To(Equal(int64(config.DefaultFinalityDepth)))
+	})
+
+	It("uses the configured depth", func() {
+		depth := int64(50)
+		finality := config.Finality{Depth: &depth}
+
+		Expect(finality.DepthForNetwork(1)).To(Equal(int64(50)))
+	})
+
+	It("uses a configured depth of 0", func() {
+		depth := int64(0)
+		finality := config.Finality{Depth: &depth}
+
+		Expect(finality.DepthForNetwork(1)).To(Equal(int64(0)))
+	})
+
+	It("uses the depth configured for the network", func() {
+		depth := int64(50)
+		finality := config.Finality{Depth: &depth, Networks: map[string]int64{"42": 5}}
+
+		Expect(finality.DepthForNetwork(42)).To(Equal(int64(5)))
+		Expect(finality.DepthForNetwork(4)).To(Equal(int64(50)))
+	})
+})
+
+var _ = Describe("Retention", func() {
+	It("is disabled unless blocks to keep are configured", func() {
+		Expect(config.Retention{}.Enabled()).To(BeFalse())
+		Expect(config.Retention{}.StartingBlockNumber(0, 1000)).To(Equal(int64(0)))
+	})
+
+	It("starts syncing after the pruned blocks", func() {
+		retention := config.Retention{KeepBlocks: 100}
+
+		Expect(retention.StartingBlockNumber(0, 1000)).To(Equal(int64(901)))
+		Expect(retention.StartingBlockNumber(950, 1000)).To(Equal(int64(950)))
+		Expect(retention.StartingBlockNumber(0, 50)).To(Equal(int64(0)))
+	})
+
+	It("defaults the prune interval", func() {
+		Expect(config.Retention{}.PruneInterval()).To(Equal(config.DefaultRetentionInterval))
+		Expect(config.Retention{Interval: time.Minute}.PruneInterval()).To(Equal(time.Minute))
+	})
+})
+
+var _ = Describe("Chain reward schedules", func() {
+	It("uses the built in schedule for known networks", func() {
+		chain := config.Chain{Rewards: core.ProofOfAuthorityRewardSchedule}
+
+		Expect(chain.RewardScheduleForNetwork(core.MAINNET_NETWORK_ID)).To(Equal(core.MainnetRewardSchedule))
+		Expect(chain.RewardScheduleForNetwork(core.KOVAN_NETWORK_ID)).To(Equal(core.ProofOfAuthorityRewardSchedule))
+	})
+
+	It("uses the configured schedule for unknown networks", func() {
+		schedule, err := config.ParseRewardSchedule(map[string]string{"100": "1000", "0": "3000"})
+		Expect(err).NotTo(HaveOccurred())
+		chain := config.Chain{Rewards: schedule}
+
+		devnetSchedule := chain.RewardScheduleForNetwork(1337)
+
+		Expect(devnetSchedule.StaticReward(0).String()).To(Equal("3000"))
+		Expect(devnetSchedule.StaticReward(99).String()).To(Equal("3000"))
+		Expect(devnetSchedule.StaticReward(100).String()).To(Equal("1000"))
+	})
+
+	It("falls back to the mainnet schedule for unknown networks", func() {
+		Expect(config.Chain{}.RewardScheduleForNetwork(1337)).To(Equal(core.MainnetRewardSchedule))
+	})
+
+	It("prefers a schedule configured for the network", func() {
+		chain := config.Chain{Networks: map[string]core.RewardSchedule{"1": core.ProofOfAuthorityRewardSchedule}}
+
+		Expect(chain.RewardScheduleForNetwork(1).StaticReward(8000000).String()).To(Equal("0"))
+	})
+
+	It("follows the mainnet hard forks", func() {
+		Expect(core.MainnetRewardSchedule.StaticReward(4369999).String()).To(Equal("5000000000000000000"))
+		Expect(core.MainnetRewardSchedule.StaticReward(4370000).String()).To(Equal("3000000000000000000"))
+		Expect(core.MainnetRewardSchedule.StaticReward(7280000).String()).To(Equal("2000000000000000000"))
+	})
+
+	It("rejects rewards that are not whole amounts of wei", func() {
+		_, err := config.ParseRewardSchedule(map[string]string{"0": "2.5"})
+
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Contracts", func() {
+	It("reads contracts from the config", func() {
+		testConfig := viper.New()
+		testConfig.SetConfigType("toml")
+		err := testConfig.ReadConfig(bytes.NewBufferString(`
+[[contract]]
+address = "0x8dd5fbCe2F6a956C3022bA3663759011Dd51e73E"
+abiFile = "abis/tusd.json"
+events = ["Transfer"]
+methods = ["balanceOf", "allowance"]
+methodFilterAddresses = ["0x1234567890123456789012345678901234567890"]
+startingBlock = 5197514
+
+[[contract.methodArgs]]
+method = "allowance"
+args = [["0x1234567890123456789012345678901234567890", "0x8dd5fbCe2F6a956C3022bA3663759011Dd51e73E"]]
+eventFields = ["Approval.owner", "Approval.spender"]
+
+[[contract]]
+address = "0x89d24A6b4CcB1B6fAA2625fE562bDD9a23260359"
+abiNetwork = "kovan"
+endingBlock = 6000000
+`))
+		Expect(err).NotTo(HaveOccurred())
+
+		var contracts []config.Contract
+		err = testConfig.UnmarshalKey("contract", &contracts)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(contracts).To(Equal([]config.Contract{
+			{
+				Address:               "0x8dd5fbCe2F6a956C3022bA3663759011Dd51e73E",
+				AbiFile:               "abis/tusd.json",
+				Events:                []string{"Transfer"},
+				Methods:               []string{"balanceOf", "allowance"},
+				MethodFilterAddresses: []string{"0x1234567890123456789012345678901234567890"},
+				MethodArgs: []config.MethodArgs{{
+					Method:      "allowance",
+					Args:        [][]string{{"0x1234567890123456789012345678901234567890", "0x8dd5fbCe2F6a956C3022bA3663759011Dd51e73E"}},
+					EventFields: []string{"Approval.owner", "Approval.spender"},
+				}},
+				StartingBlock: 5197514,
+			},
+			{
+				Address:     "0x89d24A6b4CcB1B6fAA2625fE562bDD9a23260359",
+				AbiNetwork:  "kovan",
+				EndingBlock: 6000000,
+			},
+		}))
+		Expect(config.ValidateContracts(contracts)).To(Succeed())
+	})
+
+	It("watches every event and up to the chain head by default", func() {
+		contract := config.Contract{Address: "0x8dd5fbCe2F6a956C3022bA3663759011Dd51e73E", StartingBlock: 10}
+
+		Expect(contract.WatchedEvents()).To(Equal([]string{}))
+		Expect(contract.Range()).To(Equal([2]int64{10, -1}))
+	})
+
+	It("rejects invalid contracts", func() {
+		address := "0x8dd5fbCe2F6a956C3022bA3663759011Dd51e73E"
+
+		Expect(config.ValidateContracts([]config.Contract{{}})).NotTo(Succeed())
+		Expect(config.ValidateContracts([]config.Contract{{Address: address}, {Address: "0x8dd5fbce2f6a956c3022ba3663759011dd51e73e"}})).NotTo(Succeed())
+		Expect(config.ValidateContracts([]config.Contract{{Address: address, Abi: "[]", AbiFile: "abi.json"}})).NotTo(Succeed())
+		Expect(config.ValidateContracts([]config.Contract{{Address: address, StartingBlock: 10, EndingBlock: 5}})).NotTo(Succeed())
+	})
+
+	It("rejects invalid method arguments", func() {
+		contract := func(methodArgs config.MethodArgs) []config.Contract {
+			return []config.Contract{{
+				Address:    "0x8dd5fbCe2F6a956C3022bA3663759011Dd51e73E",
+				Methods:    []string{"ownerOf", "allowance"},
+				MethodArgs: []config.MethodArgs{methodArgs},
+			}}
+		}
+
+		Expect(config.ValidateContracts(contract(config.MethodArgs{Method: "ownerOf", Args: [][]string{{"1"}, {"2"}}, EventFields: []string{"Transfer.tokenId"}}))).To(Succeed())
+		Expect(config.ValidateContracts(contract(config.MethodArgs{Method: "balanceOf", Args: [][]string{{"0x1234567890123456789012345678901234567890"}}}))).NotTo(Succeed())
+		Expect(config.ValidateContracts(contract(config.MethodArgs{Method: "allowance", Args: [][]string{{"0x1"}, {"0x1", "0x2"}}}))).NotTo(Succeed())
+		Expect(config.ValidateContracts(contract(config.MethodArgs{Method: "allowance", Args: [][]string{{"0x1"}}, EventFields: []string{"Approval.owner", "Approval.spender"}}))).NotTo(Succeed())
+		Expect(config.ValidateContracts(contract(config.MethodArgs{Method: "ownerOf", EventFields: []string{"tokenId"}}))).NotTo(Succeed())
+		Expect(config.ValidateContracts(contract(config.MethodArgs{Method: "allowance", EventFields: []string{"Approval.owner", "Transfer.to"}}))).NotTo(Succeed())
+	})
+})
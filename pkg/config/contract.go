@@ -0,0 +1,126 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Contract configures what a watcher indexes for one contract, read from a [[contract]] section
+type Contract struct {
+	Address string
+	// Inline ABI or ABI file; when neither is set the ABI is looked up as for --abi-file etc.
+	Abi     string
+	AbiFile string
+	// Network whose etherscan the ABI is fetched from, defaulting to --network. It does not
+	// change the chain the contract is watched on, which is always the connected node's.
+	AbiNetwork string
+	// Events to watch, all when empty; methods to poll, none when empty
+	Events  []string
+	Methods []string
+	// Addresses event and method data is persisted for, all found token holders when empty
+	EventFilterAddresses  []string
+	MethodFilterAddresses []string
+	// Arguments to poll methods with, read from [[contract.methodArgs]] sections
+	MethodArgs    []MethodArgs
+	StartingBlock int64
+	EndingBlock   int64
+}
+
+// MethodArgs lists argument sets to poll a method with, given directly or as fields of an event,
+// e.g. eventFields = ["Transfer.tokenId"] polls with each tokenId seen in Transfer logs
+type MethodArgs struct {
+	Method      string
+	Args        [][]string
+	EventFields []string
+}
+
+// Range returns the blocks to watch, where an ending block of -1 means the chain head
+func (contract Contract) Range() [2]int64 {
+	endingBlock := contract.EndingBlock
+	if endingBlock == 0 {
+		endingBlock = -1
+	}
+	return [2]int64{contract.StartingBlock, endingBlock}
+}
+
+// WatchedEvents returns the events to watch, an empty list meaning all of them
+func (contract Contract) WatchedEvents() []string {
+	if contract.Events == nil {
+		return []string{}
+	}
+	return contract.Events
+}
+
+// ValidateContracts checks contracts have unique addresses, at most one ABI source and a valid range
+func ValidateContracts(contracts []Contract) error {
+	seen := make(map[string]bool)
+	for _, contract := range contracts {
+		if contract.Address == "" {
+			return errors.New("contract address required")
+		}
+		address := strings.ToLower(contract.Address)
+		if seen[address] {
+			return fmt.Errorf("contract %s configured more than once", contract.Address)
+		}
+		seen[address] = true
+		if contract.Abi != "" && contract.AbiFile != "" {
+			return fmt.Errorf("contract %s has both an abi and an abiFile", contract.Address)
+		}
+		if rng := contract.Range(); rng[1] != -1 && rng[1] < rng[0] {
+			return fmt.Errorf("contract %s ending block %d is before starting block %d", contract.Address, rng[1], rng[0])
+		}
+		for _, methodArgs := range contract.MethodArgs {
+			if err := validateMethodArgs(contract, methodArgs); err != nil {
+				return fmt.Errorf("contract %s: %v", contract.Address, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateMethodArgs(contract Contract, methodArgs MethodArgs) error {
+	polled := false
+	for _, method := range contract.Methods {
+		polled = polled || method == methodArgs.Method
+	}
+	if !polled {
+		return fmt.Errorf("methodArgs given for %q, which is not in methods", methodArgs.Method)
+	}
+	for _, args := range methodArgs.Args {
+		if len(args) != len(methodArgs.Args[0]) {
+			return fmt.Errorf("argument sets for %s differ in length", methodArgs.Method)
+		}
+	}
+	if len(methodArgs.Args) > 0 && len(methodArgs.EventFields) > 0 && len(methodArgs.Args[0]) != len(methodArgs.EventFields) {
+		return fmt.Errorf("argument sets and event fields for %s differ in length", methodArgs.Method)
+	}
+	var event string
+	for _, field := range methodArgs.EventFields {
+		parts := strings.Split(field, ".")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("event field %q for %s is not of the form Event.field", field, methodArgs.Method)
+		}
+		if event != "" && parts[0] != event {
+			return fmt.Errorf("event fields for %s come from more than one event", methodArgs.Method)
+		}
+		event = parts[0]
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package config
+
+import "strconv"
+
+const DefaultFinalityDepth = 20
+
+// Finality holds the number of blocks behind the chain head after which blocks and headers
+// are considered final, with optional overrides keyed by network id
+// A nil Depth falls back to DefaultFinalityDepth, so that a depth of 0 can be configured
+type Finality struct {
+	Depth    *int64
+	Networks map[string]int64
+}
+
+func (finality Finality) DepthForNetwork(networkID float64) int64 {
+	if depth, ok := finality.Networks[strconv.FormatFloat(networkID, 'f', -1, 64)]; ok {
+		return depth
+	}
+	if finality.Depth != nil {
+		return *finality.Depth
+	}
+	return DefaultFinalityDepth
+}
@@ -0,0 +1,52 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package config
+
+import "time"
+
+const DefaultRetentionInterval = 10 * time.Minute
+
+// Retention limits raw chain data to the most recent KeepBlocks blocks, optionally keeping
+// older rows referenced by transformer output. Nothing is pruned when KeepBlocks is zero.
+type Retention struct {
+	KeepBlocks     int64
+	KeepReferenced bool
+	Interval       time.Duration
+}
+
+func (retention Retention) Enabled() bool {
+	return retention.KeepBlocks > 0
+}
+
+// StartingBlockNumber raises startingBlockNumber past the blocks pruned relative to head,
+// so syncing does not repopulate them
+func (retention Retention) StartingBlockNumber(startingBlockNumber, head int64) int64 {
+	if !retention.Enabled() {
+		return startingBlockNumber
+	}
+	if cutoff := head - retention.KeepBlocks + 1; cutoff > startingBlockNumber {
+		return cutoff
+	}
+	return startingBlockNumber
+}
+
+func (retention Retention) PruneInterval() time.Duration {
+	if retention.Interval > 0 {
+		return retention.Interval
+	}
+	return DefaultRetentionInterval
+}
@@ -0,0 +1,29 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package core
+
+import "github.com/ethereum/go-ethereum/params"
+
+var networkChainConfigs = map[float64]*params.ChainConfig{
+	MAINNET_NETWORK_ID: params.MainnetChainConfig,
+	ROPSTEN_NETWORK_ID: params.TestnetChainConfig,
+	RINKEBY_NETWORK_ID: params.RinkebyChainConfig,
+}
+
+// ChainConfigForNetwork returns the chain config of a known public network, or nil
+func ChainConfigForNetwork(networkID float64) *params.ChainConfig {
+	return networkChainConfigs[networkID]
+}
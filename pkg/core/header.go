@@ -28,6 +28,15 @@ type Header struct {
 	Hash        string
 	Raw         []byte
 	Timestamp   string `db:"block_timestamp"`
+	IsFinal     bool   `db:"is_final"`
+	// Total difficulty of the chain up to and including this header
+	TotalDifficulty string `db:"total_difficulty"`
+}
+
+// BlockTotalDifficulty reads the total difficulty that nodes include in eth_getBlockByHash
+// and eth_getBlockByNumber responses
+type BlockTotalDifficulty struct {
+	TotalDifficulty *hexutil.Big `json:"totalDifficulty"`
 }
 
 type POAHeader struct {
@@ -45,4 +54,6 @@ type POAHeader struct {
 	Time        *hexutil.Big   `json:"timestamp"        gencodec:"required"`
 	Extra       hexutil.Bytes  `json:"extraData"        gencodec:"required"`
 	Hash        common.Hash    `json:"hash"`
+	// Total difficulty of the chain, which is not part of the header itself
+	TotalDifficulty *hexutil.Big `json:"totalDifficulty"`
 }
@@ -18,9 +18,11 @@ package core
 
 type Log struct {
 	BlockNumber int64
+	BlockHash   string
 	TxHash      string
 	Address     string
 	Topics
-	Index int64
-	Data  string
+	Index   int64
+	Data    string
+	Removed bool
 }
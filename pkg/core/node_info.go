@@ -18,6 +18,7 @@ package core
 
 import (
 	"fmt"
+	"strings"
 )
 
 type NodeType int
@@ -30,9 +31,28 @@ const (
 )
 
 const (
-	KOVAN_NETWORK_ID = 42
+	MAINNET_NETWORK_ID = 1
+	ROPSTEN_NETWORK_ID = 3
+	RINKEBY_NETWORK_ID = 4
+	GOERLI_NETWORK_ID  = 5
+	KOVAN_NETWORK_ID   = 42
 )
 
+var nodeTypes = map[string]NodeType{
+	"geth":    GETH,
+	"parity":  PARITY,
+	"infura":  INFURA,
+	"ganache": GANACHE,
+}
+
+func ParseNodeType(name string) (NodeType, error) {
+	nodeType, ok := nodeTypes[strings.ToLower(name)]
+	if !ok {
+		return 0, fmt.Errorf("unknown node type %q, expected one of geth, parity, infura or ganache", name)
+	}
+	return nodeType, nil
+}
+
 type Node struct {
 	GenesisBlock string
 	NetworkID    float64
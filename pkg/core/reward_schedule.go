@@ -0,0 +1,86 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package core
+
+import (
+	"math/big"
+	"sort"
+)
+
+// BlockReward is the static reward in wei paid to a block's miner from Block onwards
+type BlockReward struct {
+	Block  int64
+	Reward *big.Int
+}
+
+// RewardSchedule is the list of static block rewards a network has paid, ordered by block number
+type RewardSchedule []BlockReward
+
+var (
+	MainnetRewardSchedule = RewardSchedule{
+		{Block: 0, Reward: etherToWei(5)},
+		// https://blog.ethereum.org/2017/10/12/byzantium-hf-announcement/
+		{Block: 4370000, Reward: etherToWei(3)},
+		// https://blog.ethereum.org/2019/02/22/ethereum-constantinople-st-petersburg-upgrade-announcement/
+		{Block: 7280000, Reward: etherToWei(2)},
+	}
+	RopstenRewardSchedule = RewardSchedule{
+		{Block: 0, Reward: etherToWei(5)},
+		{Block: 1700000, Reward: etherToWei(3)},
+		{Block: 4230000, Reward: etherToWei(2)},
+	}
+	// Proof of authority networks do not pay block rewards
+	ProofOfAuthorityRewardSchedule = RewardSchedule{}
+)
+
+var networkRewardSchedules = map[float64]RewardSchedule{
+	MAINNET_NETWORK_ID: MainnetRewardSchedule,
+	ROPSTEN_NETWORK_ID: RopstenRewardSchedule,
+	RINKEBY_NETWORK_ID: ProofOfAuthorityRewardSchedule,
+	GOERLI_NETWORK_ID:  ProofOfAuthorityRewardSchedule,
+	KOVAN_NETWORK_ID:   ProofOfAuthorityRewardSchedule,
+}
+
+// RewardScheduleForNetwork returns the reward schedule of a known public network
+func RewardScheduleForNetwork(networkID float64) (RewardSchedule, bool) {
+	schedule, ok := networkRewardSchedules[networkID]
+	return schedule, ok
+}
+
+func NewRewardSchedule(rewards ...BlockReward) RewardSchedule {
+	schedule := append(RewardSchedule{}, rewards...)
+	sort.SliceStable(schedule, func(i, j int) bool {
+		return schedule[i].Block < schedule[j].Block
+	})
+	return schedule
+}
+
+// StaticReward returns the static block reward in wei for a block number, or zero if
+// the schedule pays no reward at that height
+func (schedule RewardSchedule) StaticReward(blockNumber int64) *big.Int {
+	reward := big.NewInt(0)
+	for _, blockReward := range schedule {
+		if blockReward.Block > blockNumber {
+			break
+		}
+		reward = blockReward.Reward
+	}
+	return new(big.Int).Set(reward)
+}
+
+func etherToWei(ether int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(ether), big.NewInt(1e18))
+}
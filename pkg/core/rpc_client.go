@@ -16,9 +16,14 @@
 
 package core
 
-import "context"
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
 
 type RpcClient interface {
+	BatchCall(batch []rpc.BatchElem) error
 	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
 	IpcPath() string
 	SupportedModules() (map[string]string, error)
@@ -23,7 +23,8 @@ type Transaction struct {
 	To       string `db:"tx_to"`
 	From     string `db:"tx_from"`
 	GasLimit uint64 `db:"gaslimit"`
-	GasPrice int64  `db:"gasprice"`
+	GasPrice string `db:"gasprice"`
+	TxIndex  int64  `db:"tx_index"`
 	Receipt
 	Value string `db:"value"`
 }
@@ -23,13 +23,15 @@ const (
 )
 
 type DatabaseConfig struct {
-	Type DatabaseType
-	Path string
+	Type        DatabaseType
+	Path        string
+	AncientPath string
 }
 
-func CreateDatabaseConfig(dbType DatabaseType, path string) DatabaseConfig {
+func CreateDatabaseConfig(dbType DatabaseType, path, ancientPath string) DatabaseConfig {
 	return DatabaseConfig{
-		Type: dbType,
-		Path: path,
+		Type:        dbType,
+		Path:        path,
+		AncientPath: ancientPath,
 	}
 }
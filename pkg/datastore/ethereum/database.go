@@ -18,9 +18,13 @@ package ethereum
 
 import (
 	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
 
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
 
 	"github.com/vulcanize/vulcanizedb/pkg/datastore/ethereum/level"
 )
@@ -29,20 +33,45 @@ type Database interface {
 	GetBlock(hash []byte, blockNumber int64) *types.Block
 	GetBlockHash(blockNumber int64) []byte
 	GetBlockReceipts(blockHash []byte, blockNumber int64) types.Receipts
+	GetChainConfig(genesisHash []byte) *params.ChainConfig
+	GetHeader(blockHash []byte, blockNumber int64) *types.Header
 	GetHeadBlockNumber() int64
+	GetTotalDifficulty(blockHash []byte, blockNumber int64) *big.Int
 }
 
 func CreateDatabase(config DatabaseConfig) (Database, error) {
 	switch config.Type {
 	case Level:
+		// NewLDBDatabase creates a database where none exists, so make sure
+		// we are pointed at real chaindata first.
+		if _, err := os.Stat(filepath.Join(config.Path, "CURRENT")); err != nil {
+			return nil, fmt.Errorf("No LevelDB found at %s: %v", config.Path, err)
+		}
 		levelDBConnection, err := ethdb.NewLDBDatabase(config.Path, 128, 1024)
 		if err != nil {
 			return nil, err
 		}
-		levelDBReader := level.NewLevelDatabaseReader(levelDBConnection)
+		ancients, err := openAncients(config)
+		if err != nil {
+			return nil, err
+		}
+		levelDBReader := level.NewLevelDatabaseReader(levelDBConnection, ancients)
 		levelDB := level.NewLevelDatabase(levelDBReader)
 		return levelDB, nil
 	default:
 		return nil, fmt.Errorf("Unknown ethereum database: %s", config.Path)
 	}
 }
+
+// openAncients opens geth's ancient store, which defaults to the "ancient"
+// directory inside chaindata and is absent for nodes older than geth 1.9.
+func openAncients(config DatabaseConfig) (*level.Freezer, error) {
+	ancientPath := config.AncientPath
+	if ancientPath == "" {
+		ancientPath = filepath.Join(config.Path, "ancient")
+		if _, err := os.Stat(ancientPath); os.IsNotExist(err) {
+			return nil, nil
+		}
+	}
+	return level.OpenFreezer(ancientPath)
+}
@@ -17,8 +17,11 @@
 package level
 
 import (
+	"math/big"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
 )
 
 type LevelDatabase struct {
@@ -49,8 +52,25 @@ func (l LevelDatabase) GetBlockReceipts(blockHash []byte, blockNumber int64) typ
 	return l.reader.GetBlockReceipts(h, n)
 }
 
+func (l LevelDatabase) GetChainConfig(genesisHash []byte) *params.ChainConfig {
+	h := common.BytesToHash(genesisHash)
+	return l.reader.GetChainConfig(h)
+}
+
+func (l LevelDatabase) GetHeader(blockHash []byte, blockNumber int64) *types.Header {
+	n := uint64(blockNumber)
+	h := common.BytesToHash(blockHash)
+	return l.reader.GetHeader(h, n)
+}
+
 func (l LevelDatabase) GetHeadBlockNumber() int64 {
 	h := l.reader.GetHeadBlockHash()
 	n := l.reader.GetBlockNumber(h)
 	return int64(*n)
 }
+
+func (l LevelDatabase) GetTotalDifficulty(blockHash []byte, blockNumber int64) *big.Int {
+	n := uint64(blockNumber)
+	h := common.BytesToHash(blockHash)
+	return l.reader.GetTotalDifficulty(h, n)
+}
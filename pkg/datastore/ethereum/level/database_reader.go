@@ -17,9 +17,16 @@
 package level
 
 import (
+	"encoding/binary"
+	"math/big"
+	"sync"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
 type Reader interface {
@@ -27,19 +34,45 @@ type Reader interface {
 	GetBlockNumber(hash common.Hash) *uint64
 	GetBlockReceipts(hash common.Hash, number uint64) types.Receipts
 	GetCanonicalHash(number uint64) common.Hash
+	GetChainConfig(genesisHash common.Hash) *params.ChainConfig
+	GetHeader(hash common.Hash, number uint64) *types.Header
 	GetHeadBlockHash() common.Hash
+	GetTotalDifficulty(hash common.Hash, number uint64) *big.Int
 }
 
+// LevelDatabaseReader reads chain data from LevelDB, falling back to the
+// ancient store (if any) for blocks geth has moved out of LevelDB.
 type LevelDatabaseReader struct {
-	reader rawdb.DatabaseReader
+	reader   rawdb.DatabaseReader
+	ancients *Freezer
+
+	chainConfigOnce sync.Once
+	chainConfig     *params.ChainConfig
 }
 
-func NewLevelDatabaseReader(reader rawdb.DatabaseReader) *LevelDatabaseReader {
-	return &LevelDatabaseReader{reader: reader}
+func NewLevelDatabaseReader(reader rawdb.DatabaseReader, ancients *Freezer) *LevelDatabaseReader {
+	return &LevelDatabaseReader{reader: reader, ancients: ancients}
 }
 
 func (ldbr *LevelDatabaseReader) GetBlock(hash common.Hash, number uint64) *types.Block {
-	return rawdb.ReadBlock(ldbr.reader, hash, number)
+	block := rawdb.ReadBlock(ldbr.reader, hash, number)
+	if block != nil || !ldbr.isAncient(hash, number) {
+		return block
+	}
+	header := ldbr.getAncientHeader(number)
+	if header == nil {
+		return nil
+	}
+	data, err := ldbr.ancients.Ancient(FreezerBodiesTable, number)
+	if err != nil {
+		return nil
+	}
+	body := new(types.Body)
+	if err := rlp.DecodeBytes(data, body); err != nil {
+		log.Error("Invalid ancient block body RLP", "number", number, "err", err)
+		return nil
+	}
+	return types.NewBlockWithHeader(header).WithBody(body.Transactions, body.Uncles)
 }
 
 func (ldbr *LevelDatabaseReader) GetBlockNumber(hash common.Hash) *uint64 {
@@ -47,13 +80,109 @@ func (ldbr *LevelDatabaseReader) GetBlockNumber(hash common.Hash) *uint64 {
 }
 
 func (ldbr *LevelDatabaseReader) GetBlockReceipts(hash common.Hash, number uint64) types.Receipts {
-	return rawdb.ReadReceipts(ldbr.reader, hash, number)
+	data, _ := ldbr.reader.Get(blockReceiptsKey(number, hash))
+	if len(data) == 0 && ldbr.isAncient(hash, number) {
+		data, _ = ldbr.ancients.Ancient(FreezerReceiptTable, number)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	block := ldbr.GetBlock(hash, number)
+	if block == nil {
+		return nil
+	}
+	receipts, err := decodeReceipts(data, block, types.MakeSigner(ldbr.getChainConfig(), block.Number()))
+	if err != nil {
+		log.Error("Invalid receipt array RLP", "hash", hash, "err", err)
+		return nil
+	}
+	return receipts
 }
 
 func (ldbr *LevelDatabaseReader) GetCanonicalHash(number uint64) common.Hash {
-	return rawdb.ReadCanonicalHash(ldbr.reader, number)
+	hash := rawdb.ReadCanonicalHash(ldbr.reader, number)
+	if hash != (common.Hash{}) || ldbr.ancients == nil {
+		return hash
+	}
+	data, err := ldbr.ancients.Ancient(FreezerHashTable, number)
+	if err != nil {
+		return common.Hash{}
+	}
+	return common.BytesToHash(data)
+}
+
+func (ldbr *LevelDatabaseReader) GetChainConfig(genesisHash common.Hash) *params.ChainConfig {
+	return rawdb.ReadChainConfig(ldbr.reader, genesisHash)
+}
+
+func (ldbr *LevelDatabaseReader) GetHeader(hash common.Hash, number uint64) *types.Header {
+	header := rawdb.ReadHeader(ldbr.reader, hash, number)
+	if header != nil || !ldbr.isAncient(hash, number) {
+		return header
+	}
+	return ldbr.getAncientHeader(number)
 }
 
 func (ldbr *LevelDatabaseReader) GetHeadBlockHash() common.Hash {
 	return rawdb.ReadHeadBlockHash(ldbr.reader)
 }
+
+func (ldbr *LevelDatabaseReader) GetTotalDifficulty(hash common.Hash, number uint64) *big.Int {
+	td := rawdb.ReadTd(ldbr.reader, hash, number)
+	if td != nil || !ldbr.isAncient(hash, number) {
+		return td
+	}
+	data, err := ldbr.ancients.Ancient(FreezerDifficultyTable, number)
+	if err != nil {
+		return nil
+	}
+	td = new(big.Int)
+	if err := rlp.DecodeBytes(data, td); err != nil {
+		log.Error("Invalid ancient block total difficulty RLP", "number", number, "err", err)
+		return nil
+	}
+	return td
+}
+
+// isAncient reports whether the ancient store holds the given block. Only
+// canonical blocks are frozen, so the stored hash must match.
+func (ldbr *LevelDatabaseReader) isAncient(hash common.Hash, number uint64) bool {
+	if ldbr.ancients == nil || number >= ldbr.ancients.Ancients() {
+		return false
+	}
+	data, err := ldbr.ancients.Ancient(FreezerHashTable, number)
+	return err == nil && common.BytesToHash(data) == hash
+}
+
+func (ldbr *LevelDatabaseReader) getAncientHeader(number uint64) *types.Header {
+	data, err := ldbr.ancients.Ancient(FreezerHeaderTable, number)
+	if err != nil {
+		return nil
+	}
+	header := new(types.Header)
+	if err := rlp.DecodeBytes(data, header); err != nil {
+		log.Error("Invalid ancient block header RLP", "number", number, "err", err)
+		return nil
+	}
+	return header
+}
+
+// getChainConfig returns the chain config stored alongside the genesis
+// block, which determines the signer used to recover contract creators.
+func (ldbr *LevelDatabaseReader) getChainConfig() *params.ChainConfig {
+	ldbr.chainConfigOnce.Do(func() {
+		ldbr.chainConfig = ldbr.GetChainConfig(ldbr.GetCanonicalHash(0))
+		if ldbr.chainConfig == nil {
+			ldbr.chainConfig = params.MainnetChainConfig
+		}
+	})
+	return ldbr.chainConfig
+}
+
+// blockReceiptsKey mirrors geth's schema: "r" + number (uint64 big endian) + hash.
+func blockReceiptsKey(number uint64, hash common.Hash) []byte {
+	key := make([]byte, 9, 9+common.HashLength)
+	key[0] = 'r'
+	binary.BigEndian.PutUint64(key[1:9], number)
+	return append(key, hash.Bytes()...)
+}
@@ -0,0 +1,175 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package level_test
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/ethereum/level"
+)
+
+type storedReceipt struct {
+	PostStateOrStatus []byte
+	CumulativeGasUsed uint64
+	Logs              []storedLog
+}
+
+type storedLog struct {
+	Address common.Address
+	Topics  []common.Hash
+	Data    []byte
+}
+
+func encode(value interface{}) []byte {
+	encoded, err := rlp.EncodeToBytes(value)
+	Expect(err).NotTo(HaveOccurred())
+	return encoded
+}
+
+var _ = Describe("Level database reader", func() {
+	var (
+		dir         string
+		db          *ethdb.MemDatabase
+		genesis     *types.Block
+		block       *types.Block
+		sender      common.Address
+		transfer    *types.Transaction
+		creation    *types.Transaction
+		contractLog storedLog
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "ancient")
+		Expect(err).NotTo(HaveOccurred())
+		db = ethdb.NewMemDatabase()
+
+		key, err := crypto.GenerateKey()
+		Expect(err).NotTo(HaveOccurred())
+		sender = crypto.PubkeyToAddress(key.PublicKey)
+		transfer, err = types.SignTx(types.NewTransaction(0, common.HexToAddress("0x1234"), big.NewInt(1), 21000, big.NewInt(1), nil), types.FrontierSigner{}, key)
+		Expect(err).NotTo(HaveOccurred())
+		creation, err = types.SignTx(types.NewContractCreation(1, big.NewInt(0), 100000, big.NewInt(1), []byte{0x60}), types.FrontierSigner{}, key)
+		Expect(err).NotTo(HaveOccurred())
+
+		genesis = types.NewBlockWithHeader(&types.Header{Number: big.NewInt(0), Difficulty: big.NewInt(17179869184)})
+		block = types.NewBlock(&types.Header{Number: big.NewInt(1), ParentHash: genesis.Hash(), Difficulty: big.NewInt(17171480576)}, []*types.Transaction{transfer, creation}, nil, nil)
+		contractLog = storedLog{Address: common.HexToAddress("0xabcd"), Topics: []common.Hash{common.HexToHash("0x01")}, Data: []byte{1}}
+		receipts := []storedReceipt{
+			{PostStateOrStatus: []byte{0x01}, CumulativeGasUsed: 21000, Logs: []storedLog{}},
+			{PostStateOrStatus: []byte{0x01}, CumulativeGasUsed: 71000, Logs: []storedLog{contractLog}},
+		}
+
+		writeFreezer(dir, map[string][][]byte{
+			level.FreezerHeaderTable:     {encode(genesis.Header()), encode(block.Header())},
+			level.FreezerHashTable:       {genesis.Hash().Bytes(), block.Hash().Bytes()},
+			level.FreezerBodiesTable:     {encode(genesis.Body()), encode(block.Body())},
+			level.FreezerReceiptTable:    {encode([]storedReceipt{}), encode(receipts)},
+			level.FreezerDifficultyTable: {encode(genesis.Difficulty()), encode(big.NewInt(34351349760))},
+		})
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	Describe("with an ancient store", func() {
+		var reader *level.LevelDatabaseReader
+
+		BeforeEach(func() {
+			freezer, err := level.OpenFreezer(dir)
+			Expect(err).NotTo(HaveOccurred())
+			reader = level.NewLevelDatabaseReader(db, freezer)
+		})
+
+		It("reads frozen canonical hashes", func() {
+			Expect(reader.GetCanonicalHash(1)).To(Equal(block.Hash()))
+		})
+
+		It("reads frozen headers, blocks and total difficulty", func() {
+			header := reader.GetHeader(block.Hash(), 1)
+			Expect(header).NotTo(BeNil())
+			Expect(header.Hash()).To(Equal(block.Hash()))
+
+			frozenBlock := reader.GetBlock(block.Hash(), 1)
+			Expect(frozenBlock).NotTo(BeNil())
+			Expect(frozenBlock.Hash()).To(Equal(block.Hash()))
+			Expect(frozenBlock.Transactions().Len()).To(Equal(2))
+
+			Expect(reader.GetTotalDifficulty(block.Hash(), 1)).To(Equal(big.NewInt(34351349760)))
+		})
+
+		It("does not return frozen data for a non-canonical hash", func() {
+			Expect(reader.GetBlock(common.HexToHash("0x01"), 1)).To(BeNil())
+			Expect(reader.GetHeader(common.HexToHash("0x01"), 1)).To(BeNil())
+		})
+
+		It("derives the fields omitted from stored receipts", func() {
+			receipts := reader.GetBlockReceipts(block.Hash(), 1)
+
+			Expect(len(receipts)).To(Equal(2))
+			Expect(receipts[0].TxHash).To(Equal(transfer.Hash()))
+			Expect(receipts[0].GasUsed).To(Equal(uint64(21000)))
+			Expect(receipts[0].Status).To(Equal(types.ReceiptStatusSuccessful))
+			Expect(receipts[0].ContractAddress).To(Equal(common.Address{}))
+			Expect(receipts[1].TxHash).To(Equal(creation.Hash()))
+			Expect(receipts[1].GasUsed).To(Equal(uint64(50000)))
+			Expect(receipts[1].ContractAddress).To(Equal(crypto.CreateAddress(sender, 1)))
+			Expect(len(receipts[1].Logs)).To(Equal(1))
+			Expect(receipts[1].Logs[0].Address).To(Equal(contractLog.Address))
+			Expect(receipts[1].Logs[0].TxHash).To(Equal(creation.Hash()))
+			Expect(receipts[1].Logs[0].TxIndex).To(Equal(uint(1)))
+			Expect(receipts[1].Logs[0].BlockHash).To(Equal(block.Hash()))
+			Expect(types.BloomLookup(receipts[1].Bloom, contractLog.Address)).To(BeTrue())
+		})
+	})
+
+	Describe("without an ancient store", func() {
+		It("reads legacy receipts from LevelDB", func() {
+			rawdb.WriteBlock(db, block)
+			legacyReceipt := types.NewReceipt(nil, false, 21000)
+			legacyReceipt.TxHash = transfer.Hash()
+			legacyReceipt.GasUsed = 21000
+			legacyReceipt.Logs = []*types.Log{}
+			rawdb.WriteReceipts(db, block.Hash(), 1, types.Receipts{legacyReceipt, legacyReceipt})
+			reader := level.NewLevelDatabaseReader(db, nil)
+
+			receipts := reader.GetBlockReceipts(block.Hash(), 1)
+
+			Expect(len(receipts)).To(Equal(2))
+			Expect(receipts[0].TxHash).To(Equal(transfer.Hash()))
+			Expect(receipts[0].GasUsed).To(Equal(uint64(21000)))
+		})
+
+		It("does not find frozen blocks", func() {
+			reader := level.NewLevelDatabaseReader(db, nil)
+
+			Expect(reader.GetCanonicalHash(1)).To(Equal(common.Hash{}))
+			Expect(reader.GetBlock(block.Hash(), 1)).To(BeNil())
+		})
+	})
+})
@@ -17,6 +17,8 @@
 package level_test
 
 import (
+	"math/big"
+
 	"github.com/ethereum/go-ethereum/common"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -53,6 +55,19 @@ var _ = Describe("Level database", func() {
 		})
 	})
 
+	Describe("Getting a header", func() {
+		It("converts block number to uint64 and hash to common.Hash to fetch header from reader", func() {
+			mockReader := fakes.NewMockLevelDatabaseReader()
+			ldb := level.NewLevelDatabase(mockReader)
+			blockHash := []byte{5, 4, 3, 2, 1}
+			blockNumber := int64(12345)
+
+			ldb.GetHeader(blockHash, blockNumber)
+
+			mockReader.AssertGetHeaderCalledWith(common.BytesToHash(blockHash), uint64(blockNumber))
+		})
+	})
+
 	Describe("Getting a block's receipts", func() {
 		It("converts block number to uint64 and hash to common.Hash to fetch receipts from reader", func() {
 			mockReader := fakes.NewMockLevelDatabaseReader()
@@ -68,6 +83,21 @@ var _ = Describe("Level database", func() {
 		})
 	})
 
+	Describe("Getting a block's total difficulty", func() {
+		It("converts block number to uint64 and hash to common.Hash to fetch total difficulty from reader", func() {
+			mockReader := fakes.NewMockLevelDatabaseReader()
+			mockReader.SetReturnTotalDifficulty(big.NewInt(100))
+			ldb := level.NewLevelDatabase(mockReader)
+			blockHash := []byte{5, 4, 3, 2, 1}
+			blockNumber := int64(12345)
+
+			td := ldb.GetTotalDifficulty(blockHash, blockNumber)
+
+			mockReader.AssertGetTotalDifficultyCalledWith(common.BytesToHash(blockHash), uint64(blockNumber))
+			Expect(td).To(Equal(big.NewInt(100)))
+		})
+	})
+
 	Describe("Getting the latest block number", func() {
 		It("invokes the database reader to get the latest block number by hash and converts result to int64", func() {
 			mockReader := fakes.NewMockLevelDatabaseReader()
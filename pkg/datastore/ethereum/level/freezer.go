@@ -0,0 +1,233 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package level
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/golang/snappy"
+)
+
+// Tables geth moves finalized chain segments into once they are older than
+// its immutability threshold.
+const (
+	FreezerHeaderTable     = "headers"
+	FreezerHashTable       = "hashes"
+	FreezerBodiesTable     = "bodies"
+	FreezerReceiptTable    = "receipts"
+	FreezerDifficultyTable = "diffs"
+)
+
+var freezerTables = []string{
+	FreezerHeaderTable,
+	FreezerHashTable,
+	FreezerBodiesTable,
+	FreezerReceiptTable,
+	FreezerDifficultyTable,
+}
+
+const freezerIndexEntrySize = 6
+
+var ErrAncientOutOfBounds = errors.New("ancient item out of bounds")
+
+// Freezer is a read-only view of geth's ancient store: flat, append-only
+// files holding one table per kind of chain data, indexed by block number.
+type Freezer struct {
+	tables map[string]*freezerTable
+	frozen uint64
+}
+
+func OpenFreezer(path string) (*Freezer, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("ancient path %s is not a directory", path)
+	}
+	freezer := &Freezer{tables: make(map[string]*freezerTable)}
+	for i, name := range freezerTables {
+		table, err := openFreezerTable(path, name)
+		if err != nil {
+			freezer.Close()
+			return nil, err
+		}
+		freezer.tables[name] = table
+		// geth truncates every table to the shortest one on startup, so
+		// only items present in all of them are considered frozen.
+		if i == 0 || table.items < freezer.frozen {
+			freezer.frozen = table.items
+		}
+	}
+	return freezer, nil
+}
+
+// Ancient returns the raw (decompressed) item stored for a block number.
+func (f *Freezer) Ancient(kind string, number uint64) ([]byte, error) {
+	table, ok := f.tables[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown ancient table %s", kind)
+	}
+	if number >= f.frozen {
+		return nil, ErrAncientOutOfBounds
+	}
+	return table.retrieve(number)
+}
+
+// Ancients returns the number of blocks in the ancient store.
+func (f *Freezer) Ancients() uint64 {
+	return f.frozen
+}
+
+func (f *Freezer) Close() error {
+	var firstErr error
+	for _, table := range f.tables {
+		if err := table.close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+type freezerIndexEntry struct {
+	filenum uint32
+	offset  uint32
+}
+
+func (e *freezerIndexEntry) unmarshalBinary(b []byte) {
+	e.filenum = uint32(binary.BigEndian.Uint16(b[:2]))
+	e.offset = binary.BigEndian.Uint32(b[2:6])
+}
+
+// freezerTable reads a single table. The index holds one entry per item
+// marking where the item ends; an item that does not fit in the remainder
+// of a data file starts at the beginning of the next one. The first entry
+// records the tail of the table, which is non-zero once geth has pruned it.
+type freezerTable struct {
+	path       string
+	name       string
+	compressed bool
+	index      *os.File
+	itemOffset uint64
+	items      uint64
+
+	lock  sync.Mutex
+	files map[uint32]*os.File
+}
+
+func openFreezerTable(path, name string) (*freezerTable, error) {
+	table := &freezerTable{path: path, name: name, compressed: true, files: make(map[uint32]*os.File)}
+	index, err := os.Open(filepath.Join(path, name+".cidx"))
+	if os.IsNotExist(err) {
+		table.compressed = false
+		index, err = os.Open(filepath.Join(path, name+".ridx"))
+	}
+	if err != nil {
+		return nil, err
+	}
+	table.index = index
+	stat, err := index.Stat()
+	if err != nil {
+		index.Close()
+		return nil, err
+	}
+	entries := uint64(stat.Size() / freezerIndexEntrySize)
+	if entries == 0 {
+		return table, nil
+	}
+	var tail freezerIndexEntry
+	if err := table.readIndexEntry(0, &tail); err != nil {
+		index.Close()
+		return nil, err
+	}
+	table.itemOffset = uint64(tail.offset)
+	table.items = table.itemOffset + entries - 1
+	return table, nil
+}
+
+func (t *freezerTable) retrieve(item uint64) ([]byte, error) {
+	if item < t.itemOffset || item >= t.items {
+		return nil, ErrAncientOutOfBounds
+	}
+	position := item - t.itemOffset
+	var start, end freezerIndexEntry
+	if err := t.readIndexEntry(position, &start); err != nil {
+		return nil, err
+	}
+	if err := t.readIndexEntry(position+1, &end); err != nil {
+		return nil, err
+	}
+	startOffset := start.offset
+	if position == 0 || start.filenum != end.filenum {
+		startOffset = 0
+	}
+	if end.offset < startOffset {
+		return nil, fmt.Errorf("corrupt index for %s item %d", t.name, item)
+	}
+	data, err := t.dataFile(end.filenum)
+	if err != nil {
+		return nil, err
+	}
+	blob := make([]byte, end.offset-startOffset)
+	if _, err := data.ReadAt(blob, int64(startOffset)); err != nil {
+		return nil, err
+	}
+	if !t.compressed {
+		return blob, nil
+	}
+	return snappy.Decode(nil, blob)
+}
+
+func (t *freezerTable) readIndexEntry(position uint64, entry *freezerIndexEntry) error {
+	buffer := make([]byte, freezerIndexEntrySize)
+	if _, err := t.index.ReadAt(buffer, int64(position*freezerIndexEntrySize)); err != nil {
+		return err
+	}
+	entry.unmarshalBinary(buffer)
+	return nil
+}
+
+func (t *freezerTable) dataFile(filenum uint32) (*os.File, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if file, ok := t.files[filenum]; ok {
+		return file, nil
+	}
+	extension := "cdat"
+	if !t.compressed {
+		extension = "rdat"
+	}
+	file, err := os.Open(filepath.Join(t.path, fmt.Sprintf("%s.%04d.%s", t.name, filenum, extension)))
+	if err != nil {
+		return nil, err
+	}
+	t.files[filenum] = file
+	return file, nil
+}
+
+func (t *freezerTable) close() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	for _, file := range t.files {
+		file.Close()
+	}
+	return t.index.Close()
+}
@@ -0,0 +1,147 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package level_test
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/snappy"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/ethereum/level"
+)
+
+// writeFreezerTable lays out a table the way geth does, starting a new data
+// file whenever an item would push the current one past maxFileSize.
+func writeFreezerTable(dir, name string, compressed bool, maxFileSize uint32, items [][]byte) {
+	indexExtension, dataExtension := "ridx", "rdat"
+	if compressed {
+		indexExtension, dataExtension = "cidx", "cdat"
+	}
+	index := make([]byte, 6)
+	var filenum, offset uint32
+	data := []byte{}
+	flush := func() {
+		path := filepath.Join(dir, fmt.Sprintf("%s.%04d.%s", name, filenum, dataExtension))
+		Expect(ioutil.WriteFile(path, data, 0644)).To(Succeed())
+	}
+	for _, item := range items {
+		if compressed {
+			item = snappy.Encode(nil, item)
+		}
+		if offset+uint32(len(item)) > maxFileSize {
+			flush()
+			filenum++
+			offset = 0
+			data = []byte{}
+		}
+		data = append(data, item...)
+		offset += uint32(len(item))
+		entry := make([]byte, 6)
+		binary.BigEndian.PutUint16(entry[:2], uint16(filenum))
+		binary.BigEndian.PutUint32(entry[2:], offset)
+		index = append(index, entry...)
+	}
+	flush()
+	Expect(ioutil.WriteFile(filepath.Join(dir, name+"."+indexExtension), index, 0644)).To(Succeed())
+}
+
+func writeFreezer(dir string, tables map[string][][]byte) {
+	for _, name := range []string{level.FreezerHeaderTable, level.FreezerHashTable, level.FreezerBodiesTable, level.FreezerReceiptTable, level.FreezerDifficultyTable} {
+		compressed := name != level.FreezerHashTable && name != level.FreezerDifficultyTable
+		writeFreezerTable(dir, name, compressed, 1<<20, tables[name])
+	}
+}
+
+var _ = Describe("Freezer", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "freezer")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("returns an error if the ancient directory does not exist", func() {
+		_, err := level.OpenFreezer(filepath.Join(dir, "missing"))
+
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("retrieves compressed and uncompressed items", func() {
+		writeFreezer(dir, map[string][][]byte{
+			level.FreezerHeaderTable:     {[]byte("header 0"), []byte("header 1")},
+			level.FreezerHashTable:       {[]byte("hash 0"), []byte("hash 1")},
+			level.FreezerBodiesTable:     {[]byte("body 0"), []byte("body 1")},
+			level.FreezerReceiptTable:    {[]byte("receipts 0"), []byte("receipts 1")},
+			level.FreezerDifficultyTable: {[]byte("td 0"), []byte("td 1")},
+		})
+		freezer, err := level.OpenFreezer(dir)
+		Expect(err).NotTo(HaveOccurred())
+		defer freezer.Close()
+
+		Expect(freezer.Ancients()).To(Equal(uint64(2)))
+		header, err := freezer.Ancient(level.FreezerHeaderTable, 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(header).To(Equal([]byte("header 1")))
+		hash, err := freezer.Ancient(level.FreezerHashTable, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hash).To(Equal([]byte("hash 0")))
+	})
+
+	It("reads items that were moved to the next data file", func() {
+		items := [][]byte{[]byte("aaaa"), []byte("bbbb"), []byte("cccc"), []byte("dddd")}
+		writeFreezerTable(dir, level.FreezerHashTable, false, 10, items)
+		for _, name := range []string{level.FreezerHeaderTable, level.FreezerBodiesTable, level.FreezerReceiptTable, level.FreezerDifficultyTable} {
+			writeFreezerTable(dir, name, false, 10, items)
+		}
+		freezer, err := level.OpenFreezer(dir)
+		Expect(err).NotTo(HaveOccurred())
+		defer freezer.Close()
+
+		for i, expected := range items {
+			item, err := freezer.Ancient(level.FreezerHashTable, uint64(i))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(item).To(Equal(expected))
+		}
+	})
+
+	It("only exposes items present in every table", func() {
+		writeFreezer(dir, map[string][][]byte{
+			level.FreezerHeaderTable:     {[]byte("header 0"), []byte("header 1")},
+			level.FreezerHashTable:       {[]byte("hash 0")},
+			level.FreezerBodiesTable:     {[]byte("body 0"), []byte("body 1")},
+			level.FreezerReceiptTable:    {[]byte("receipts 0"), []byte("receipts 1")},
+			level.FreezerDifficultyTable: {[]byte("td 0"), []byte("td 1")},
+		})
+		freezer, err := level.OpenFreezer(dir)
+		Expect(err).NotTo(HaveOccurred())
+		defer freezer.Close()
+
+		Expect(freezer.Ancients()).To(Equal(uint64(1)))
+		_, err = freezer.Ancient(level.FreezerHeaderTable, 1)
+		Expect(err).To(MatchError(level.ErrAncientOutOfBounds))
+	})
+})
@@ -0,0 +1,109 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package level
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// storedReceiptRLP is the receipt encoding written by geth 1.9 and later,
+// which drops every field that can be derived from the block body.
+type storedReceiptRLP struct {
+	PostStateOrStatus []byte
+	CumulativeGasUsed uint64
+	Logs              []*storedLogRLP
+}
+
+type storedLogRLP struct {
+	Address common.Address
+	Topics  []common.Hash
+	Data    []byte
+}
+
+// decodeReceipts decodes a block's stored receipts in either the legacy
+// encoding or the trimmed one, deriving the omitted fields from the block.
+func decodeReceipts(data []byte, block *types.Block, signer types.Signer) (types.Receipts, error) {
+	legacyReceipts := []*types.ReceiptForStorage{}
+	if err := rlp.DecodeBytes(data, &legacyReceipts); err == nil {
+		receipts := make(types.Receipts, len(legacyReceipts))
+		for i, receipt := range legacyReceipts {
+			receipts[i] = (*types.Receipt)(receipt)
+		}
+		return receipts, nil
+	}
+
+	storedReceipts := []*storedReceiptRLP{}
+	if err := rlp.DecodeBytes(data, &storedReceipts); err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("missing block body for receipts")
+	}
+	transactions := block.Transactions()
+	if len(transactions) != len(storedReceipts) {
+		return nil, fmt.Errorf("block %d has %d transactions but %d receipts", block.NumberU64(), len(transactions), len(storedReceipts))
+	}
+	receipts := make(types.Receipts, len(storedReceipts))
+	var previousCumulativeGasUsed uint64
+	var logIndex uint
+	for i, stored := range storedReceipts {
+		transaction := transactions[i]
+		receipt := &types.Receipt{
+			CumulativeGasUsed: stored.CumulativeGasUsed,
+			GasUsed:           stored.CumulativeGasUsed - previousCumulativeGasUsed,
+			TxHash:            transaction.Hash(),
+		}
+		previousCumulativeGasUsed = stored.CumulativeGasUsed
+		switch {
+		case bytes.Equal(stored.PostStateOrStatus, []byte{0x01}):
+			receipt.Status = types.ReceiptStatusSuccessful
+		case len(stored.PostStateOrStatus) == 0:
+			receipt.Status = types.ReceiptStatusFailed
+		default:
+			receipt.PostState = stored.PostStateOrStatus
+		}
+		if transaction.To() == nil {
+			sender, err := types.Sender(signer, transaction)
+			if err != nil {
+				return nil, err
+			}
+			receipt.ContractAddress = crypto.CreateAddress(sender, transaction.Nonce())
+		}
+		receipt.Logs = make([]*types.Log, len(stored.Logs))
+		for j, storedLog := range stored.Logs {
+			receipt.Logs[j] = &types.Log{
+				Address:     storedLog.Address,
+				Topics:      storedLog.Topics,
+				Data:        storedLog.Data,
+				BlockNumber: block.NumberU64(),
+				TxHash:      receipt.TxHash,
+				TxIndex:     uint(i),
+				BlockHash:   block.Hash(),
+				Index:       logIndex,
+			}
+			logIndex++
+		}
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+		receipts[i] = receipt
+	}
+	return receipts, nil
+}
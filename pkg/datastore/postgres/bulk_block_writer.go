@@ -0,0 +1,282 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package postgres
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+)
+
+var (
+	blockColumns       = []string{"eth_node_id", "number", "gaslimit", "gasused", "time", "difficulty", "hash", "nonce", "parenthash", "size", "uncle_hash", "is_final", "miner", "extra_data", "reward", "uncles_reward", "eth_node_fingerprint", "total_difficulty"}
+	uncleColumns       = []string{"block_id", "hash", "number", "miner", "reward"}
+	transactionColumns = []string{"block_id", "block_number", "hash", "nonce", "tx_to", "tx_from", "gaslimit", "gasprice", "value", "input_data", "tx_index"}
+	receiptColumns     = []string{"contract_address", "tx_hash", "cumulative_gas_used", "gas_used", "state_root", "status", "block_id", "block_number", "bloom"}
+	logColumns         = []string{"block_number", "address", "tx_hash", "index", "topic0", "topic1", "topic2", "topic3", "data", "receipt_id", "block_hash", "removed"}
+)
+
+// BulkBlockWriter persists batches of blocks, along with their uncles, transactions,
+// receipts and logs, with one COPY per table inside a single database transaction.
+// Receipts are taken from each transaction's Receipt.
+type BulkBlockWriter struct {
+	db *DB
+}
+
+func NewBulkBlockWriter(db *DB) *BulkBlockWriter {
+	return &BulkBlockWriter{db: db}
+}
+
+// WriteBlocks skips blocks already stored with the same hash and replaces those
+// stored with a different one, matching BlockRepository.CreateOrUpdateBlock.
+func (writer *BulkBlockWriter) WriteBlocks(blocks []core.Block) error {
+	if len(blocks) == 0 {
+		return nil
+	}
+	return writer.WriteBlocksWith(blocks, nil)
+}
+
+// WriteBlocksWith writes blocks as WriteBlocks does and calls write, when given, inside the same
+// transaction, so that rows recorded alongside the blocks are committed or rolled back with them.
+func (writer *BulkBlockWriter) WriteBlocksWith(blocks []core.Block, write func(tx *sqlx.Tx) error) error {
+	if len(blocks) > 0 {
+		first, last := blockRange(blocks)
+		err := writer.db.EnsureBlockPartitions(first, last)
+		if err != nil {
+			return err
+		}
+	}
+	tx, err := writer.db.Beginx()
+	if err != nil {
+		return err
+	}
+	err = writer.writeBlocks(tx, blocks)
+	if err == nil && write != nil {
+		err = write(tx)
+	}
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (writer *BulkBlockWriter) writeBlocks(tx *sqlx.Tx, blocks []core.Block) error {
+	blocks, err := writer.removeChangedBlocks(tx, blocks)
+	if err != nil || len(blocks) == 0 {
+		return err
+	}
+	blockIds, err := writer.copyBlocks(tx, blocks)
+	if err != nil {
+		return err
+	}
+	var uncleRows, transactionRows, receiptRows [][]interface{}
+	for _, block := range blocks {
+		blockId := blockIds[block.Hash]
+		for _, uncle := range block.Uncles {
+			uncleRows = append(uncleRows, []interface{}{blockId, uncle.Hash, uncle.Number, uncle.Miner, nullStringToZero(uncle.Reward)})
+		}
+		for _, transaction := range block.Transactions {
+			transactionRows = append(transactionRows, []interface{}{blockId, block.Number, transaction.Hash, transaction.Nonce, transaction.To, transaction.From, transaction.GasLimit, nullStringToZero(transaction.GasPrice), nullStringToZero(transaction.Value), transaction.Data, transaction.TxIndex})
+			receipt := transaction.Receipt
+			if receipt.TxHash != "" {
+				receiptRows = append(receiptRows, []interface{}{receipt.ContractAddress, receipt.TxHash, receipt.CumulativeGasUsed, receipt.GasUsed, receipt.StateRoot, receipt.Status, blockId, block.Number, receipt.Bloom})
+			}
+		}
+	}
+	err = copyRows(tx, "uncles", uncleColumns, uncleRows)
+	if err != nil {
+		return err
+	}
+	err = copyRows(tx, "transactions", transactionColumns, transactionRows)
+	if err != nil {
+		return err
+	}
+	err = copyRows(tx, "receipts", receiptColumns, receiptRows)
+	if err != nil {
+		return err
+	}
+	return writer.copyLogs(tx, blocks, blockIds)
+}
+
+// removeChangedBlocks deletes stored blocks whose hash differs from the incoming
+// block at the same number, and returns the blocks that still need writing.
+func (writer *BulkBlockWriter) removeChangedBlocks(tx *sqlx.Tx, blocks []core.Block) ([]core.Block, error) {
+	numbers := make([]int64, len(blocks))
+	for i, block := range blocks {
+		numbers[i] = block.Number
+	}
+	var stored []struct {
+		Number int64
+		Hash   string
+	}
+	err := tx.Select(&stored,
+		`SELECT number, hash FROM blocks WHERE eth_node_id = $1 AND number = ANY($2)`,
+		writer.db.NodeID, pq.Array(numbers))
+	if err != nil {
+		return nil, err
+	}
+	storedHashes := make(map[int64]string, len(stored))
+	for _, block := range stored {
+		storedHashes[block.Number] = block.Hash
+	}
+	var changed []int64
+	var remaining []core.Block
+	for _, block := range blocks {
+		storedHash, ok := storedHashes[block.Number]
+		if ok && storedHash == block.Hash {
+			continue
+		}
+		if ok {
+			changed = append(changed, block.Number)
+		}
+		remaining = append(remaining, block)
+	}
+	if len(changed) > 0 {
+		// Delete receipts in one statement first: cascading from blocks would fire the receipt
+		// and log triggers once per block
+		_, err = tx.Exec(`DELETE FROM receipts USING blocks
+                WHERE receipts.block_id = blocks.id AND receipts.block_number = ANY($2)
+                  AND blocks.eth_node_id = $1 AND blocks.number = ANY($2)`,
+			writer.db.NodeID, pq.Array(changed))
+		if err != nil {
+			return nil, ErrDBDeleteFailed
+		}
+		_, err = tx.Exec(`DELETE FROM blocks WHERE eth_node_id = $1 AND number = ANY($2)`,
+			writer.db.NodeID, pq.Array(changed))
+		if err != nil {
+			return nil, ErrDBDeleteFailed
+		}
+	}
+	return remaining, nil
+}
+
+func (writer *BulkBlockWriter) copyBlocks(tx *sqlx.Tx, blocks []core.Block) (map[string]int64, error) {
+	rows := make([][]interface{}, len(blocks))
+	numbers := make([]int64, len(blocks))
+	for i, block := range blocks {
+		var totalDifficulty interface{}
+		if block.TotalDifficulty != "" {
+			totalDifficulty = block.TotalDifficulty
+		}
+		rows[i] = []interface{}{writer.db.NodeID, block.Number, block.GasLimit, block.GasUsed, block.Time, nullStringToZero(block.Difficulty), block.Hash, block.Nonce, block.ParentHash, block.Size, block.UncleHash, block.IsFinal, block.Miner, block.ExtraData, nullStringToZero(block.Reward), nullStringToZero(block.UnclesReward), writer.db.Node.ID, totalDifficulty}
+		numbers[i] = block.Number
+	}
+	err := copyRows(tx, "blocks", blockColumns, rows)
+	if err != nil {
+		return nil, err
+	}
+	var ids []struct {
+		Id   int64
+		Hash string
+	}
+	err = tx.Select(&ids,
+		`SELECT id, hash FROM blocks WHERE eth_node_id = $1 AND number = ANY($2)`,
+		writer.db.NodeID, pq.Array(numbers))
+	if err != nil {
+		return nil, err
+	}
+	blockIds := make(map[string]int64, len(ids))
+	for _, id := range ids {
+		blockIds[id.Hash] = id.Id
+	}
+	return blockIds, nil
+}
+
+// copyLogs looks up the ids of the receipts just copied so logs can reference them
+func (writer *BulkBlockWriter) copyLogs(tx *sqlx.Tx, blocks []core.Block, blockIds map[string]int64) error {
+	ids := make([]int64, 0, len(blockIds))
+	for _, id := range blockIds {
+		ids = append(ids, id)
+	}
+	first, last := blockRange(blocks)
+	var receipts []struct {
+		Id      int64
+		BlockId int64  `db:"block_id"`
+		TxHash  string `db:"tx_hash"`
+	}
+	err := tx.Select(&receipts,
+		`SELECT id, block_id, tx_hash FROM receipts
+                WHERE block_id = ANY($1) AND block_number BETWEEN $2 AND $3`,
+		pq.Array(ids), first, last)
+	if err != nil {
+		return err
+	}
+	type receiptKey struct {
+		blockId int64
+		txHash  string
+	}
+	receiptIds := make(map[receiptKey]int64, len(receipts))
+	for _, receipt := range receipts {
+		receiptIds[receiptKey{receipt.BlockId, receipt.TxHash}] = receipt.Id
+	}
+	var rows [][]interface{}
+	for _, block := range blocks {
+		for _, transaction := range block.Transactions {
+			receiptId := receiptIds[receiptKey{blockIds[block.Hash], transaction.Receipt.TxHash}]
+			for _, log := range transaction.Receipt.Logs {
+				rows = append(rows, []interface{}{log.BlockNumber, log.Address, log.TxHash, log.Index, log.Topics[0], log.Topics[1], log.Topics[2], log.Topics[3], log.Data, receiptId, log.BlockHash, log.Removed})
+			}
+		}
+	}
+	return copyRows(tx, "logs", logColumns, rows)
+}
+
+func copyRows(tx *sqlx.Tx, table string, columns []string, rows [][]interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	stmt, err := tx.Prepare(pq.CopyIn(table, columns...))
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		_, err = stmt.Exec(row...)
+		if err != nil {
+			stmt.Close()
+			return ErrDBInsertFailed
+		}
+	}
+	_, err = stmt.Exec()
+	if err != nil {
+		stmt.Close()
+		return ErrDBInsertFailed
+	}
+	return stmt.Close()
+}
+
+func blockRange(blocks []core.Block) (first, last int64) {
+	first, last = blocks[0].Number, blocks[0].Number
+	for _, block := range blocks {
+		if block.Number < first {
+			first = block.Number
+		}
+		if block.Number > last {
+			last = block.Number
+		}
+	}
+	return first, last
+}
+
+// Unset numerics are stored as zero rather than failing the cast
+func nullStringToZero(s string) string {
+	if s == "" {
+		return "0"
+	}
+	return s
+}
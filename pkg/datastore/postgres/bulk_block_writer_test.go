@@ -0,0 +1,120 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package postgres_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/test_config"
+)
+
+var _ = Describe("Bulk block writer", func() {
+	var (
+		db              *postgres.DB
+		blockRepository *repositories.BlockRepository
+		writer          *postgres.BulkBlockWriter
+	)
+
+	BeforeEach(func() {
+		db = test_config.NewTestDB(test_config.NewTestNode())
+		test_config.CleanTestDB(db)
+		blockRepository = repositories.NewBlockRepository(db)
+		writer = postgres.NewBulkBlockWriter(db)
+	})
+
+	It("writes blocks with their uncles, transactions, receipts and logs", func() {
+		log := core.Log{
+			BlockNumber: 2,
+			BlockHash:   "0x2",
+			TxHash:      "0xb",
+			Address:     "0xcontract",
+			Topics:      core.Topics{"0xtopic0", "0xtopic1"},
+			Index:       0,
+			Data:        "0xdata",
+		}
+		blocks := []core.Block{
+			{Number: 1, Hash: "0x1", Difficulty: "123456789012345678901234567890", TotalDifficulty: "1000"},
+			{
+				Number: 2,
+				Hash:   "0x2",
+				Reward: "5000000000000000000",
+				Uncles: []core.Uncle{{Hash: "0xu", Number: 1, Miner: "0xminer", Reward: "4375000000000000000"}},
+				Transactions: []core.Transaction{
+					{Hash: "0xa", GasPrice: "1", Value: "2", TxIndex: 0},
+					{Hash: "0xb", GasPrice: "1", Value: "2", TxIndex: 1, Receipt: core.Receipt{TxHash: "0xb", GasUsed: 21000, Status: 1, Logs: []core.Log{log}}},
+				},
+			},
+		}
+
+		err := writer.WriteBlocks(blocks)
+
+		Expect(err).NotTo(HaveOccurred())
+		first, err := blockRepository.GetBlock(1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(first.Difficulty).To(Equal("123456789012345678901234567890"))
+		Expect(first.TotalDifficulty).To(Equal("1000"))
+		second, err := blockRepository.GetBlock(2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(second.TotalDifficulty).To(Equal(""))
+		Expect(len(second.Transactions)).To(Equal(2))
+		Expect(len(second.Uncles)).To(Equal(1))
+		Expect(second.Uncles[0].Reward).To(Equal("4375000000000000000"))
+		receiptRepository := repositories.ReceiptRepository{DB: db}
+		receipt, err := receiptRepository.GetReceipt("0xb")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(receipt.GasUsed).To(Equal(uint64(21000)))
+		logRepository := repositories.LogRepository{DB: db}
+		logs := logRepository.GetLogs("0xcontract", 2)
+		Expect(logs).To(Equal([]core.Log{log}))
+	})
+
+	It("skips blocks already stored with the same hash", func() {
+		_, err := blockRepository.CreateOrUpdateBlock(core.Block{Number: 1, Hash: "0x1"})
+		Expect(err).NotTo(HaveOccurred())
+
+		err = writer.WriteBlocks([]core.Block{{Number: 1, Hash: "0x1"}, {Number: 2, Hash: "0x2"}})
+
+		Expect(err).NotTo(HaveOccurred())
+		var count int
+		err = db.Get(&count, `SELECT COUNT(*) FROM blocks`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(count).To(Equal(2))
+	})
+
+	It("replaces blocks stored with a different hash", func() {
+		_, err := blockRepository.CreateOrUpdateBlock(core.Block{Number: 1, Hash: "0xold", Transactions: []core.Transaction{{Hash: "0xa"}}})
+		Expect(err).NotTo(HaveOccurred())
+
+		err = writer.WriteBlocks([]core.Block{{Number: 1, Hash: "0xnew"}})
+
+		Expect(err).NotTo(HaveOccurred())
+		block, err := blockRepository.GetBlock(1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(block.Hash).To(Equal("0xnew"))
+		Expect(block.Transactions).To(BeEmpty())
+	})
+
+	It("writes nothing for an empty batch", func() {
+		err := writer.WriteBlocks(nil)
+
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -0,0 +1,113 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package postgres
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+)
+
+var headerColumns = []string{"block_number", "hash", "block_timestamp", "raw", "eth_node_id", "eth_node_fingerprint", "total_difficulty"}
+
+// BulkHeaderWriter persists batches of headers with one COPY inside a single database transaction
+type BulkHeaderWriter struct {
+	db *DB
+}
+
+func NewBulkHeaderWriter(db *DB) *BulkHeaderWriter {
+	return &BulkHeaderWriter{db: db}
+}
+
+// WriteHeaders skips headers already stored with the same hash and replaces those
+// stored with a different one, matching HeaderRepository.CreateOrUpdateHeader.
+func (writer *BulkHeaderWriter) WriteHeaders(headers []core.Header) error {
+	if len(headers) == 0 {
+		return nil
+	}
+	tx, err := writer.db.Beginx()
+	if err != nil {
+		return err
+	}
+	err = writer.writeHeaders(tx, headers)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (writer *BulkHeaderWriter) writeHeaders(tx *sqlx.Tx, headers []core.Header) error {
+	headers, err := writer.removeChangedHeaders(tx, headers)
+	if err != nil {
+		return err
+	}
+	rows := make([][]interface{}, len(headers))
+	for i, header := range headers {
+		var totalDifficulty interface{}
+		if header.TotalDifficulty != "" {
+			totalDifficulty = header.TotalDifficulty
+		}
+		// raw is JSONB, which COPY would not accept as bytea
+		rows[i] = []interface{}{header.BlockNumber, header.Hash, nullStringToZero(header.Timestamp), string(header.Raw), writer.db.NodeID, writer.db.Node.ID, totalDifficulty}
+	}
+	return copyRows(tx, "headers", headerColumns, rows)
+}
+
+// removeChangedHeaders deletes stored headers whose hash differs from the incoming
+// header at the same number, and returns the headers that still need writing.
+func (writer *BulkHeaderWriter) removeChangedHeaders(tx *sqlx.Tx, headers []core.Header) ([]core.Header, error) {
+	numbers := make([]int64, len(headers))
+	for i, header := range headers {
+		numbers[i] = header.BlockNumber
+	}
+	var stored []struct {
+		BlockNumber int64 `db:"block_number"`
+		Hash        string
+	}
+	err := tx.Select(&stored,
+		`SELECT block_number, hash FROM headers WHERE eth_node_fingerprint = $1 AND block_number = ANY($2)`,
+		writer.db.Node.ID, pq.Array(numbers))
+	if err != nil {
+		return nil, err
+	}
+	storedHashes := make(map[int64]string, len(stored))
+	for _, header := range stored {
+		storedHashes[header.BlockNumber] = header.Hash
+	}
+	var changed []int64
+	var remaining []core.Header
+	for _, header := range headers {
+		storedHash, ok := storedHashes[header.BlockNumber]
+		if ok && storedHash == header.Hash {
+			continue
+		}
+		if ok {
+			changed = append(changed, header.BlockNumber)
+		}
+		remaining = append(remaining, header)
+	}
+	if len(changed) > 0 {
+		_, err = tx.Exec(`DELETE FROM headers WHERE eth_node_fingerprint = $1 AND block_number = ANY($2)`,
+			writer.db.Node.ID, pq.Array(changed))
+		if err != nil {
+			return nil, ErrDBDeleteFailed
+		}
+	}
+	return remaining, nil
+}
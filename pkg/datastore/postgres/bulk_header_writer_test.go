@@ -0,0 +1,102 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package postgres_test
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/test_config"
+)
+
+var _ = Describe("Bulk header writer", func() {
+	var (
+		db               *postgres.DB
+		headerRepository repositories.HeaderRepository
+		writer           *postgres.BulkHeaderWriter
+		rawHeader        []byte
+	)
+
+	BeforeEach(func() {
+		db = test_config.NewTestDB(test_config.NewTestNode())
+		test_config.CleanTestDB(db)
+		headerRepository = repositories.NewHeaderRepository(db)
+		writer = postgres.NewBulkHeaderWriter(db)
+		var err error
+		rawHeader, err = json.Marshal(types.Header{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("writes headers with their raw json and total difficulty", func() {
+		headers := []core.Header{
+			{BlockNumber: 1, Hash: "0x1", Raw: rawHeader, Timestamp: "1500000000", TotalDifficulty: "1000"},
+			{BlockNumber: 2, Hash: "0x2", Raw: rawHeader, Timestamp: "1500000015"},
+		}
+
+		err := writer.WriteHeaders(headers)
+
+		Expect(err).NotTo(HaveOccurred())
+		header, err := headerRepository.GetHeader(1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(header.Hash).To(Equal("0x1"))
+		Expect(header.Raw).To(MatchJSON(rawHeader))
+		Expect(header.TotalDifficulty).To(Equal("1000"))
+		header, err = headerRepository.GetHeader(2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(header.TotalDifficulty).To(BeEmpty())
+	})
+
+	It("skips headers already stored with the same hash", func() {
+		_, err := headerRepository.CreateOrUpdateHeader(core.Header{BlockNumber: 1, Hash: "0x1", Raw: rawHeader, Timestamp: "1500000000"})
+		Expect(err).NotTo(HaveOccurred())
+
+		err = writer.WriteHeaders([]core.Header{
+			{BlockNumber: 1, Hash: "0x1", Raw: rawHeader, Timestamp: "1500000000"},
+			{BlockNumber: 2, Hash: "0x2", Raw: rawHeader, Timestamp: "1500000015"},
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		var count int
+		err = db.Get(&count, `SELECT COUNT(*) FROM headers`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(count).To(Equal(2))
+	})
+
+	It("replaces headers stored with a different hash", func() {
+		_, err := headerRepository.CreateOrUpdateHeader(core.Header{BlockNumber: 1, Hash: "0xold", Raw: rawHeader, Timestamp: "1500000000"})
+		Expect(err).NotTo(HaveOccurred())
+
+		err = writer.WriteHeaders([]core.Header{{BlockNumber: 1, Hash: "0xnew", Raw: rawHeader, Timestamp: "1500000000"}})
+
+		Expect(err).NotTo(HaveOccurred())
+		header, err := headerRepository.GetHeader(1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(header.Hash).To(Equal("0xnew"))
+	})
+
+	It("writes nothing for an empty batch", func() {
+		err := writer.WriteHeaders(nil)
+
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -0,0 +1,95 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package migrations_test
+
+import (
+	"testing/fstest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/db"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/migrations"
+)
+
+var _ = Describe("Loading migrations", func() {
+	It("orders migrations by version and pairs up and down files", func() {
+		files := fstest.MapFS{
+			"20181018120000_second.up.sql":   {Data: []byte("CREATE TABLE second ();")},
+			"20181018120000_second.down.sql": {Data: []byte("DROP TABLE second;")},
+			"1509119369_first.up.sql":        {Data: []byte("CREATE TABLE first ();")},
+			"README.md":                      {Data: []byte("not a migration")},
+		}
+
+		loaded, err := migrations.Load(files)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loaded).To(Equal([]migrations.Migration{
+			{Version: 1509119369, Name: "first", Up: "CREATE TABLE first ();"},
+			{Version: 20181018120000, Name: "second", Up: "CREATE TABLE second ();", Down: "DROP TABLE second;"},
+		}))
+	})
+
+	It("returns an error when a migration has no up file", func() {
+		files := fstest.MapFS{"1_first.down.sql": {Data: []byte("DROP TABLE first;")}}
+
+		_, err := migrations.Load(files)
+
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns an error when two migrations share a version", func() {
+		files := fstest.MapFS{
+			"1_first.up.sql":  {Data: []byte("CREATE TABLE first ();")},
+			"1_second.up.sql": {Data: []byte("CREATE TABLE second ();")},
+		}
+
+		_, err := migrations.Load(files)
+
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("loads the migrations compiled into the binary", func() {
+		loaded, err := migrations.Load(db.Migrations())
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(len(loaded)).To(BeNumerically(">", 1))
+		Expect(loaded[0].Version).To(Equal(int64(1508943247)))
+		for _, migration := range loaded {
+			Expect(migration.Down).NotTo(BeEmpty(), migration.Name)
+		}
+	})
+})
+
+var _ = Describe("Registering plugins", func() {
+	It("adds plugin migrations after the core migrations", func() {
+		migrations.RegisterPlugin("example_plugin", fstest.MapFS{})
+
+		sources := migrations.Sources()
+
+		Expect(sources[0]).To(Equal(migrations.Core))
+		Expect(sources[len(sources)-1].Name).To(Equal("example_plugin"))
+		Expect(sources[len(sources)-1].Table).To(Equal("example_plugin_schema_migrations"))
+	})
+
+	It("panics on invalid or duplicate names", func() {
+		migrations.RegisterPlugin("duplicate_plugin", fstest.MapFS{})
+
+		Expect(func() { migrations.RegisterPlugin("duplicate_plugin", fstest.MapFS{}) }).To(Panic())
+		Expect(func() { migrations.RegisterPlugin("Invalid-Name", fstest.MapFS{}) }).To(Panic())
+	})
+})
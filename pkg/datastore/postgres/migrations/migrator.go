@@ -0,0 +1,241 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Migrator applies the migrations of one Source, tracking the current version in
+// the source's table the same way the migrate CLI does, so databases migrated
+// with it are picked up where they left off.
+type Migrator struct {
+	db         *sqlx.DB
+	source     Source
+	migrations []Migration
+}
+
+type MigrationStatus struct {
+	Migration
+	Applied bool
+}
+
+func NewMigrator(db *sqlx.DB, source Source) (*Migrator, error) {
+	migrations, err := Load(source.Files)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, source: source, migrations: migrations}, nil
+}
+
+// Version returns the version of the last applied migration, or NilVersion. It only reads,
+// so that checking a read-only or unmigrated database does not write to it.
+func (migrator *Migrator) Version() (int64, bool, error) {
+	tracked, err := migrator.tracked()
+	if err != nil || !tracked {
+		return NilVersion, false, err
+	}
+	return migrator.version(migrator.db)
+}
+
+func (migrator *Migrator) LatestVersion() int64 {
+	if len(migrator.migrations) == 0 {
+		return NilVersion
+	}
+	return migrator.migrations[len(migrator.migrations)-1].Version
+}
+
+func (migrator *Migrator) Status() ([]MigrationStatus, error) {
+	version, _, err := migrator.Version()
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]MigrationStatus, len(migrator.migrations))
+	for i, migration := range migrator.migrations {
+		statuses[i] = MigrationStatus{Migration: migration, Applied: migration.Version <= version}
+	}
+	return statuses, nil
+}
+
+// CheckVersion returns ErrSchemaNotMigrated if no migration has been applied, and
+// ErrSchemaOutOfDate if any has not been
+func (migrator *Migrator) CheckVersion() error {
+	tracked, err := migrator.tracked()
+	if err != nil {
+		return err
+	}
+	if !tracked {
+		return ErrSchemaNotMigrated
+	}
+	version, dirty, err := migrator.version(migrator.db)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return ErrDirtySchema
+	}
+	if version < migrator.LatestVersion() {
+		return ErrSchemaOutOfDate
+	}
+	return nil
+}
+
+// Up applies every migration newer than the current version, returning how many were applied
+func (migrator *Migrator) Up() (int, error) {
+	applied := 0
+	err := migrator.withConn(func(conn *sql.Conn) error {
+		version, dirty, err := migrator.version(conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return ErrDirtySchema
+		}
+		for _, migration := range migrator.migrations {
+			if migration.Version <= version {
+				continue
+			}
+			err = migrator.apply(conn, migration.Version, migration.Up, migration.Version)
+			if err != nil {
+				return fmt.Errorf("migrations: applying %d_%s: %v", migration.Version, migration.Name, err)
+			}
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down rolls back up to steps migrations, returning how many were rolled back
+func (migrator *Migrator) Down(steps int) (int, error) {
+	rolledBack := 0
+	err := migrator.withConn(func(conn *sql.Conn) error {
+		version, dirty, err := migrator.version(conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return ErrDirtySchema
+		}
+		for i := len(migrator.migrations) - 1; i >= 0 && rolledBack < steps; i-- {
+			migration := migrator.migrations[i]
+			if migration.Version > version {
+				continue
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("%v: %d_%s", ErrNoDownMigration, migration.Version, migration.Name)
+			}
+			previous := NilVersion
+			if i > 0 {
+				previous = migrator.migrations[i-1].Version
+			}
+			err = migrator.apply(conn, migration.Version, migration.Down, previous)
+			if err != nil {
+				return fmt.Errorf("migrations: rolling back %d_%s: %v", migration.Version, migration.Name, err)
+			}
+			rolledBack++
+		}
+		return nil
+	})
+	return rolledBack, err
+}
+
+// apply marks the schema dirty at version while running the migration, so a
+// failure part way through is caught by the next run instead of being retried
+func (migrator *Migrator) apply(conn *sql.Conn, version int64, query string, newVersion int64) error {
+	err := migrator.setVersion(conn, version, true)
+	if err != nil {
+		return err
+	}
+	_, err = conn.ExecContext(context.Background(), query)
+	if err != nil {
+		// leave the connection usable if the migration failed inside its own transaction
+		conn.ExecContext(context.Background(), `ROLLBACK`)
+		return err
+	}
+	return migrator.setVersion(conn, newVersion, false)
+}
+
+// withConn runs f on a single connection holding an advisory lock on the
+// source's table, so concurrent runs do not apply the same migration twice
+func (migrator *Migrator) withConn(f func(conn *sql.Conn) error) error {
+	ctx := context.Background()
+	conn, err := migrator.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.ExecContext(ctx, `SELECT pg_advisory_lock(hashtext($1))`, migrator.source.Table)
+	if err != nil {
+		return err
+	}
+	defer conn.ExecContext(ctx, `SELECT pg_advisory_unlock(hashtext($1))`, migrator.source.Table)
+	_, err = conn.ExecContext(ctx, fmt.Sprintf(
+		`CREATE TABLE IF NOT EXISTS %s (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)`,
+		migrator.source.Table))
+	if err != nil {
+		return err
+	}
+	return f(conn)
+}
+
+// tracked is true once the source's version table has been created
+func (migrator *Migrator) tracked() (bool, error) {
+	var tracked bool
+	err := migrator.db.Get(&tracked, `SELECT to_regclass($1) IS NOT NULL`, migrator.source.Table)
+	return tracked, err
+}
+
+type rowQueryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func (migrator *Migrator) version(conn rowQueryer) (int64, bool, error) {
+	var version int64
+	var dirty bool
+	err := conn.QueryRowContext(context.Background(),
+		fmt.Sprintf(`SELECT version, dirty FROM %s LIMIT 1`, migrator.source.Table)).Scan(&version, &dirty)
+	if err == sql.ErrNoRows {
+		return NilVersion, false, nil
+	}
+	return version, dirty, err
+}
+
+func (migrator *Migrator) setVersion(conn *sql.Conn, version int64, dirty bool) error {
+	ctx := context.Background()
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(fmt.Sprintf(`TRUNCATE %s`, migrator.source.Table))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if version != NilVersion {
+		_, err = tx.Exec(fmt.Sprintf(`INSERT INTO %s (version, dirty) VALUES ($1, $2)`, migrator.source.Table), version, dirty)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
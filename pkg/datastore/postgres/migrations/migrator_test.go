@@ -0,0 +1,148 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package migrations_test
+
+import (
+	"testing/fstest"
+
+	"github.com/jmoiron/sqlx"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/config"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/migrations"
+	"github.com/vulcanize/vulcanizedb/test_config"
+)
+
+var _ = Describe("Migrator", func() {
+	var (
+		db       *sqlx.DB
+		source   migrations.Source
+		migrator *migrations.Migrator
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, err = sqlx.Connect("postgres", config.DbConnectionString(test_config.DBConfig))
+		Expect(err).NotTo(HaveOccurred())
+		db.MustExec(`DROP TABLE IF EXISTS migrator_test_schema_migrations, migrator_test_first, migrator_test_second`)
+		source = migrations.Source{
+			Name:  "migrator_test",
+			Table: "migrator_test_schema_migrations",
+			Files: fstest.MapFS{
+				"1_first.up.sql":    {Data: []byte("CREATE TABLE migrator_test_first (id INTEGER);")},
+				"1_first.down.sql":  {Data: []byte("DROP TABLE migrator_test_first;")},
+				"2_second.up.sql":   {Data: []byte("CREATE TABLE migrator_test_second (id INTEGER);")},
+				"2_second.down.sql": {Data: []byte("DROP TABLE migrator_test_second;")},
+			},
+		}
+		migrator, err = migrations.NewMigrator(db, source)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		db.MustExec(`DROP TABLE IF EXISTS migrator_test_schema_migrations, migrator_test_first, migrator_test_second`)
+		db.Close()
+	})
+
+	tableExists := func(table string) bool {
+		var exists bool
+		err := db.Get(&exists, `SELECT to_regclass($1) IS NOT NULL`, table)
+		Expect(err).NotTo(HaveOccurred())
+		return exists
+	}
+
+	It("starts at the nil version", func() {
+		version, dirty, err := migrator.Version()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(version).To(Equal(migrations.NilVersion))
+		Expect(dirty).To(BeFalse())
+		Expect(migrator.CheckVersion()).To(Equal(migrations.ErrSchemaNotMigrated))
+	})
+
+	It("checks the version without writing to the database", func() {
+		Expect(migrator.CheckVersion()).To(Equal(migrations.ErrSchemaNotMigrated))
+
+		Expect(tableExists(source.Table)).To(BeFalse())
+	})
+
+	It("reports a partly migrated schema as out of date", func() {
+		_, err := migrator.Up()
+		Expect(err).NotTo(HaveOccurred())
+		_, err = migrator.Down(1)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(migrator.CheckVersion()).To(Equal(migrations.ErrSchemaOutOfDate))
+	})
+
+	It("applies pending migrations in order", func() {
+		applied, err := migrator.Up()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(applied).To(Equal(2))
+		Expect(tableExists("migrator_test_first")).To(BeTrue())
+		Expect(tableExists("migrator_test_second")).To(BeTrue())
+		version, _, err := migrator.Version()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(version).To(Equal(int64(2)))
+		Expect(migrator.CheckVersion()).To(Succeed())
+	})
+
+	It("does not reapply migrations", func() {
+		_, err := migrator.Up()
+		Expect(err).NotTo(HaveOccurred())
+
+		applied, err := migrator.Up()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(applied).To(BeZero())
+	})
+
+	It("rolls back the given number of migrations", func() {
+		_, err := migrator.Up()
+		Expect(err).NotTo(HaveOccurred())
+
+		rolledBack, err := migrator.Down(1)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rolledBack).To(Equal(1))
+		Expect(tableExists("migrator_test_second")).To(BeFalse())
+		Expect(tableExists("migrator_test_first")).To(BeTrue())
+		statuses, err := migrator.Status()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statuses[0].Applied).To(BeTrue())
+		Expect(statuses[1].Applied).To(BeFalse())
+	})
+
+	It("marks the schema dirty when a migration fails", func() {
+		source.Files.(fstest.MapFS)["3_broken.up.sql"] = &fstest.MapFile{Data: []byte("NOT SQL;")}
+		migrator, err := migrations.NewMigrator(db, source)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = migrator.Up()
+
+		Expect(err).To(HaveOccurred())
+		version, dirty, err := migrator.Version()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(version).To(Equal(int64(3)))
+		Expect(dirty).To(BeTrue())
+		Expect(migrator.CheckVersion()).To(Equal(migrations.ErrDirtySchema))
+		_, err = migrator.Up()
+		Expect(err).To(Equal(migrations.ErrDirtySchema))
+	})
+})
@@ -0,0 +1,83 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package migrations
+
+import (
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/vulcanize/vulcanizedb/db"
+)
+
+// Source is a set of migrations whose version is tracked in its own table
+type Source struct {
+	Name  string
+	Table string
+	Files fs.FS
+}
+
+var Core = Source{Name: "vulcanizedb", Table: "schema_migrations", Files: db.Migrations()}
+
+var (
+	pluginsMutex sync.Mutex
+	plugins      []Source
+	pluginName   = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+)
+
+// RegisterPlugin adds migrations for tables owned by a transformer, applied after the
+// core migrations and tracked in <name>_schema_migrations. It is meant to be called
+// from the init function of the transformer's package and panics on an invalid or
+// duplicate name.
+func RegisterPlugin(name string, files fs.FS) {
+	pluginsMutex.Lock()
+	defer pluginsMutex.Unlock()
+	if !pluginName.MatchString(name) {
+		panic(fmt.Sprintf("migrations: invalid plugin name %q", name))
+	}
+	for _, plugin := range plugins {
+		if plugin.Name == name {
+			panic(fmt.Sprintf("migrations: plugin %q registered twice", name))
+		}
+	}
+	plugins = append(plugins, Source{Name: name, Table: name + "_schema_migrations", Files: files})
+}
+
+// Sources returns the core migrations followed by those of each plugin in registration order
+func Sources() []Source {
+	pluginsMutex.Lock()
+	defer pluginsMutex.Unlock()
+	return append([]Source{Core}, plugins...)
+}
+
+// CheckVersions returns an error naming the first source whose migrations have not all been applied
+func CheckVersions(db *sqlx.DB) error {
+	for _, source := range Sources() {
+		migrator, err := NewMigrator(db, source)
+		if err != nil {
+			return err
+		}
+		err = migrator.CheckVersion()
+		if err != nil {
+			return fmt.Errorf("%s: %v", source.Name, err)
+		}
+	}
+	return nil
+}
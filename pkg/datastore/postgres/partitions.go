@@ -0,0 +1,25 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package postgres
+
+// EnsureBlockPartitions creates any missing partitions of the transactions, receipts
+// and logs tables covering the given block range, so rows for those blocks land in
+// their own partition rather than the default one.
+func (db *DB) EnsureBlockPartitions(startingBlockNumber, endingBlockNumber int64) error {
+	_, err := db.Exec(`SELECT ensure_block_partitions($1, $2)`, startingBlockNumber, endingBlockNumber)
+	return err
+}
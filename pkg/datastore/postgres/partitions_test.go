@@ -0,0 +1,83 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package postgres_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/test_config"
+)
+
+var _ = Describe("Block partitions", func() {
+	var db *postgres.DB
+
+	BeforeEach(func() {
+		db = test_config.NewTestDB(test_config.NewTestNode())
+		test_config.CleanTestDB(db)
+	})
+
+	It("creates partitions of transactions, receipts and logs covering a block range", func() {
+		err := db.EnsureBlockPartitions(2500000, 3100000)
+
+		Expect(err).NotTo(HaveOccurred())
+		for _, partition := range []string{"transactions_2000000", "receipts_3000000", "logs_2000000", "logs_3000000"} {
+			var exists bool
+			err = db.Get(&exists, `SELECT to_regclass('public.' || $1) IS NOT NULL`, partition)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeTrue(), partition)
+		}
+	})
+
+	It("moves rows out of the default partition when their partition is created", func() {
+		_, err := db.Exec(`INSERT INTO logs (block_number, address, tx_hash, index) VALUES (9000000001, '0xcontract', '0xa', 0)`)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = db.EnsureBlockPartitions(9000000001, 9000000001)
+
+		Expect(err).NotTo(HaveOccurred())
+		var partition string
+		err = db.Get(&partition, `SELECT tableoid::regclass::text FROM logs WHERE block_number = 9000000001`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(partition).To(Equal("logs_9000000000"))
+	})
+
+	It("removes logs along with their receipts when a block is removed", func() {
+		blockRepository := repositories.NewBlockRepository(db)
+		block := core.Block{
+			Number: 1,
+			Hash:   "0x1",
+			Transactions: []core.Transaction{{
+				Hash:    "0xa",
+				Receipt: core.Receipt{TxHash: "0xa", Logs: []core.Log{{BlockNumber: 1, TxHash: "0xa", Topics: core.Topics{}}}},
+			}},
+		}
+		_, err := blockRepository.CreateOrUpdateBlock(block)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = db.Exec(`DELETE FROM blocks WHERE number = 1`)
+
+		Expect(err).NotTo(HaveOccurred())
+		var logCount int
+		err = db.Get(&logCount, `SELECT count(*) FROM logs`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(logCount).To(BeZero())
+	})
+})
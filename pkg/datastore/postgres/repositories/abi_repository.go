@@ -0,0 +1,56 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package repositories
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+)
+
+type AbiRepository struct {
+	database *postgres.DB
+}
+
+func NewAbiRepository(database *postgres.DB) AbiRepository {
+	return AbiRepository{database: database}
+}
+
+func (repository AbiRepository) GetAbi(contractAddr string) (string, error) {
+	var abi string
+	err := repository.database.Get(&abi,
+		`SELECT abi FROM contract_abis WHERE contract_address = $1`, strings.ToLower(contractAddr))
+	if err == sql.ErrNoRows {
+		return "", datastore.ErrAbiDoesNotExist(contractAddr)
+	}
+	return abi, err
+}
+
+// CreateAbi stores the ABI for a contract, replacing any stored before. Source records
+// where it came from, e.g. "etherscan".
+func (repository AbiRepository) CreateAbi(contractAddr, abi, source string) error {
+	_, err := repository.database.Exec(
+		`INSERT INTO contract_abis (contract_address, abi, source) VALUES ($1, $2, $3)
+                ON CONFLICT (contract_address) DO UPDATE SET abi = $2, source = $3, created_at = NOW()`,
+		strings.ToLower(contractAddr), abi, source)
+	if err != nil {
+		return postgres.ErrDBInsertFailed
+	}
+	return nil
+}
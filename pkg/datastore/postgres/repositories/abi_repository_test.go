@@ -0,0 +1,72 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package repositories_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/test_config"
+)
+
+var _ = Describe("ABI repository", func() {
+	var (
+		db   *postgres.DB
+		repo repositories.AbiRepository
+	)
+
+	BeforeEach(func() {
+		db = test_config.NewTestDB(test_config.NewTestNode())
+		test_config.CleanTestDB(db)
+		repo = repositories.NewAbiRepository(db)
+	})
+
+	It("stores and returns an ABI regardless of address case", func() {
+		err := repo.CreateAbi("0xABC", `[{"type":"fallback"}]`, "etherscan")
+		Expect(err).NotTo(HaveOccurred())
+
+		abi, err := repo.GetAbi("0xabc")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(abi).To(Equal(`[{"type":"fallback"}]`))
+		var source string
+		err = db.Get(&source, `SELECT source FROM contract_abis WHERE contract_address = '0xabc'`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(source).To(Equal("etherscan"))
+	})
+
+	It("replaces a stored ABI", func() {
+		err := repo.CreateAbi("0xabc", `[]`, "etherscan")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = repo.CreateAbi("0xabc", `[{"type":"fallback"}]`, "manual")
+
+		Expect(err).NotTo(HaveOccurred())
+		abi, err := repo.GetAbi("0xabc")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(abi).To(Equal(`[{"type":"fallback"}]`))
+	})
+
+	It("returns an error when no ABI is stored for the contract", func() {
+		_, err := repo.GetAbi("0xabc")
+
+		Expect(err).To(MatchError(datastore.ErrAbiDoesNotExist("0xabc")))
+	})
+})
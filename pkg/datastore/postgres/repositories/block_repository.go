@@ -29,10 +29,6 @@ import (
 	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
 )
 
-const (
-	blocksFromHeadBeforeFinal = 20
-)
-
 var ErrBlockExists = errors.New("Won't add block that already exists.")
 
 type BlockRepository struct {
@@ -43,8 +39,8 @@ func NewBlockRepository(database *postgres.DB) *BlockRepository {
 	return &BlockRepository{database: database}
 }
 
-func (blockRepository BlockRepository) SetBlocksStatus(chainHead int64) {
-	cutoff := chainHead - blocksFromHeadBeforeFinal
+func (blockRepository BlockRepository) SetBlocksStatus(chainHead, finalityDepth int64) {
+	cutoff := chainHead - finalityDepth
 	blockRepository.database.Exec(`
                   UPDATE blocks SET is_final = TRUE
                   WHERE is_final = FALSE AND number < $1`,
@@ -82,6 +78,64 @@ func (blockRepository BlockRepository) MissingBlockNumbers(startingBlockNumber i
 	return numbers
 }
 
+func (blockRepository BlockRepository) BlockNumbersNeedingRefresh(nodeId string) []int64 {
+	numbers := make([]int64, 0)
+	blockRepository.database.Select(&numbers,
+		`SELECT number
+               FROM blocks
+               WHERE needs_refresh AND eth_node_fingerprint = $1
+               ORDER BY number`,
+		nodeId)
+	return numbers
+}
+
+// RefreshBlock corrects the difficulty, rewards and gas prices of a stored block in place.
+// A block whose hash no longer matches has been reorged out and is replaced instead.
+func (blockRepository BlockRepository) RefreshBlock(block core.Block) error {
+	tx, err := blockRepository.database.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	var blockId int64
+	err = tx.QueryRow(
+		`UPDATE blocks
+                SET difficulty = $1::NUMERIC, reward = $2::NUMERIC, uncles_reward = $3::NUMERIC, needs_refresh = FALSE
+                WHERE number = $4 AND eth_node_id = $5 AND hash = $6
+                RETURNING id`,
+		nullStringToZero(block.Difficulty), nullStringToZero(block.Reward), nullStringToZero(block.UnclesReward), block.Number, blockRepository.database.NodeID, block.Hash).
+		Scan(&blockId)
+	if err == sql.ErrNoRows {
+		tx.Rollback()
+		_, err = blockRepository.CreateOrUpdateBlock(block)
+		return err
+	}
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	for _, transaction := range block.Transactions {
+		_, err = tx.Exec(
+			`UPDATE transactions SET gasprice = $1::NUMERIC
+                WHERE block_id = $2 AND block_number = $3 AND hash = $4`,
+			nullStringToZero(transaction.GasPrice), blockId, block.Number, transaction.Hash)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	for _, uncle := range block.Uncles {
+		_, err = tx.Exec(
+			`UPDATE uncles SET reward = $1::NUMERIC
+                WHERE block_id = $2 AND hash = $3`,
+			nullStringToZero(uncle.Reward), blockId, uncle.Hash)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
 func (blockRepository BlockRepository) GetBlock(blockNumber int64) (core.Block, error) {
 	blockRows := blockRepository.database.QueryRowx(
 		`SELECT id,
@@ -99,7 +153,8 @@ func (blockRepository BlockRepository) GetBlock(blockNumber int64) (core.Block,
                        miner,
                        extra_data,
                        reward,
-                       uncles_reward
+                       uncles_reward,
+                       COALESCE(total_difficulty::TEXT, '') AS total_difficulty
                FROM blocks
                WHERE eth_node_id = $1 AND number = $2`, blockRepository.database.NodeID, blockNumber)
 	savedBlock, err := blockRepository.loadBlock(blockRows)
@@ -116,20 +171,31 @@ func (blockRepository BlockRepository) GetBlock(blockNumber int64) (core.Block,
 
 func (blockRepository BlockRepository) insertBlock(block core.Block) (int64, error) {
 	var blockId int64
+	err := blockRepository.database.EnsureBlockPartitions(block.Number, block.Number)
+	if err != nil {
+		return 0, err
+	}
 	tx, _ := blockRepository.database.BeginTx(context.Background(), nil)
-	err := tx.QueryRow(
+	err = tx.QueryRow(
 		`INSERT INTO blocks
-                (eth_node_id, number, gaslimit, gasused, time, difficulty, hash, nonce, parenthash, size, uncle_hash, is_final, miner, extra_data, reward, uncles_reward, eth_node_fingerprint)
-                VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+                (eth_node_id, number, gaslimit, gasused, time, difficulty, hash, nonce, parenthash, size, uncle_hash, is_final, miner, extra_data, reward, uncles_reward, eth_node_fingerprint, total_difficulty)
+                VALUES ($1, $2, $3, $4, $5, $6::NUMERIC, $7, $8, $9, $10, $11, $12, $13, $14, $15::NUMERIC, $16::NUMERIC, $17, NULLIF($18, '')::NUMERIC)
                 RETURNING id `,
-		blockRepository.database.NodeID, block.Number, block.GasLimit, block.GasUsed, block.Time, block.Difficulty, block.Hash, block.Nonce, block.ParentHash, block.Size, block.UncleHash, block.IsFinal, block.Miner, block.ExtraData, block.Reward, block.UnclesReward, blockRepository.database.Node.ID).
+		blockRepository.database.NodeID, block.Number, block.GasLimit, block.GasUsed, block.Time, nullStringToZero(block.Difficulty), block.Hash, block.Nonce, block.ParentHash, block.Size, block.UncleHash, block.IsFinal, block.Miner, block.ExtraData, nullStringToZero(block.Reward), nullStringToZero(block.UnclesReward), blockRepository.database.Node.ID, block.TotalDifficulty).
 		Scan(&blockId)
 	if err != nil {
 		tx.Rollback()
 		return 0, err
 	}
 	if len(block.Transactions) > 0 {
-		err = blockRepository.createTransactions(tx, blockId, block.Transactions)
+		err = blockRepository.createTransactions(tx, blockId, block.Number, block.Transactions)
+		if err != nil {
+			tx.Rollback()
+			return 0, postgres.ErrDBInsertFailed
+		}
+	}
+	if len(block.Uncles) > 0 {
+		err = blockRepository.createUncles(tx, blockId, block.Uncles)
 		if err != nil {
 			tx.Rollback()
 			return 0, postgres.ErrDBInsertFailed
@@ -139,9 +205,22 @@ func (blockRepository BlockRepository) insertBlock(block core.Block) (int64, err
 	return blockId, nil
 }
 
-func (blockRepository BlockRepository) createTransactions(tx *sql.Tx, blockId int64, transactions []core.Transaction) error {
+func (blockRepository BlockRepository) createTransactions(tx *sql.Tx, blockId int64, blockNumber int64, transactions []core.Transaction) error {
 	for _, transaction := range transactions {
-		err := blockRepository.createTransaction(tx, blockId, transaction)
+		err := blockRepository.createTransaction(tx, blockId, blockNumber, transaction)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (blockRepository BlockRepository) createUncles(tx *sql.Tx, blockId int64, uncles []core.Uncle) error {
+	for _, uncle := range uncles {
+		_, err := tx.Exec(
+			`INSERT INTO uncles (block_id, hash, number, miner, reward)
+                VALUES ($1, $2, $3, $4, $5::NUMERIC)`,
+			blockId, uncle.Hash, uncle.Number, uncle.Miner, nullStringToZero(uncle.Reward))
 		if err != nil {
 			return err
 		}
@@ -159,18 +238,18 @@ func nullStringToZero(s string) string {
 	return s
 }
 
-func (blockRepository BlockRepository) createTransaction(tx *sql.Tx, blockId int64, transaction core.Transaction) error {
+func (blockRepository BlockRepository) createTransaction(tx *sql.Tx, blockId int64, blockNumber int64, transaction core.Transaction) error {
 	_, err := tx.Exec(
 		`INSERT INTO transactions
-       (block_id, hash, nonce, tx_to, tx_from, gaslimit, gasprice, value, input_data)
-       VALUES ($1, $2, $3, $4, $5, $6, $7,  $8::NUMERIC, $9)
+       (block_id, block_number, hash, nonce, tx_to, tx_from, gaslimit, gasprice, value, input_data, tx_index)
+       VALUES ($1, $2, $3, $4, $5, $6, $7, $8::NUMERIC,  $9::NUMERIC, $10, $11)
        RETURNING id`,
-		blockId, transaction.Hash, transaction.Nonce, transaction.To, transaction.From, transaction.GasLimit, transaction.GasPrice, nullStringToZero(transaction.Value), transaction.Data)
+		blockId, blockNumber, transaction.Hash, transaction.Nonce, transaction.To, transaction.From, transaction.GasLimit, nullStringToZero(transaction.GasPrice), nullStringToZero(transaction.Value), transaction.Data, transaction.TxIndex)
 	if err != nil {
 		return err
 	}
 	if hasReceipt(transaction) {
-		receiptId, err := blockRepository.createReceipt(tx, blockId, transaction.Receipt)
+		receiptId, err := blockRepository.createReceipt(tx, blockId, blockNumber, transaction.Receipt)
 		if err != nil {
 			return err
 		}
@@ -192,15 +271,14 @@ func hasReceipt(transaction core.Transaction) bool {
 	return transaction.Receipt.TxHash != ""
 }
 
-func (blockRepository BlockRepository) createReceipt(tx *sql.Tx, blockId int64, receipt core.Receipt) (int, error) {
-	//Not currently persisting log bloom filters
+func (blockRepository BlockRepository) createReceipt(tx *sql.Tx, blockId int64, blockNumber int64, receipt core.Receipt) (int, error) {
 	var receiptId int
 	err := tx.QueryRow(
 		`INSERT INTO receipts
-               (contract_address, tx_hash, cumulative_gas_used, gas_used, state_root, status, block_id)
-               VALUES ($1, $2, $3, $4, $5, $6, $7) 
+               (contract_address, tx_hash, cumulative_gas_used, gas_used, state_root, status, block_id, block_number, bloom)
+               VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
                RETURNING id`,
-		receipt.ContractAddress, receipt.TxHash, receipt.CumulativeGasUsed, receipt.GasUsed, receipt.StateRoot, receipt.Status, blockId).Scan(&receiptId)
+		receipt.ContractAddress, receipt.TxHash, receipt.CumulativeGasUsed, receipt.GasUsed, receipt.StateRoot, receipt.Status, blockId, blockNumber, receipt.Bloom).Scan(&receiptId)
 	if err != nil {
 		return receiptId, err
 	}
@@ -220,10 +298,10 @@ func (blockRepository BlockRepository) getBlockHash(block core.Block) (string, b
 func (blockRepository BlockRepository) createLogs(tx *sql.Tx, logs []core.Log, receiptId int) error {
 	for _, tlog := range logs {
 		_, err := tx.Exec(
-			`INSERT INTO logs (block_number, address, tx_hash, index, topic0, topic1, topic2, topic3, data, receipt_id)
-                VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			`INSERT INTO logs (block_number, address, tx_hash, index, topic0, topic1, topic2, topic3, data, receipt_id, block_hash, removed)
+                VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
                 `,
-			tlog.BlockNumber, tlog.Address, tlog.TxHash, tlog.Index, tlog.Topics[0], tlog.Topics[1], tlog.Topics[2], tlog.Topics[3], tlog.Data, receiptId,
+			tlog.BlockNumber, tlog.Address, tlog.TxHash, tlog.Index, tlog.Topics[0], tlog.Topics[1], tlog.Topics[2], tlog.Topics[3], tlog.Data, receiptId, tlog.BlockHash, tlog.Removed,
 		)
 		if err != nil {
 			return postgres.ErrDBInsertFailed
@@ -266,7 +344,8 @@ func (blockRepository BlockRepository) loadBlock(blockRows *sqlx.Row) (core.Bloc
 				   gaslimit,
 				   gasprice,
 				   value,
-				   input_data
+				   input_data,
+				   COALESCE(tx_index, 0) AS tx_index
             FROM transactions
             WHERE block_id = $1
             ORDER BY hash`, block.ID)
@@ -274,6 +353,14 @@ func (blockRepository BlockRepository) loadBlock(blockRows *sqlx.Row) (core.Bloc
 		return core.Block{}, err
 	}
 	block.Transactions = blockRepository.LoadTransactions(transactionRows)
+	err = blockRepository.database.Select(&block.Uncles, `
+            SELECT hash, number, miner, reward
+            FROM uncles
+            WHERE block_id = $1
+            ORDER BY hash`, block.ID)
+	if err != nil {
+		return core.Block{}, err
+	}
 	return block.Block, nil
 }
 
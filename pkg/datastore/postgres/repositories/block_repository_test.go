@@ -78,24 +78,25 @@ var _ = Describe("Saving blocks", func() {
 		blockTime := int64(1508981640)
 		uncleHash := "x789"
 		blockSize := string("1000")
-		difficulty := int64(10)
-		blockReward := float64(5.132)
-		unclesReward := float64(3.580)
+		difficulty := "10000000000000000000000"
+		blockReward := "5132000000000000000"
+		unclesReward := "3580000000000000000"
 		block := core.Block{
-			Reward:       blockReward,
-			Difficulty:   difficulty,
-			GasLimit:     gasLimit,
-			GasUsed:      gasUsed,
-			Hash:         blockHash,
-			ExtraData:    extraData,
-			Nonce:        blockNonce,
-			Miner:        miner,
-			Number:       blockNumber,
-			ParentHash:   blockParentHash,
-			Size:         blockSize,
-			Time:         blockTime,
-			UncleHash:    uncleHash,
-			UnclesReward: unclesReward,
+			Reward:          blockReward,
+			Difficulty:      difficulty,
+			GasLimit:        gasLimit,
+			GasUsed:         gasUsed,
+			Hash:            blockHash,
+			ExtraData:       extraData,
+			Nonce:           blockNonce,
+			Miner:           miner,
+			Number:          blockNumber,
+			ParentHash:      blockParentHash,
+			Size:            blockSize,
+			Time:            blockTime,
+			UncleHash:       uncleHash,
+			UnclesReward:    unclesReward,
+			TotalDifficulty: "20000000000000000000000",
 		}
 
 		blockRepository.CreateOrUpdateBlock(block)
@@ -116,6 +117,22 @@ var _ = Describe("Saving blocks", func() {
 		Expect(savedBlock.Time).To(Equal(blockTime))
 		Expect(savedBlock.UncleHash).To(Equal(uncleHash))
 		Expect(savedBlock.UnclesReward).To(Equal(unclesReward))
+		Expect(savedBlock.TotalDifficulty).To(Equal("20000000000000000000000"))
+	})
+
+	It("saves the uncles of the block", func() {
+		uncles := []core.Uncle{
+			{Hash: "x111", Number: 122, Miner: "x456", Reward: "4375000000000000000"},
+			{Hash: "x222", Number: 121, Miner: "x789", Reward: "3750000000000000000"},
+		}
+		block := core.Block{Number: 123, Hash: "x123", Uncles: uncles}
+
+		_, err := blockRepository.CreateOrUpdateBlock(block)
+
+		Expect(err).NotTo(HaveOccurred())
+		savedBlock, err := blockRepository.GetBlock(123)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(savedBlock.Uncles).To(Equal(uncles))
 	})
 
 	It("does not find a block when searching for a number that does not exist", func() {
@@ -216,7 +233,7 @@ var _ = Describe("Saving blocks", func() {
 
 	It("saves the attributes associated to a transaction", func() {
 		gasLimit := uint64(5000)
-		gasPrice := int64(3)
+		gasPrice := "20000000000000000000"
 		nonce := uint64(10000)
 		to := "1234567890"
 		from := "0987654321"
@@ -232,6 +249,7 @@ var _ = Describe("Saving blocks", func() {
 			From:     from,
 			Value:    value.String(),
 			Data:     inputData,
+			TxIndex:  7,
 		}
 		block := core.Block{
 			Number:       123,
@@ -251,6 +269,7 @@ var _ = Describe("Saving blocks", func() {
 		Expect(savedTransaction.GasLimit).To(Equal(gasLimit))
 		Expect(savedTransaction.GasPrice).To(Equal(gasPrice))
 		Expect(savedTransaction.Value).To(Equal(value.String()))
+		Expect(savedTransaction.TxIndex).To(Equal(int64(7)))
 	})
 
 	Describe("The missing block numbers", func() {
@@ -330,6 +349,55 @@ var _ = Describe("Saving blocks", func() {
 		})
 	})
 
+	Describe("The canonical chain", func() {
+		It("follows the heaviest chain across nodes", func() {
+			nodeTwo := core.Node{
+				GenesisBlock: "GENESIS",
+				NetworkID:    1,
+				ID:           "x123456",
+				ClientName:   "Geth",
+			}
+			dbTwo := test_config.NewTestDB(nodeTwo)
+			repositoryTwo := repositories.NewBlockRepository(dbTwo)
+			blockRepository.CreateOrUpdateBlock(core.Block{Number: 1, Hash: "xa", ParentHash: "x0", TotalDifficulty: "10"})
+			blockRepository.CreateOrUpdateBlock(core.Block{Number: 2, Hash: "xb", ParentHash: "xa", TotalDifficulty: "20"})
+			repositoryTwo.CreateOrUpdateBlock(core.Block{Number: 1, Hash: "xa", ParentHash: "x0", TotalDifficulty: "10"})
+			repositoryTwo.CreateOrUpdateBlock(core.Block{Number: 2, Hash: "xc", ParentHash: "xa", TotalDifficulty: "21"})
+			repositoryTwo.CreateOrUpdateBlock(core.Block{Number: 3, Hash: "xd", ParentHash: "xc", TotalDifficulty: "31"})
+
+			var hashes []string
+			err := db.Select(&hashes, `SELECT hash FROM canonical_blocks ORDER BY number`)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hashes).To(Equal([]string{"xa", "xc", "xd"}))
+		})
+
+		It("resolves every height past gaps and forks", func() {
+			nodeTwo := core.Node{
+				GenesisBlock: "GENESIS",
+				NetworkID:    1,
+				ID:           "x123456",
+				ClientName:   "Geth",
+			}
+			dbTwo := test_config.NewTestDB(nodeTwo)
+			repositoryTwo := repositories.NewBlockRepository(dbTwo)
+			blockRepository.CreateOrUpdateBlock(core.Block{Number: 1, Hash: "xa", ParentHash: "x0"})
+			blockRepository.CreateOrUpdateBlock(core.Block{Number: 3, Hash: "xc", ParentHash: "xb", TotalDifficulty: "30"})
+			repositoryTwo.CreateOrUpdateBlock(core.Block{Number: 3, Hash: "xd", ParentHash: "xb", TotalDifficulty: "31"})
+			blockRepository.CreateOrUpdateBlock(core.Block{Number: 5, Hash: "xe", ParentHash: "xd", TotalDifficulty: "50"})
+
+			var hashes []string
+			err := db.Select(&hashes, `SELECT hash FROM canonical_blocks ORDER BY number`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hashes).To(Equal([]string{"xa", "xd", "xe"}))
+
+			var hash string
+			err = db.Get(&hash, `SELECT hash FROM canonical_blocks WHERE number = 3`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hash).To(Equal("xd"))
+		})
+	})
+
 	Describe("The block status", func() {
 		It("sets the status of blocks within n-20 of chain HEAD as final", func() {
 			blockNumberOfChainHead := 25
@@ -337,7 +405,7 @@ var _ = Describe("Saving blocks", func() {
 				blockRepository.CreateOrUpdateBlock(core.Block{Number: int64(i), Hash: strconv.Itoa(i)})
 			}
 
-			blockRepository.SetBlocksStatus(int64(blockNumberOfChainHead))
+			blockRepository.SetBlocksStatus(int64(blockNumberOfChainHead), 20)
 
 			blockOne, err := blockRepository.GetBlock(1)
 			Expect(err).ToNot(HaveOccurred())
@@ -346,5 +414,72 @@ var _ = Describe("Saving blocks", func() {
 			Expect(err).ToNot(HaveOccurred())
 			Expect(blockTwo.IsFinal).To(BeFalse())
 		})
+
+		It("uses the given finality depth", func() {
+			blockNumberOfChainHead := 10
+			for i := 0; i < blockNumberOfChainHead; i++ {
+				blockRepository.CreateOrUpdateBlock(core.Block{Number: int64(i), Hash: strconv.Itoa(i)})
+			}
+
+			blockRepository.SetBlocksStatus(int64(blockNumberOfChainHead), 5)
+
+			finalBlock, err := blockRepository.GetBlock(4)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(finalBlock.IsFinal).To(BeTrue())
+			pendingBlock, err := blockRepository.GetBlock(5)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pendingBlock.IsFinal).To(BeFalse())
+		})
+	})
+
+	Describe("Refreshing blocks", func() {
+		It("lists flagged blocks for the node", func() {
+			blockRepository.CreateOrUpdateBlock(core.Block{Number: 1, Hash: "x1"})
+			blockRepository.CreateOrUpdateBlock(core.Block{Number: 2, Hash: "x2"})
+			_, err := db.Exec(`UPDATE blocks SET needs_refresh = TRUE WHERE number = 2`)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(blockRepository.BlockNumbersNeedingRefresh(node.ID)).To(Equal([]int64{2}))
+		})
+
+		It("corrects difficulty, rewards and gas prices in place", func() {
+			block := core.Block{
+				Number:       1,
+				Hash:         "x1",
+				Difficulty:   "-1",
+				Reward:       "-2",
+				Transactions: []core.Transaction{{Hash: "x1234", GasPrice: "-3"}},
+			}
+			blockRepository.CreateOrUpdateBlock(block)
+			_, err := db.Exec(`UPDATE blocks SET needs_refresh = TRUE`)
+			Expect(err).NotTo(HaveOccurred())
+			block.Difficulty = "9223372036854775808"
+			block.Reward = "5000000000000000000"
+			block.Transactions[0].GasPrice = "9223372036854775809"
+
+			err = blockRepository.RefreshBlock(block)
+
+			Expect(err).NotTo(HaveOccurred())
+			savedBlock, err := blockRepository.GetBlock(1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(savedBlock.Difficulty).To(Equal("9223372036854775808"))
+			Expect(savedBlock.Reward).To(Equal("5000000000000000000"))
+			Expect(savedBlock.Transactions[0].GasPrice).To(Equal("9223372036854775809"))
+			Expect(blockRepository.BlockNumbersNeedingRefresh(node.ID)).To(BeEmpty())
+		})
+
+		It("replaces a flagged block that has been reorged out", func() {
+			blockRepository.CreateOrUpdateBlock(core.Block{Number: 1, Hash: "x1", Difficulty: "-1"})
+			_, err := db.Exec(`UPDATE blocks SET needs_refresh = TRUE`)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = blockRepository.RefreshBlock(core.Block{Number: 1, Hash: "x2", Difficulty: "1"})
+
+			Expect(err).NotTo(HaveOccurred())
+			savedBlock, err := blockRepository.GetBlock(1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(savedBlock.Hash).To(Equal("x2"))
+			Expect(blockRepository.BlockNumbersNeedingRefresh(node.ID)).To(BeEmpty())
+		})
 	})
 })
@@ -0,0 +1,54 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package repositories
+
+import (
+	"database/sql"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+)
+
+type CheckpointRepository struct {
+	database *postgres.DB
+}
+
+func NewCheckpointRepository(database *postgres.DB) CheckpointRepository {
+	return CheckpointRepository{database: database}
+}
+
+// GetCheckpoint returns the last block number of an uninterrupted import that began at the
+// starting block number, or the block before it if nothing has been imported yet
+func (repository CheckpointRepository) GetCheckpoint(nodeID string, startingBlockNumber int64) (int64, error) {
+	var lastBlockNumber int64
+	err := repository.database.Get(&lastBlockNumber,
+		`SELECT last_block_number FROM public.cold_import_checkpoints
+		WHERE eth_node_fingerprint = $1 AND starting_block_number = $2`,
+		nodeID, startingBlockNumber)
+	if err == sql.ErrNoRows {
+		return startingBlockNumber - 1, nil
+	}
+	return lastBlockNumber, err
+}
+
+func (repository CheckpointRepository) SetCheckpoint(nodeID string, startingBlockNumber, lastBlockNumber int64) error {
+	_, err := repository.database.Exec(
+		`INSERT INTO public.cold_import_checkpoints (eth_node_fingerprint, starting_block_number, last_block_number)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (eth_node_fingerprint, starting_block_number)
+		DO UPDATE SET last_block_number = $3, updated_at = NOW()`,
+		nodeID, startingBlockNumber, lastBlockNumber)
+	return err
+}
@@ -0,0 +1,67 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package repositories_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/test_config"
+)
+
+var _ = Describe("Checkpoint repository", func() {
+	var (
+		db   *postgres.DB
+		repo repositories.CheckpointRepository
+	)
+
+	BeforeEach(func() {
+		db = test_config.NewTestDB(core.Node{ID: "EthNodeFingerprint"})
+		test_config.CleanTestDB(db)
+		repo = repositories.NewCheckpointRepository(db)
+	})
+
+	It("returns the block before the starting block when there is no checkpoint", func() {
+		checkpoint, err := repo.GetCheckpoint("EthNodeFingerprint", 100)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checkpoint).To(Equal(int64(99)))
+	})
+
+	It("returns the latest checkpoint for the starting block", func() {
+		Expect(repo.SetCheckpoint("EthNodeFingerprint", 100, 150)).To(Succeed())
+		Expect(repo.SetCheckpoint("EthNodeFingerprint", 100, 200)).To(Succeed())
+		Expect(repo.SetCheckpoint("EthNodeFingerprint", 0, 50)).To(Succeed())
+
+		checkpoint, err := repo.GetCheckpoint("EthNodeFingerprint", 100)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checkpoint).To(Equal(int64(200)))
+	})
+
+	It("keeps checkpoints separate per node", func() {
+		Expect(repo.SetCheckpoint("OtherNode", 100, 150)).To(Succeed())
+
+		checkpoint, err := repo.GetCheckpoint("EthNodeFingerprint", 100)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checkpoint).To(Equal(int64(99)))
+	})
+})
@@ -79,7 +79,8 @@ func (contractRepository ContractRepository) addTransactions(contract core.Contr
                    gaslimit,
                    gasprice,
                    value,
-                   input_data
+                   input_data,
+                   COALESCE(tx_index, 0) AS tx_index
             FROM transactions
             WHERE tx_to = $1
             ORDER BY block_id DESC`, contract.Hash)
@@ -50,11 +50,20 @@ func (repository HeaderRepository) CreateOrUpdateHeader(header core.Header) (int
 
 func (repository HeaderRepository) GetHeader(blockNumber int64) (core.Header, error) {
 	var header core.Header
-	err := repository.database.Get(&header, `SELECT block_number, hash, raw FROM headers WHERE block_number = $1 AND eth_node_fingerprint = $2`,
+	err := repository.database.Get(&header, `SELECT block_number, hash, raw, is_final, COALESCE(total_difficulty::TEXT, '') AS total_difficulty FROM headers WHERE block_number = $1 AND eth_node_fingerprint = $2`,
 		blockNumber, repository.database.Node.ID)
 	return header, err
 }
 
+func (repository HeaderRepository) GetHeaders(startingBlockNumber, endingBlockNumber int64) ([]core.Header, error) {
+	headers := make([]core.Header, 0)
+	err := repository.database.Select(&headers, `SELECT id, block_number, hash, raw, block_timestamp, COALESCE(total_difficulty::TEXT, '') AS total_difficulty FROM headers
+		WHERE block_number BETWEEN $1 AND $2 AND eth_node_fingerprint = $3
+		ORDER BY block_number`,
+		startingBlockNumber, endingBlockNumber, repository.database.Node.ID)
+	return headers, err
+}
+
 func (repository HeaderRepository) MissingBlockNumbers(startingBlockNumber, endingBlockNumber int64, nodeID string) []int64 {
 	numbers := make([]int64, 0)
 	repository.database.Select(&numbers, `SELECT all_block_numbers
@@ -67,6 +76,13 @@ func (repository HeaderRepository) MissingBlockNumbers(startingBlockNumber, endi
 	return numbers
 }
 
+func (repository HeaderRepository) SetHeadersStatus(chainHead, finalityDepth int64) error {
+	_, err := repository.database.Exec(`UPDATE headers SET is_final = TRUE
+		WHERE is_final = FALSE AND block_number < $1 AND eth_node_fingerprint = $2`,
+		chainHead-finalityDepth, repository.database.Node.ID)
+	return err
+}
+
 func headerMustBeReplaced(hash string, header core.Header) bool {
 	return hash != header.Hash
 }
@@ -85,8 +101,8 @@ func (repository HeaderRepository) getHeaderHash(header core.Header) (string, er
 func (repository HeaderRepository) insertHeader(header core.Header) (int64, error) {
 	var headerId int64
 	err := repository.database.QueryRowx(
-		`INSERT INTO public.headers (block_number, hash, block_timestamp, raw, eth_node_id, eth_node_fingerprint) VALUES ($1, $2, $3::NUMERIC, $4, $5, $6) RETURNING id`,
-		header.BlockNumber, header.Hash, header.Timestamp, header.Raw, repository.database.NodeID, repository.database.Node.ID).Scan(&headerId)
+		`INSERT INTO public.headers (block_number, hash, block_timestamp, raw, eth_node_id, eth_node_fingerprint, total_difficulty) VALUES ($1, $2, $3::NUMERIC, $4, $5, $6, NULLIF($7, '')::NUMERIC) RETURNING id`,
+		header.BlockNumber, header.Hash, header.Timestamp, header.Raw, repository.database.NodeID, repository.database.Node.ID, header.TotalDifficulty).Scan(&headerId)
 	return headerId, err
 }
 
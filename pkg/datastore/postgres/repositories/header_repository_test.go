@@ -245,6 +245,27 @@ var _ = Describe("Block header repository", func() {
 			Expect(dbHeader.Raw).To(MatchJSON(header.Raw))
 		})
 
+		It("returns the header's total difficulty", func() {
+			node := core.Node{}
+			db := test_config.NewTestDB(node)
+			test_config.CleanTestDB(db)
+			repo := repositories.NewHeaderRepository(db)
+			header := core.Header{
+				BlockNumber:     100,
+				Hash:            common.BytesToHash([]byte{1, 2, 3, 4, 5}).Hex(),
+				Raw:             rawHeader,
+				Timestamp:       timestamp,
+				TotalDifficulty: "10000000000000000000000",
+			}
+			_, err := repo.CreateOrUpdateHeader(header)
+			Expect(err).NotTo(HaveOccurred())
+
+			dbHeader, err := repo.GetHeader(header.BlockNumber)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbHeader.TotalDifficulty).To(Equal(header.TotalDifficulty))
+		})
+
 		It("does not return header for a different node fingerprint", func() {
 			node := core.Node{}
 			db := test_config.NewTestDB(node)
@@ -327,4 +348,28 @@ var _ = Describe("Block header repository", func() {
 			Expect(missingBlockNumbers).To(ConsistOf([]int64{1, 2, 3, 4, 5}))
 		})
 	})
+
+	Describe("The canonical headers", func() {
+		It("resolves every height past gaps and forks", func() {
+			nodeOne := core.Node{ID: "NodeOne"}
+			db := test_config.NewTestDB(nodeOne)
+			test_config.CleanTestDB(db)
+			repoOne := repositories.NewHeaderRepository(db)
+			repoTwo := repositories.NewHeaderRepository(test_config.NewTestDB(core.Node{ID: "NodeTwo"}))
+			_, err = repoOne.CreateOrUpdateHeader(core.Header{BlockNumber: 1, Hash: "xa", Raw: rawHeader, Timestamp: timestamp})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = repoOne.CreateOrUpdateHeader(core.Header{BlockNumber: 3, Hash: "xc", Raw: rawHeader, Timestamp: timestamp, TotalDifficulty: "30"})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = repoTwo.CreateOrUpdateHeader(core.Header{BlockNumber: 3, Hash: "xd", Raw: rawHeader, Timestamp: timestamp, TotalDifficulty: "31"})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = repoOne.CreateOrUpdateHeader(core.Header{BlockNumber: 5, Hash: "xe", Raw: rawHeader, Timestamp: timestamp, TotalDifficulty: "50"})
+			Expect(err).NotTo(HaveOccurred())
+
+			var hashes []string
+			err = db.Select(&hashes, `SELECT hash FROM canonical_headers ORDER BY block_number`)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hashes).To(Equal([]string{"xa", "xd", "xe"}))
+		})
+	})
 })
@@ -33,10 +33,10 @@ func (logRepository LogRepository) CreateLogs(lgs []core.Log, receiptId int64) e
 	tx, _ := logRepository.DB.BeginTx(context.Background(), nil)
 	for _, tlog := range lgs {
 		_, err := tx.Exec(
-			`INSERT INTO logs (block_number, address, tx_hash, index, topic0, topic1, topic2, topic3, data, receipt_id)
-                VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			`INSERT INTO logs (block_number, address, tx_hash, index, topic0, topic1, topic2, topic3, data, receipt_id, block_hash, removed)
+                VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
                 `,
-			tlog.BlockNumber, tlog.Address, tlog.TxHash, tlog.Index, tlog.Topics[0], tlog.Topics[1], tlog.Topics[2], tlog.Topics[3], tlog.Data, receiptId,
+			tlog.BlockNumber, tlog.Address, tlog.TxHash, tlog.Index, tlog.Topics[0], tlog.Topics[1], tlog.Topics[2], tlog.Topics[3], tlog.Data, receiptId, tlog.BlockHash, tlog.Removed,
 		)
 		if err != nil {
 			tx.Rollback()
@@ -57,7 +57,9 @@ func (logRepository LogRepository) GetLogs(address string, blockNumber int64) []
 					  topic1,
 					  topic2,
 					  topic3,
-					  data
+					  data,
+					  block_hash,
+					  removed
 				FROM logs
 				WHERE address = $1 AND block_number = $2
 				ORDER BY block_number DESC`, address, blockNumber)
@@ -72,14 +74,18 @@ func (logRepository LogRepository) loadLogs(logsRows *sql.Rows) []core.Log {
 		var txHash string
 		var index int64
 		var data string
+		var blockHash sql.NullString
+		var removed bool
 		var topics core.Topics
-		logsRows.Scan(&blockNumber, &address, &txHash, &index, &topics[0], &topics[1], &topics[2], &topics[3], &data)
+		logsRows.Scan(&blockNumber, &address, &txHash, &index, &topics[0], &topics[1], &topics[2], &topics[3], &data, &blockHash, &removed)
 		lg := core.Log{
 			BlockNumber: blockNumber,
+			BlockHash:   blockHash.String,
 			TxHash:      txHash,
 			Address:     address,
 			Index:       index,
 			Data:        data,
+			Removed:     removed,
 		}
 		for i, topic := range topics {
 			lg.Topics[i] = topic
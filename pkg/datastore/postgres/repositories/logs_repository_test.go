@@ -58,11 +58,13 @@ var _ = Describe("Logs Repository", func() {
 			Expect(err).NotTo(HaveOccurred())
 			logsRepository.CreateLogs([]core.Log{{
 				BlockNumber: blockNumber,
+				BlockHash:   "x012",
 				Index:       0,
 				Address:     "x123",
 				TxHash:      "x456",
 				Topics:      core.Topics{0: "x777", 1: "x888", 2: "x999"},
 				Data:        "xabc",
+				Removed:     true,
 			}}, receiptId)
 
 			log := logsRepository.GetLogs("x123", blockNumber)
@@ -76,6 +78,8 @@ var _ = Describe("Logs Repository", func() {
 			Expect(log[0].Topics[1]).To(Equal("x888"))
 			Expect(log[0].Topics[2]).To(Equal("x999"))
 			Expect(log[0].Data).To(Equal("xabc"))
+			Expect(log[0].BlockHash).To(Equal("x012"))
+			Expect(log[0].Removed).To(BeTrue())
 		})
 
 		It("returns nil if log does not exist", func() {
@@ -0,0 +1,218 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+)
+
+// pruneBatchSize bounds the number of blocks or headers deleted per statement, so pruning
+// a long history does not hold locks or build up one huge transaction
+const pruneBatchSize = 10000
+
+var ErrInvalidKeepBlocks = errors.New("prune: number of blocks to keep must be positive")
+
+// PruneRepository deletes raw chain data more than a given number of blocks behind the
+// highest stored block, across all nodes.
+type PruneRepository struct {
+	database *postgres.DB
+}
+
+func NewPruneRepository(database *postgres.DB) PruneRepository {
+	return PruneRepository{database: database}
+}
+
+type reference struct {
+	Table  string `db:"table_name"`
+	Column string `db:"column_name"`
+}
+
+// PruneBlocks deletes old blocks along with their uncles, transactions, receipts and logs,
+// and the rows transformers derived from those logs. With keepReferenced, blocks with logs
+// referenced by transformer output are kept whole. Partitions left empty are dropped.
+func (repository PruneRepository) PruneBlocks(keepBlocks int64, keepReferenced bool) (int64, error) {
+	var keep string
+	if keepReferenced {
+		var references []reference
+		err := repository.database.Select(&references,
+			`SELECT table_name, column_name FROM log_references WHERE to_regclass(table_name) IS NOT NULL`)
+		if err != nil {
+			return 0, err
+		}
+		keep = keepReferencedCondition(references, `SELECT 1 FROM receipts
+                JOIN logs ON logs.receipt_id = receipts.id AND logs.block_number = receipts.block_number
+                WHERE receipts.block_id = blocks.id AND receipts.block_number = blocks.number`, "logs.id")
+	}
+	// Receipts are deleted in one statement per batch ahead of their blocks: cascading from
+	// blocks would fire the receipt and log triggers once per block
+	receipts := `DELETE FROM receipts USING blocks
+                WHERE receipts.block_id = blocks.id AND receipts.block_number = blocks.number
+                  AND receipts.block_number >= $1 AND receipts.block_number < $2
+                  AND blocks.number >= $1 AND blocks.number < $2` + keep
+	pruned, cutoff, err := repository.prune("blocks", "number", keep, keepBlocks, receipts)
+	if err != nil {
+		return pruned, err
+	}
+	return pruned, repository.dropEmptyPartitions(cutoff)
+}
+
+// PruneHeaders deletes old headers, cascading to checked headers and transformer output.
+// With keepReferenced, headers referenced by any table other than checked_headers are kept.
+func (repository PruneRepository) PruneHeaders(keepBlocks int64, keepReferenced bool) (int64, error) {
+	var keep string
+	if keepReferenced {
+		var references []reference
+		err := repository.database.Select(&references,
+			`SELECT con.conrelid::regclass::text AS table_name, att.attname AS column_name
+                FROM pg_constraint con
+                  JOIN pg_attribute att ON att.attrelid = con.conrelid AND att.attnum = con.conkey[1]
+                WHERE con.contype = 'f' AND con.confrelid = 'public.headers'::regclass
+                  AND con.conrelid <> 'public.checked_headers'::regclass`)
+		if err != nil {
+			return 0, err
+		}
+		keep = keepReferencedCondition(references, "", "headers.id")
+	}
+	pruned, _, err := repository.prune("headers", "block_number", keep, keepBlocks, "")
+	return pruned, err
+}
+
+// keepReferencedCondition builds a WHERE clause excluding rows whose id, as selected by
+// join, is referenced by any of the given tables
+func keepReferencedCondition(references []reference, join, id string) string {
+	if len(references) == 0 {
+		return ""
+	}
+	var referenced []string
+	for i, reference := range references {
+		alias := fmt.Sprintf("reference_%d", i)
+		referenced = append(referenced, fmt.Sprintf("EXISTS (SELECT 1 FROM %s %s WHERE %s.%s = %s)",
+			reference.Table, alias, alias, pq.QuoteIdentifier(reference.Column), id))
+	}
+	condition := strings.Join(referenced, " OR ")
+	if join == "" {
+		return fmt.Sprintf(" AND NOT (%s)", condition)
+	}
+	return fmt.Sprintf(" AND NOT EXISTS (%s AND (%s))", join, condition)
+}
+
+// prune deletes rows of table below the cutoff in batches, each in its own transaction. When
+// given, dependents is run first for every batch, with the same bounds.
+func (repository PruneRepository) prune(table, numberColumn, keep string, keepBlocks int64, dependents string) (int64, int64, error) {
+	if keepBlocks <= 0 {
+		return 0, 0, ErrInvalidKeepBlocks
+	}
+	var first, last sql.NullInt64
+	err := repository.database.QueryRow(
+		fmt.Sprintf(`SELECT min(%s), max(%s) FROM %s`, numberColumn, numberColumn, table)).Scan(&first, &last)
+	if err != nil || !last.Valid {
+		return 0, 0, err
+	}
+	cutoff := last.Int64 - keepBlocks + 1
+	var pruned int64
+	for start := first.Int64; start < cutoff; start += pruneBatchSize {
+		end := start + pruneBatchSize
+		if end > cutoff {
+			end = cutoff
+		}
+		deleted, err := repository.pruneBatch(table, numberColumn, keep, dependents, start, end)
+		if err != nil {
+			return pruned, cutoff, err
+		}
+		pruned += deleted
+	}
+	return pruned, cutoff, nil
+}
+
+func (repository PruneRepository) pruneBatch(table, numberColumn, keep, dependents string, start, end int64) (int64, error) {
+	tx, err := repository.database.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	if dependents != "" {
+		_, err = tx.Exec(dependents, start, end)
+		if err != nil {
+			tx.Rollback()
+			return 0, postgres.ErrDBDeleteFailed
+		}
+	}
+	result, err := tx.Exec(
+		fmt.Sprintf(`DELETE FROM %s WHERE %s >= $1 AND %s < $2%s`, table, numberColumn, numberColumn, keep),
+		start, end)
+	if err != nil {
+		tx.Rollback()
+		return 0, postgres.ErrDBDeleteFailed
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	return deleted, tx.Commit()
+}
+
+// dropEmptyPartitions drops partitions of transactions, receipts and logs entirely below
+// cutoff once they are empty, returning their space to the operating system without
+// waiting for a vacuum
+func (repository PruneRepository) dropEmptyPartitions(cutoff int64) error {
+	var partitionSize int64
+	err := repository.database.Get(&partitionSize, `SELECT block_partition_size()`)
+	if err != nil {
+		return err
+	}
+	var partitions []struct {
+		Parent string
+		Child  string
+	}
+	err = repository.database.Select(&partitions,
+		`SELECT parent.relname AS parent, child.relname AS child
+                FROM pg_inherits
+                  JOIN pg_class parent ON parent.oid = pg_inherits.inhparent
+                  JOIN pg_class child ON child.oid = pg_inherits.inhrelid
+                WHERE parent.relname IN ('transactions', 'receipts', 'logs')`)
+	if err != nil {
+		return err
+	}
+	for _, partition := range partitions {
+		start, err := strconv.ParseInt(strings.TrimPrefix(partition.Child, partition.Parent+"_"), 10, 64)
+		if err != nil || start+partitionSize > cutoff {
+			continue
+		}
+		var empty bool
+		err = repository.database.Get(&empty, fmt.Sprintf(`SELECT NOT EXISTS (SELECT 1 FROM %s)`, pq.QuoteIdentifier(partition.Child)))
+		if err != nil {
+			return err
+		}
+		if !empty {
+			continue
+		}
+		_, err = repository.database.Exec(fmt.Sprintf(`ALTER TABLE %s DETACH PARTITION %s; DROP TABLE %s`,
+			pq.QuoteIdentifier(partition.Parent), pq.QuoteIdentifier(partition.Child), pq.QuoteIdentifier(partition.Child)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
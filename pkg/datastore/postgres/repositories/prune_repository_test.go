@@ -0,0 +1,151 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package repositories_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/test_config"
+)
+
+var _ = Describe("Prune repository", func() {
+	var (
+		db               *postgres.DB
+		blockRepository  *repositories.BlockRepository
+		headerRepository repositories.HeaderRepository
+		repo             repositories.PruneRepository
+	)
+
+	BeforeEach(func() {
+		db = test_config.NewTestDB(test_config.NewTestNode())
+		test_config.CleanTestDB(db)
+		blockRepository = repositories.NewBlockRepository(db)
+		headerRepository = repositories.NewHeaderRepository(db)
+		repo = repositories.NewPruneRepository(db)
+	})
+
+	AfterEach(func() {
+		db.MustExec(`DELETE FROM log_references WHERE table_name = 'prune_test_log_output'`)
+		db.MustExec(`DROP TABLE IF EXISTS prune_test_log_output, prune_test_header_output`)
+	})
+
+	createBlock := func(number int64) {
+		txHash := "0xtx" + string('a'+rune(number))
+		_, err := blockRepository.CreateOrUpdateBlock(core.Block{
+			Number: number,
+			Hash:   "0xblock" + string('a'+rune(number)),
+			Transactions: []core.Transaction{{
+				Hash: txHash,
+				Receipt: core.Receipt{
+					TxHash: txHash,
+					Logs:   []core.Log{{BlockNumber: number, TxHash: txHash, Topics: core.Topics{}}},
+				},
+			}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	count := func(table string) int {
+		var rows int
+		err := db.Get(&rows, `SELECT count(*) FROM `+table)
+		Expect(err).NotTo(HaveOccurred())
+		return rows
+	}
+
+	It("prunes blocks more than the given number behind the highest stored block", func() {
+		for number := int64(1); number <= 5; number++ {
+			createBlock(number)
+		}
+
+		pruned, err := repo.PruneBlocks(2, false)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pruned).To(Equal(int64(3)))
+		var numbers []int64
+		err = db.Select(&numbers, `SELECT number FROM blocks ORDER BY number`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(numbers).To(Equal([]int64{4, 5}))
+		Expect(count("transactions")).To(Equal(2))
+		Expect(count("receipts")).To(Equal(2))
+		Expect(count("logs")).To(Equal(2))
+	})
+
+	It("deletes transformer output derived from pruned logs", func() {
+		createBlock(1)
+		createBlock(2)
+		db.MustExec(`CREATE TABLE prune_test_log_output (vulcanize_log_id INTEGER NOT NULL)`)
+		db.MustExec(`INSERT INTO log_references (table_name, column_name) VALUES ('prune_test_log_output', 'vulcanize_log_id')`)
+		db.MustExec(`INSERT INTO prune_test_log_output SELECT id FROM logs`)
+
+		_, err := repo.PruneBlocks(1, false)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(count("prune_test_log_output")).To(Equal(1))
+	})
+
+	It("keeps blocks with logs referenced by transformer output", func() {
+		createBlock(1)
+		createBlock(2)
+		createBlock(3)
+		db.MustExec(`CREATE TABLE prune_test_log_output (vulcanize_log_id INTEGER NOT NULL)`)
+		db.MustExec(`INSERT INTO log_references (table_name, column_name) VALUES ('prune_test_log_output', 'vulcanize_log_id')`)
+		db.MustExec(`INSERT INTO prune_test_log_output SELECT id FROM logs WHERE block_number = 1`)
+
+		pruned, err := repo.PruneBlocks(1, true)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pruned).To(Equal(int64(1)))
+		var numbers []int64
+		err = db.Select(&numbers, `SELECT number FROM blocks ORDER BY number`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(numbers).To(Equal([]int64{1, 3}))
+		Expect(count("prune_test_log_output")).To(Equal(1))
+	})
+
+	It("prunes headers, keeping those referenced by transformer output when asked", func() {
+		var headerIds []int64
+		for number := int64(1); number <= 3; number++ {
+			id, err := headerRepository.CreateOrUpdateHeader(core.Header{BlockNumber: number, Hash: "0xheader" + string('a'+rune(number)), Raw: []byte("{}"), Timestamp: "1"})
+			Expect(err).NotTo(HaveOccurred())
+			headerIds = append(headerIds, id)
+		}
+		db.MustExec(`CREATE TABLE prune_test_header_output (header_id INTEGER NOT NULL REFERENCES headers (id) ON DELETE CASCADE)`)
+		db.MustExec(`INSERT INTO prune_test_header_output VALUES ($1)`, headerIds[0])
+
+		pruned, err := repo.PruneHeaders(1, true)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pruned).To(Equal(int64(1)))
+		var numbers []int64
+		err = db.Select(&numbers, `SELECT block_number FROM headers ORDER BY block_number`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(numbers).To(Equal([]int64{1, 3}))
+	})
+
+	It("refuses to prune everything", func() {
+		createBlock(1)
+
+		_, err := repo.PruneBlocks(0, false)
+
+		Expect(err).To(MatchError(repositories.ErrInvalidKeepBlocks))
+		Expect(count("blocks")).To(Equal(1))
+	})
+})
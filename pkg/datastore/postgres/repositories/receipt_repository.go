@@ -56,10 +56,10 @@ func createReceipt(receipt core.Receipt, blockId int64, tx *sql.Tx) (int64, erro
 	var receiptId int64
 	err := tx.QueryRow(
 		`INSERT INTO receipts
-		               (contract_address, tx_hash, cumulative_gas_used, gas_used, state_root, status, block_id)
-		               VALUES ($1, $2, $3, $4, $5, $6, $7)
+		               (contract_address, tx_hash, cumulative_gas_used, gas_used, state_root, status, block_id, block_number, bloom)
+		               VALUES ($1, $2, $3, $4, $5, $6, $7, (SELECT number FROM blocks WHERE id = $7), $8)
 		               RETURNING id`,
-		receipt.ContractAddress, receipt.TxHash, receipt.CumulativeGasUsed, receipt.GasUsed, receipt.StateRoot, receipt.Status, blockId,
+		receipt.ContractAddress, receipt.TxHash, receipt.CumulativeGasUsed, receipt.GasUsed, receipt.StateRoot, receipt.Status, blockId, receipt.Bloom,
 	).Scan(&receiptId)
 	return receiptId, err
 }
@@ -67,10 +67,10 @@ func createReceipt(receipt core.Receipt, blockId int64, tx *sql.Tx) (int64, erro
 func createLogs(logs []core.Log, receiptId int64, tx *sql.Tx) error {
 	for _, log := range logs {
 		_, err := tx.Exec(
-			`INSERT INTO logs (block_number, address, tx_hash, index, topic0, topic1, topic2, topic3, data, receipt_id)
-                VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			`INSERT INTO logs (block_number, address, tx_hash, index, topic0, topic1, topic2, topic3, data, receipt_id, block_hash, removed)
+                VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
                 `,
-			log.BlockNumber, log.Address, log.TxHash, log.Index, log.Topics[0], log.Topics[1], log.Topics[2], log.Topics[3], log.Data, receiptId,
+			log.BlockNumber, log.Address, log.TxHash, log.Index, log.Topics[0], log.Topics[1], log.Topics[2], log.Topics[3], log.Data, receiptId, log.BlockHash, log.Removed,
 		)
 		if err != nil {
 			return err
@@ -84,10 +84,10 @@ func (receiptRepository ReceiptRepository) CreateReceipt(blockId int64, receipt
 	var receiptId int64
 	err := tx.QueryRow(
 		`INSERT INTO receipts
-               (contract_address, tx_hash, cumulative_gas_used, gas_used, state_root, status, block_id)
-               VALUES ($1, $2, $3, $4, $5, $6, $7)
+               (contract_address, tx_hash, cumulative_gas_used, gas_used, state_root, status, block_id, block_number, bloom)
+               VALUES ($1, $2, $3, $4, $5, $6, $7, (SELECT number FROM blocks WHERE id = $7), $8)
                RETURNING id`,
-		receipt.ContractAddress, receipt.TxHash, receipt.CumulativeGasUsed, receipt.GasUsed, receipt.StateRoot, receipt.Status, blockId).Scan(&receiptId)
+		receipt.ContractAddress, receipt.TxHash, receipt.CumulativeGasUsed, receipt.GasUsed, receipt.StateRoot, receipt.Status, blockId, receipt.Bloom).Scan(&receiptId)
 	if err != nil {
 		tx.Rollback()
 		return receiptId, err
@@ -103,7 +103,8 @@ func (receiptRepository ReceiptRepository) GetReceipt(txHash string) (core.Recei
                        cumulative_gas_used,
                        gas_used,
                        state_root,
-                       status
+                       status,
+                       bloom
                 FROM receipts
                 WHERE tx_hash = $1`, txHash)
 	receipt, err := loadReceipt(row)
@@ -125,9 +126,11 @@ func loadReceipt(receiptsRow *sql.Row) (core.Receipt, error) {
 	var gasUsed uint64
 	var stateRoot string
 	var status int
+	var bloom sql.NullString
 
-	err := receiptsRow.Scan(&contractAddress, &txHash, &cumulativeGasUsed, &gasUsed, &stateRoot, &status)
+	err := receiptsRow.Scan(&contractAddress, &txHash, &cumulativeGasUsed, &gasUsed, &stateRoot, &status, &bloom)
 	return core.Receipt{
+		Bloom:             bloom.String,
 		TxHash:            txHash,
 		ContractAddress:   contractAddress,
 		CumulativeGasUsed: cumulativeGasUsed,
@@ -17,6 +17,8 @@
 package repositories_test
 
 import (
+	"strings"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/vulcanize/vulcanizedb/pkg/core"
@@ -103,6 +105,7 @@ var _ = Describe("Receipts Repository", func() {
 	Describe("Saving receipts on a block's transactions", func() {
 		It("returns the receipt when it exists", func() {
 			expected := core.Receipt{
+				Bloom:             "0x" + strings.Repeat("0", 510) + "01",
 				ContractAddress:   "0xde0b295669a9fd93d5f28d9ec85e40f4cb697bae",
 				CumulativeGasUsed: 7996119,
 				GasUsed:           21000,
@@ -123,8 +126,7 @@ var _ = Describe("Receipts Repository", func() {
 			Expect(err).NotTo(HaveOccurred())
 			receipt, err := receiptRepository.GetReceipt("0xe340558980f89d5f86045ac11e5cc34e4bcec20f9f1e2a427aa39d87114e8223")
 			Expect(err).ToNot(HaveOccurred())
-			//Not currently serializing bloom logs
-			Expect(receipt.Bloom).To(Equal(core.Receipt{}.Bloom))
+			Expect(receipt.Bloom).To(Equal(expected.Bloom))
 			Expect(receipt.TxHash).To(Equal(expected.TxHash))
 			Expect(receipt.CumulativeGasUsed).To(Equal(expected.CumulativeGasUsed))
 			Expect(receipt.GasUsed).To(Equal(expected.GasUsed))
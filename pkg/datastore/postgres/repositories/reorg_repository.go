@@ -0,0 +1,57 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package repositories
+
+import (
+	"github.com/jmoiron/sqlx"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+)
+
+type ReorgRepository struct {
+	database *postgres.DB
+}
+
+func NewReorgRepository(database *postgres.DB) ReorgRepository {
+	return ReorgRepository{database: database}
+}
+
+// CreateReorg replaces the orphaned blocks with their canonical replacements and records the reorg
+// in one transaction, so that a failure leaves neither a half replaced chain nor an unrecorded reorg
+func (repository ReorgRepository) CreateReorg(reorg core.Reorg, replacements []core.Block) (int64, error) {
+	var reorgId int64
+	writer := postgres.NewBulkBlockWriter(repository.database)
+	err := writer.WriteBlocksWith(replacements, func(tx *sqlx.Tx) error {
+		return tx.QueryRowx(
+			`INSERT INTO public.reorgs (block_number, depth, old_hash, new_hash, eth_node_id, eth_node_fingerprint)
+			VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+			reorg.BlockNumber, reorg.Depth, reorg.OldHash, reorg.NewHash, repository.database.NodeID, repository.database.Node.ID).Scan(&reorgId)
+	})
+	return reorgId, err
+}
+
+// GetReorgs returns the reorgs recorded for this node after the given reorg id, oldest first,
+// so that consumers can keep track of the last reorg they have handled
+func (repository ReorgRepository) GetReorgs(afterId int64) ([]core.Reorg, error) {
+	reorgs := make([]core.Reorg, 0)
+	err := repository.database.Select(&reorgs,
+		`SELECT id, block_number, depth, old_hash, new_hash FROM public.reorgs
+		WHERE id > $1 AND eth_node_id = $2
+		ORDER BY id`,
+		afterId, repository.database.NodeID)
+	return reorgs, err
+}
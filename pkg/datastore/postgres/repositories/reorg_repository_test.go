@@ -0,0 +1,93 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package repositories_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/test_config"
+)
+
+var _ = Describe("Reorg repository", func() {
+	var (
+		db   *postgres.DB
+		repo repositories.ReorgRepository
+	)
+
+	BeforeEach(func() {
+		db = test_config.NewTestDB(core.Node{ID: "EthNodeFingerprint"})
+		test_config.CleanTestDB(db)
+		repo = repositories.NewReorgRepository(db)
+	})
+
+	It("creates a reorg associated with the node", func() {
+		reorg := core.Reorg{BlockNumber: 100, Depth: 2, OldHash: "0xold", NewHash: "0xnew"}
+
+		id, err := repo.CreateReorg(reorg, nil)
+
+		Expect(err).NotTo(HaveOccurred())
+		var dbReorg core.Reorg
+		err = db.Get(&dbReorg, `SELECT id, block_number, depth, old_hash, new_hash FROM public.reorgs WHERE id = $1`, id)
+		Expect(err).NotTo(HaveOccurred())
+		reorg.Id = id
+		Expect(dbReorg).To(Equal(reorg))
+		var ethNodeFingerprint string
+		err = db.Get(&ethNodeFingerprint, `SELECT eth_node_fingerprint FROM public.reorgs WHERE id = $1`, id)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ethNodeFingerprint).To(Equal(db.Node.ID))
+	})
+
+	It("replaces the orphaned blocks in the same transaction", func() {
+		blockRepository := repositories.NewBlockRepository(db)
+		_, err := blockRepository.CreateOrUpdateBlock(core.Block{Number: 100, Hash: "0xold"})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = repo.CreateReorg(core.Reorg{BlockNumber: 100, Depth: 1, OldHash: "0xold", NewHash: "0xnew"}, []core.Block{{Number: 100, Hash: "0xnew"}})
+
+		Expect(err).NotTo(HaveOccurred())
+		block, err := blockRepository.GetBlock(100)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(block.Hash).To(Equal("0xnew"))
+	})
+
+	It("returns reorgs recorded after the given id in order", func() {
+		firstId, err := repo.CreateReorg(core.Reorg{BlockNumber: 100, Depth: 1, OldHash: "0xold1", NewHash: "0xnew1"}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		secondId, err := repo.CreateReorg(core.Reorg{BlockNumber: 200, Depth: 3, OldHash: "0xold2", NewHash: "0xnew2"}, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		reorgs, err := repo.GetReorgs(firstId)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reorgs).To(Equal([]core.Reorg{{Id: secondId, BlockNumber: 200, Depth: 3, OldHash: "0xold2", NewHash: "0xnew2"}}))
+	})
+
+	It("does not return reorgs recorded for other nodes", func() {
+		otherDb := test_config.NewTestDB(core.Node{ID: "OtherNodeFingerprint"})
+		otherRepo := repositories.NewReorgRepository(otherDb)
+		_, err := otherRepo.CreateReorg(core.Reorg{BlockNumber: 100, Depth: 1, OldHash: "0xold", NewHash: "0xnew"}, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		reorgs, err := repo.GetReorgs(0)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reorgs).To(BeEmpty())
+	})
+})
@@ -23,6 +23,17 @@ import (
 	"github.com/vulcanize/vulcanizedb/pkg/filters"
 )
 
+var ErrAbiDoesNotExist = func(contractAddr string) error {
+	return fmt.Errorf("ABI for contract %s does not exist", contractAddr)
+}
+
+// AbiRepository is a registry of contract ABIs, keyed by contract address, which also
+// caches ABIs fetched over the network
+type AbiRepository interface {
+	GetAbi(contractAddr string) (string, error)
+	CreateAbi(contractAddr, abi, source string) error
+}
+
 var ErrBlockDoesNotExist = func(blockNumber int64) error {
 	return fmt.Errorf("Block number %d does not exist", blockNumber)
 }
@@ -31,7 +42,21 @@ type BlockRepository interface {
 	CreateOrUpdateBlock(block core.Block) (int64, error)
 	GetBlock(blockNumber int64) (core.Block, error)
 	MissingBlockNumbers(startingBlockNumber, endingBlockNumber int64, nodeID string) []int64
-	SetBlocksStatus(chainHead int64)
+	BlockNumbersNeedingRefresh(nodeID string) []int64
+	RefreshBlock(block core.Block) error
+	SetBlocksStatus(chainHead, finalityDepth int64)
+}
+
+// BlockWriter persists batches of blocks along with their uncles, transactions, receipts and logs
+type BlockWriter interface {
+	WriteBlocks(blocks []core.Block) error
+}
+
+// CheckpointRepository records how far an import that began at a starting block number has
+// progressed without gaps, so that an interrupted import can resume
+type CheckpointRepository interface {
+	GetCheckpoint(nodeID string, startingBlockNumber int64) (int64, error)
+	SetCheckpoint(nodeID string, startingBlockNumber, lastBlockNumber int64) error
 }
 
 var ErrContractDoesNotExist = func(contractHash string) error {
@@ -56,7 +81,14 @@ type FilterRepository interface {
 type HeaderRepository interface {
 	CreateOrUpdateHeader(header core.Header) (int64, error)
 	GetHeader(blockNumber int64) (core.Header, error)
+	GetHeaders(startingBlockNumber, endingBlockNumber int64) ([]core.Header, error)
 	MissingBlockNumbers(startingBlockNumber, endingBlockNumber int64, nodeID string) []int64
+	SetHeadersStatus(chainHead, finalityDepth int64) error
+}
+
+// HeaderWriter persists batches of headers
+type HeaderWriter interface {
+	WriteHeaders(headers []core.Header) error
 }
 
 type LogRepository interface {
@@ -68,12 +100,22 @@ var ErrReceiptDoesNotExist = func(txHash string) error {
 	return fmt.Errorf("Receipt for tx: %v does not exist", txHash)
 }
 
+type PruneRepository interface {
+	PruneBlocks(keepBlocks int64, keepReferenced bool) (int64, error)
+	PruneHeaders(keepBlocks int64, keepReferenced bool) (int64, error)
+}
+
 type ReceiptRepository interface {
 	CreateReceiptsAndLogs(blockId int64, receipts []core.Receipt) error
 	CreateReceipt(blockId int64, receipt core.Receipt) (int64, error)
 	GetReceipt(txHash string) (core.Receipt, error)
 }
 
+type ReorgRepository interface {
+	CreateReorg(reorg core.Reorg, replacements []core.Block) (int64, error)
+	GetReorgs(afterId int64) ([]core.Reorg, error)
+}
+
 type WatchedEventRepository interface {
 	GetWatchedEvents(name string) ([]*core.WatchedEvent, error)
 }
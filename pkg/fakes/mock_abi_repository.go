@@ -0,0 +1,69 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package fakes
+
+import (
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore"
+)
+
+type MockAbiRepository struct {
+	abis                     map[string]string
+	createAbiReturnErr       error
+	createAbiPassedAddresses []string
+	createAbiPassedSources   []string
+}
+
+func NewMockAbiRepository() *MockAbiRepository {
+	return &MockAbiRepository{abis: make(map[string]string)}
+}
+
+func (repository *MockAbiRepository) SetAbi(contractAddr, abi string) {
+	repository.abis[contractAddr] = abi
+}
+
+func (repository *MockAbiRepository) SetCreateAbiReturnErr(err error) {
+	repository.createAbiReturnErr = err
+}
+
+func (repository *MockAbiRepository) GetAbi(contractAddr string) (string, error) {
+	abi, ok := repository.abis[contractAddr]
+	if !ok {
+		return "", datastore.ErrAbiDoesNotExist(contractAddr)
+	}
+	return abi, nil
+}
+
+func (repository *MockAbiRepository) CreateAbi(contractAddr, abi, source string) error {
+	repository.createAbiPassedAddresses = append(repository.createAbiPassedAddresses, contractAddr)
+	repository.createAbiPassedSources = append(repository.createAbiPassedSources, source)
+	if repository.createAbiReturnErr != nil {
+		return repository.createAbiReturnErr
+	}
+	repository.abis[contractAddr] = abi
+	return nil
+}
+
+func (repository *MockAbiRepository) AssertCreateAbiCalledWith(contractAddrs, sources []string) {
+	Expect(repository.createAbiPassedAddresses).To(Equal(contractAddrs))
+	Expect(repository.createAbiPassedSources).To(Equal(sources))
+}
+
+func (repository *MockAbiRepository) AssertCreateAbiNotCalled() {
+	Expect(repository.createAbiPassedAddresses).To(BeEmpty())
+}
@@ -29,13 +29,18 @@ type MockBlockRepository struct {
 	createOrUpdateBlockPassedBlockNumbers        []int64
 	createOrUpdateBlockReturnErr                 error
 	createOrUpdateBlockReturnInt                 int64
+	getBlockReturnBlocks                         map[int64]core.Block
 	missingBlockNumbersCalled                    bool
 	missingBlockNumbersPassedEndingBlockNumber   int64
 	missingBlockNumbersPassedNodeId              string
 	missingBlockNumbersPassedStartingBlockNumber int64
 	missingBlockNumbersReturnArray               []int64
+	needingRefreshReturnArray                    []int64
+	refreshBlockPassedBlockNumbers               []int64
+	refreshBlockReturnErr                        error
 	setBlockStatusCalled                         bool
 	setBlockStatusPassedChainHead                int64
+	setBlockStatusPassedFinalityDepth            int64
 }
 
 func NewMockBlockRepository() *MockBlockRepository {
@@ -61,6 +66,13 @@ func (repository *MockBlockRepository) SetCreateOrUpdateBlockReturnVals(i int64,
 	repository.createOrUpdateBlockReturnErr = err
 }
 
+func (repository *MockBlockRepository) SetGetBlockReturnBlocks(blocks []core.Block) {
+	repository.getBlockReturnBlocks = make(map[int64]core.Block)
+	for _, block := range blocks {
+		repository.getBlockReturnBlocks[block.Number] = block
+	}
+}
+
 func (repository *MockBlockRepository) SetMissingBlockNumbersReturnArray(returnArray []int64) {
 	repository.missingBlockNumbersReturnArray = returnArray
 }
@@ -74,6 +86,9 @@ func (repository *MockBlockRepository) CreateOrUpdateBlock(block core.Block) (in
 }
 
 func (repository *MockBlockRepository) GetBlock(blockNumber int64) (core.Block, error) {
+	if block, ok := repository.getBlockReturnBlocks[blockNumber]; ok {
+		return block, nil
+	}
 	return core.Block{Number: blockNumber}, nil
 }
 
@@ -85,9 +100,27 @@ func (repository *MockBlockRepository) MissingBlockNumbers(startingBlockNumber i
 	return repository.missingBlockNumbersReturnArray
 }
 
-func (repository *MockBlockRepository) SetBlocksStatus(chainHead int64) {
+func (repository *MockBlockRepository) SetBlockNumbersNeedingRefreshReturnArray(returnArray []int64) {
+	repository.needingRefreshReturnArray = returnArray
+}
+
+func (repository *MockBlockRepository) SetRefreshBlockReturnErr(err error) {
+	repository.refreshBlockReturnErr = err
+}
+
+func (repository *MockBlockRepository) BlockNumbersNeedingRefresh(nodeId string) []int64 {
+	return repository.needingRefreshReturnArray
+}
+
+func (repository *MockBlockRepository) RefreshBlock(block core.Block) error {
+	repository.refreshBlockPassedBlockNumbers = append(repository.refreshBlockPassedBlockNumbers, block.Number)
+	return repository.refreshBlockReturnErr
+}
+
+func (repository *MockBlockRepository) SetBlocksStatus(chainHead, finalityDepth int64) {
 	repository.setBlockStatusCalled = true
 	repository.setBlockStatusPassedChainHead = chainHead
+	repository.setBlockStatusPassedFinalityDepth = finalityDepth
 }
 
 func (repository *MockBlockRepository) AssertCreateOrUpdateBlockCallCountEquals(times int) {
@@ -111,7 +144,12 @@ func (repository *MockBlockRepository) AssertMissingBlockNumbersCalledWith(start
 	Expect(repository.missingBlockNumbersPassedNodeId).To(Equal(nodeId))
 }
 
-func (repository *MockBlockRepository) AssertSetBlockStatusCalledWith(chainHead int64) {
+func (repository *MockBlockRepository) AssertSetBlockStatusCalledWith(chainHead, finalityDepth int64) {
 	Expect(repository.setBlockStatusCalled).To(BeTrue())
 	Expect(repository.setBlockStatusPassedChainHead).To(Equal(chainHead))
+	Expect(repository.setBlockStatusPassedFinalityDepth).To(Equal(finalityDepth))
+}
+
+func (repository *MockBlockRepository) AssertRefreshBlockCalledWithBlockNumbers(blockNumbers []int64) {
+	Expect(repository.refreshBlockPassedBlockNumbers).To(Equal(blockNumbers))
 }
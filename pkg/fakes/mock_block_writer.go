@@ -0,0 +1,68 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package fakes
+
+import (
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+)
+
+type MockBlockWriter struct {
+	writeBlocksCallCount          int
+	writeBlocksPassedBlockNumbers []int64
+	writeBlocksPassedBlocks       []core.Block
+	writeBlocksReturnErr          error
+}
+
+func NewMockBlockWriter() *MockBlockWriter {
+	return &MockBlockWriter{
+		writeBlocksCallCount:          0,
+		writeBlocksPassedBlockNumbers: nil,
+		writeBlocksPassedBlocks:       nil,
+		writeBlocksReturnErr:          nil,
+	}
+}
+
+func (writer *MockBlockWriter) SetWriteBlocksReturnErr(err error) {
+	writer.writeBlocksReturnErr = err
+}
+
+func (writer *MockBlockWriter) WriteBlocks(blocks []core.Block) error {
+	writer.writeBlocksCallCount++
+	for _, block := range blocks {
+		writer.writeBlocksPassedBlockNumbers = append(writer.writeBlocksPassedBlockNumbers, block.Number)
+	}
+	writer.writeBlocksPassedBlocks = append(writer.writeBlocksPassedBlocks, blocks...)
+	return writer.writeBlocksReturnErr
+}
+
+func (writer *MockBlockWriter) AssertWriteBlocksCallCountEquals(times int) {
+	Expect(writer.writeBlocksCallCount).To(Equal(times))
+}
+
+func (writer *MockBlockWriter) AssertWriteBlocksCalledWithBlockNumbers(blockNumbers []int64) {
+	Expect(writer.writeBlocksPassedBlockNumbers).To(Equal(blockNumbers))
+}
+
+func (writer *MockBlockWriter) AssertWriteBlocksCalledWith(blocks []core.Block) {
+	Expect(writer.writeBlocksPassedBlocks).To(Equal(blocks))
+}
+
+func (writer *MockBlockWriter) AssertWriteBlocksNotCalled() {
+	Expect(writer.writeBlocksCallCount).To(Equal(0))
+}
@@ -17,10 +17,12 @@
 package fakes
 
 import (
+	"context"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
 	. "github.com/onsi/gomega"
 
 	"github.com/vulcanize/vulcanizedb/pkg/core"
@@ -35,11 +37,15 @@ type MockBlockChain struct {
 	fetchContractDataPassedResult      interface{}
 	fetchContractDataPassedBlockNumber int64
 	getBlockByNumberErr                error
+	getBlockByNumberReturnBlocks       map[int64]core.Block
+	getHeaderByNumberReturnHeaders     map[int64]core.Header
 	logQuery                           ethereum.FilterQuery
 	logQueryErr                        error
 	logQueryReturnLogs                 []types.Log
 	lastBlock                          *big.Int
+	newHeads                           []*types.Header
 	node                               core.Node
+	subscribeNewHeadErr                error
 }
 
 func NewMockBlockChain() *MockBlockChain {
@@ -56,10 +62,32 @@ func (blockChain *MockBlockChain) SetLastBlock(blockNumber *big.Int) {
 	blockChain.lastBlock = blockNumber
 }
 
+func (blockChain *MockBlockChain) SetNewHeads(heads []*types.Header) {
+	blockChain.newHeads = heads
+}
+
+func (blockChain *MockBlockChain) SetSubscribeNewHeadErr(err error) {
+	blockChain.subscribeNewHeadErr = err
+}
+
 func (blockChain *MockBlockChain) SetGetBlockByNumberErr(err error) {
 	blockChain.getBlockByNumberErr = err
 }
 
+func (blockChain *MockBlockChain) SetGetBlockByNumberReturnBlocks(blocks []core.Block) {
+	blockChain.getBlockByNumberReturnBlocks = make(map[int64]core.Block)
+	for _, block := range blocks {
+		blockChain.getBlockByNumberReturnBlocks[block.Number] = block
+	}
+}
+
+func (blockChain *MockBlockChain) SetGetHeaderByNumberReturnHeaders(headers []core.Header) {
+	blockChain.getHeaderByNumberReturnHeaders = make(map[int64]core.Header)
+	for _, header := range headers {
+		blockChain.getHeaderByNumberReturnHeaders[header.BlockNumber] = header
+	}
+}
+
 func (chain *MockBlockChain) SetGetEthLogsWithCustomQueryErr(err error) {
 	chain.logQueryErr = err
 }
@@ -69,6 +97,9 @@ func (chain *MockBlockChain) SetGetEthLogsWithCustomQueryReturnLogs(logs []types
 }
 
 func (blockChain *MockBlockChain) GetHeaderByNumber(blockNumber int64) (core.Header, error) {
+	if header, ok := blockChain.getHeaderByNumberReturnHeaders[blockNumber]; ok {
+		return header, nil
+	}
 	return core.Header{BlockNumber: blockNumber}, nil
 }
 
@@ -83,6 +114,9 @@ func (blockChain *MockBlockChain) FetchContractData(abiJSON string, address stri
 }
 
 func (chain *MockBlockChain) GetBlockByNumber(blockNumber int64) (core.Block, error) {
+	if block, ok := chain.getBlockByNumberReturnBlocks[blockNumber]; ok {
+		return block, chain.getBlockByNumberErr
+	}
 	return core.Block{Number: blockNumber}, chain.getBlockByNumberErr
 }
 
@@ -103,6 +137,23 @@ func (chain *MockBlockChain) LastBlock() *big.Int {
 	return chain.lastBlock
 }
 
+func (chain *MockBlockChain) SubscribeNewHead(ctx context.Context, heads chan<- *types.Header) (ethereum.Subscription, error) {
+	if chain.subscribeNewHeadErr != nil {
+		return nil, chain.subscribeNewHeadErr
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		for _, head := range chain.newHeads {
+			select {
+			case heads <- head:
+			case <-quit:
+				return nil
+			}
+		}
+		<-quit
+		return nil
+	}), nil
+}
+
 func (chain *MockBlockChain) Node() core.Node {
 	return chain.node
 }
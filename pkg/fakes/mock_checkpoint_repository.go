@@ -0,0 +1,59 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package fakes
+
+import (
+	. "github.com/onsi/gomega"
+)
+
+type MockCheckpointRepository struct {
+	getCheckpointReturnNumber *int64
+	getCheckpointReturnErr    error
+	setCheckpointPassedNumber []int64
+	setCheckpointReturnErr    error
+}
+
+func NewMockCheckpointRepository() *MockCheckpointRepository {
+	return &MockCheckpointRepository{}
+}
+
+func (repository *MockCheckpointRepository) SetGetCheckpointReturnNumber(blockNumber int64) {
+	repository.getCheckpointReturnNumber = &blockNumber
+}
+
+func (repository *MockCheckpointRepository) SetGetCheckpointReturnErr(err error) {
+	repository.getCheckpointReturnErr = err
+}
+
+func (repository *MockCheckpointRepository) SetSetCheckpointReturnErr(err error) {
+	repository.setCheckpointReturnErr = err
+}
+
+func (repository *MockCheckpointRepository) GetCheckpoint(nodeID string, startingBlockNumber int64) (int64, error) {
+	if repository.getCheckpointReturnNumber == nil {
+		return startingBlockNumber - 1, repository.getCheckpointReturnErr
+	}
+	return *repository.getCheckpointReturnNumber, repository.getCheckpointReturnErr
+}
+
+func (repository *MockCheckpointRepository) SetCheckpoint(nodeID string, startingBlockNumber, lastBlockNumber int64) error {
+	repository.setCheckpointPassedNumber = append(repository.setCheckpointPassedNumber, lastBlockNumber)
+	return repository.setCheckpointReturnErr
+}
+
+func (repository *MockCheckpointRepository) AssertSetCheckpointCalledWith(lastBlockNumbers []int64) {
+	Expect(repository.setCheckpointPassedNumber).To(Equal(lastBlockNumbers))
+}
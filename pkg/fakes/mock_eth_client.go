@@ -23,6 +23,7 @@ import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
 	. "github.com/onsi/gomega"
 )
 
@@ -44,6 +45,8 @@ type MockEthClient struct {
 	filterLogsPassedContext     context.Context
 	filterLogsPassedQuery       ethereum.FilterQuery
 	filterLogsReturnLogs        []types.Log
+	subscribeNewHeadErr         error
+	subscribeNewHeadPassedChan  chan<- *types.Header
 	transactionReceipts         map[string]*types.Receipt
 	err                         error
 	transactionSenderErr        error
@@ -106,6 +109,10 @@ func (client *MockEthClient) SetFilterLogsReturnLogs(logs []types.Log) {
 	client.filterLogsReturnLogs = logs
 }
 
+func (client *MockEthClient) SetSubscribeNewHeadErr(err error) {
+	client.subscribeNewHeadErr = err
+}
+
 func (client *MockEthClient) SetTransactionReceiptErr(err error) {
 	client.transactionReceiptErr = err
 }
@@ -145,6 +152,17 @@ func (client *MockEthClient) FilterLogs(ctx context.Context, q ethereum.FilterQu
 	return client.filterLogsReturnLogs, client.filterLogsErr
 }
 
+func (client *MockEthClient) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	client.subscribeNewHeadPassedChan = ch
+	if client.subscribeNewHeadErr != nil {
+		return nil, client.subscribeNewHeadErr
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	}), nil
+}
+
 func (client *MockEthClient) TransactionSender(ctx context.Context, tx *types.Transaction, block common.Hash, index uint) (common.Address, error) {
 	return common.HexToAddress("0x123"), client.transactionSenderErr
 }
@@ -176,3 +194,7 @@ func (client *MockEthClient) AssertFilterLogsCalledWith(ctx context.Context, q e
 	Expect(client.filterLogsPassedContext).To(Equal(ctx))
 	Expect(client.filterLogsPassedQuery).To(Equal(q))
 }
+
+func (client *MockEthClient) AssertSubscribeNewHeadCalledWith(ch chan<- *types.Header) {
+	Expect(client.subscribeNewHeadPassedChan).To(Equal(ch))
+}
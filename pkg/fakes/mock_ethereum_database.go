@@ -17,12 +17,17 @@
 package fakes
 
 import (
+	"math/big"
+	"sync"
+
 	. "github.com/onsi/gomega"
 
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
 )
 
 type MockEthereumDatabase struct {
+	mutex                          sync.Mutex
 	getBlockCalled                 bool
 	getBlockPassedHash             []byte
 	getBlockPassedNumber           int64
@@ -34,8 +39,13 @@ type MockEthereumDatabase struct {
 	getBlockReceiptsPassedHash     []byte
 	getBlockReceiptsPassedNumber   int64
 	getBlockReceiptsReturnReceipts types.Receipts
+	getChainConfigReturnConfig     *params.ChainConfig
+	getHeaderPassedNumbers         []int64
+	getHeaderReturnHeader          *types.Header
 	getHeadBlockNumberCalled       bool
 	getHeadBlockNumberReturnVal    int64
+	getTotalDifficultyPassedHash   []byte
+	getTotalDifficultyReturnTd     *big.Int
 }
 
 func NewMockEthereumDatabase() *MockEthereumDatabase {
@@ -64,11 +74,25 @@ func (med *MockEthereumDatabase) SetReturnHash(hash []byte) {
 	med.getBlockHashReturnHash = hash
 }
 
+func (med *MockEthereumDatabase) SetReturnChainConfig(chainConfig *params.ChainConfig) {
+	med.getChainConfigReturnConfig = chainConfig
+}
+
+func (med *MockEthereumDatabase) SetReturnTotalDifficulty(td *big.Int) {
+	med.getTotalDifficultyReturnTd = td
+}
+
+func (med *MockEthereumDatabase) SetReturnHeader(header *types.Header) {
+	med.getHeaderReturnHeader = header
+}
+
 func (med *MockEthereumDatabase) SetReturnReceipts(receipts types.Receipts) {
 	med.getBlockReceiptsReturnReceipts = receipts
 }
 
 func (med *MockEthereumDatabase) GetBlock(hash []byte, blockNumber int64) *types.Block {
+	med.mutex.Lock()
+	defer med.mutex.Unlock()
 	med.getBlockCalled = true
 	med.getBlockPassedHash = hash
 	med.getBlockPassedNumber = blockNumber
@@ -76,23 +100,49 @@ func (med *MockEthereumDatabase) GetBlock(hash []byte, blockNumber int64) *types
 }
 
 func (med *MockEthereumDatabase) GetBlockHash(blockNumber int64) []byte {
+	med.mutex.Lock()
+	defer med.mutex.Unlock()
 	med.getBlockHashCalled = true
 	med.getBlockHashPassedNumber = blockNumber
 	return med.getBlockHashReturnHash
 }
 
 func (med *MockEthereumDatabase) GetBlockReceipts(blockHash []byte, blockNumber int64) types.Receipts {
+	med.mutex.Lock()
+	defer med.mutex.Unlock()
 	med.getBlockReceiptsCalled = true
 	med.getBlockReceiptsPassedHash = blockHash
 	med.getBlockReceiptsPassedNumber = blockNumber
 	return med.getBlockReceiptsReturnReceipts
 }
 
+func (med *MockEthereumDatabase) GetChainConfig(genesisHash []byte) *params.ChainConfig {
+	med.mutex.Lock()
+	defer med.mutex.Unlock()
+	return med.getChainConfigReturnConfig
+}
+
+func (med *MockEthereumDatabase) GetHeader(blockHash []byte, blockNumber int64) *types.Header {
+	med.mutex.Lock()
+	defer med.mutex.Unlock()
+	med.getHeaderPassedNumbers = append(med.getHeaderPassedNumbers, blockNumber)
+	return med.getHeaderReturnHeader
+}
+
 func (med *MockEthereumDatabase) GetHeadBlockNumber() int64 {
+	med.mutex.Lock()
+	defer med.mutex.Unlock()
 	med.getHeadBlockNumberCalled = true
 	return med.getHeadBlockNumberReturnVal
 }
 
+func (med *MockEthereumDatabase) GetTotalDifficulty(blockHash []byte, blockNumber int64) *big.Int {
+	med.mutex.Lock()
+	defer med.mutex.Unlock()
+	med.getTotalDifficultyPassedHash = blockHash
+	return med.getTotalDifficultyReturnTd
+}
+
 func (med *MockEthereumDatabase) AssertGetBlockCalledWith(hash []byte, blockNumber int64) {
 	Expect(med.getBlockCalled).To(BeTrue())
 	Expect(med.getBlockPassedHash).To(Equal(hash))
@@ -109,3 +159,7 @@ func (med *MockEthereumDatabase) AssertGetBlockReceiptsCalledWith(blockHash []by
 	Expect(med.getBlockReceiptsPassedHash).To(Equal(blockHash))
 	Expect(med.getBlockReceiptsPassedNumber).To(Equal(blockNumber))
 }
+
+func (med *MockEthereumDatabase) AssertGetHeaderCalledWith(blockNumbers []int64) {
+	Expect(med.getHeaderPassedNumbers).To(Equal(blockNumbers))
+}
@@ -25,7 +25,11 @@ import (
 type MockHeaderRepository struct {
 	createOrUpdateBlockNumbersCallCount          int
 	createOrUpdateBlockNumbersPassedBlockNumbers []int64
+	getHeadersReturnHeaders                      []core.Header
 	missingBlockNumbers                          []int64
+	setHeadersStatusCalled                       bool
+	setHeadersStatusPassedChainHead              int64
+	setHeadersStatusPassedFinalityDepth          int64
 }
 
 func NewMockHeaderRepository() *MockHeaderRepository {
@@ -36,6 +40,10 @@ func (repository *MockHeaderRepository) SetMissingBlockNumbers(blockNumbers []in
 	repository.missingBlockNumbers = blockNumbers
 }
 
+func (repository *MockHeaderRepository) SetGetHeadersReturnHeaders(headers []core.Header) {
+	repository.getHeadersReturnHeaders = headers
+}
+
 func (repository *MockHeaderRepository) CreateOrUpdateHeader(header core.Header) (int64, error) {
 	repository.createOrUpdateBlockNumbersCallCount++
 	repository.createOrUpdateBlockNumbersPassedBlockNumbers = append(repository.createOrUpdateBlockNumbersPassedBlockNumbers, header.BlockNumber)
@@ -46,11 +54,34 @@ func (*MockHeaderRepository) GetHeader(blockNumber int64) (core.Header, error) {
 	return core.Header{BlockNumber: blockNumber}, nil
 }
 
+func (repository *MockHeaderRepository) GetHeaders(startingBlockNumber, endingBlockNumber int64) ([]core.Header, error) {
+	var headers []core.Header
+	for _, header := range repository.getHeadersReturnHeaders {
+		if header.BlockNumber >= startingBlockNumber && header.BlockNumber <= endingBlockNumber {
+			headers = append(headers, header)
+		}
+	}
+	return headers, nil
+}
+
 func (repository *MockHeaderRepository) MissingBlockNumbers(startingBlockNumber, endingBlockNumber int64, nodeID string) []int64 {
 	return repository.missingBlockNumbers
 }
 
+func (repository *MockHeaderRepository) SetHeadersStatus(chainHead, finalityDepth int64) error {
+	repository.setHeadersStatusCalled = true
+	repository.setHeadersStatusPassedChainHead = chainHead
+	repository.setHeadersStatusPassedFinalityDepth = finalityDepth
+	return nil
+}
+
 func (repository *MockHeaderRepository) AssertCreateOrUpdateHeaderCallCountAndPassedBlockNumbers(times int, blockNumbers []int64) {
 	Expect(repository.createOrUpdateBlockNumbersCallCount).To(Equal(times))
 	Expect(repository.createOrUpdateBlockNumbersPassedBlockNumbers).To(Equal(blockNumbers))
 }
+
+func (repository *MockHeaderRepository) AssertSetHeadersStatusCalledWith(chainHead, finalityDepth int64) {
+	Expect(repository.setHeadersStatusCalled).To(BeTrue())
+	Expect(repository.setHeadersStatusPassedChainHead).To(Equal(chainHead))
+	Expect(repository.setHeadersStatusPassedFinalityDepth).To(Equal(finalityDepth))
+}
@@ -0,0 +1,64 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package fakes
+
+import (
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+)
+
+type MockHeaderWriter struct {
+	writeHeadersCallCount          int
+	writeHeadersPassedBlockNumbers []int64
+	writeHeadersPassedHeaders      []core.Header
+	writeHeadersReturnErr          error
+}
+
+func NewMockHeaderWriter() *MockHeaderWriter {
+	return &MockHeaderWriter{
+		writeHeadersCallCount:          0,
+		writeHeadersPassedBlockNumbers: nil,
+		writeHeadersPassedHeaders:      nil,
+		writeHeadersReturnErr:          nil,
+	}
+}
+
+func (writer *MockHeaderWriter) SetWriteHeadersReturnErr(err error) {
+	writer.writeHeadersReturnErr = err
+}
+
+func (writer *MockHeaderWriter) WriteHeaders(headers []core.Header) error {
+	writer.writeHeadersCallCount++
+	for _, header := range headers {
+		writer.writeHeadersPassedBlockNumbers = append(writer.writeHeadersPassedBlockNumbers, header.BlockNumber)
+	}
+	writer.writeHeadersPassedHeaders = append(writer.writeHeadersPassedHeaders, headers...)
+	return writer.writeHeadersReturnErr
+}
+
+func (writer *MockHeaderWriter) AssertWriteHeadersCallCountEquals(times int) {
+	Expect(writer.writeHeadersCallCount).To(Equal(times))
+}
+
+func (writer *MockHeaderWriter) AssertWriteHeadersCalledWithBlockNumbers(blockNumbers []int64) {
+	Expect(writer.writeHeadersPassedBlockNumbers).To(Equal(blockNumbers))
+}
+
+func (writer *MockHeaderWriter) AssertWriteHeadersCalledWith(headers []core.Header) {
+	Expect(writer.writeHeadersPassedHeaders).To(Equal(headers))
+}
@@ -17,8 +17,11 @@
 package fakes
 
 import (
+	"math/big"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
 	. "github.com/onsi/gomega"
 )
 
@@ -34,12 +37,23 @@ type MockLevelDatabaseReader struct {
 	getCanonicalHashCalled       bool
 	getCanonicalHashPassedNumber uint64
 	getCanonicalHashReturnHash   common.Hash
+	getChainConfigCalled         bool
+	getChainConfigPassedHash     common.Hash
+	getHeaderCalled              bool
+	getHeaderPassedHash          common.Hash
+	getHeaderPassedNumber        uint64
 	getHeadBlockHashCalled       bool
 	getHeadBlockHashReturnHash   common.Hash
+	getTotalDifficultyCalled     bool
+	getTotalDifficultyPassedHash common.Hash
+	getTotalDifficultyPassedNum  uint64
 	passedHash                   common.Hash
 	returnBlock                  *types.Block
 	returnBlockNumber            uint64
+	returnChainConfig            *params.ChainConfig
+	returnHeader                 *types.Header
 	returnReceipts               types.Receipts
+	returnTotalDifficulty        *big.Int
 }
 
 func NewMockLevelDatabaseReader() *MockLevelDatabaseReader {
@@ -76,10 +90,22 @@ func (mldr *MockLevelDatabaseReader) SetGetCanonicalHashReturnHash(hash common.H
 	mldr.getCanonicalHashReturnHash = hash
 }
 
+func (mldr *MockLevelDatabaseReader) SetReturnChainConfig(chainConfig *params.ChainConfig) {
+	mldr.returnChainConfig = chainConfig
+}
+
 func (mldr *MockLevelDatabaseReader) SetHeadBlockHashReturnHash(hash common.Hash) {
 	mldr.getHeadBlockHashReturnHash = hash
 }
 
+func (mldr *MockLevelDatabaseReader) SetReturnTotalDifficulty(td *big.Int) {
+	mldr.returnTotalDifficulty = td
+}
+
+func (mldr *MockLevelDatabaseReader) SetReturnHeader(header *types.Header) {
+	mldr.returnHeader = header
+}
+
 func (mldr *MockLevelDatabaseReader) SetReturnReceipts(receipts types.Receipts) {
 	mldr.returnReceipts = receipts
 }
@@ -110,11 +136,31 @@ func (mldr *MockLevelDatabaseReader) GetCanonicalHash(number uint64) common.Hash
 	return mldr.getCanonicalHashReturnHash
 }
 
+func (mldr *MockLevelDatabaseReader) GetChainConfig(genesisHash common.Hash) *params.ChainConfig {
+	mldr.getChainConfigCalled = true
+	mldr.getChainConfigPassedHash = genesisHash
+	return mldr.returnChainConfig
+}
+
+func (mldr *MockLevelDatabaseReader) GetHeader(hash common.Hash, number uint64) *types.Header {
+	mldr.getHeaderCalled = true
+	mldr.getHeaderPassedHash = hash
+	mldr.getHeaderPassedNumber = number
+	return mldr.returnHeader
+}
+
 func (mldr *MockLevelDatabaseReader) GetHeadBlockHash() common.Hash {
 	mldr.getHeadBlockHashCalled = true
 	return mldr.getHeadBlockHashReturnHash
 }
 
+func (mldr *MockLevelDatabaseReader) GetTotalDifficulty(hash common.Hash, number uint64) *big.Int {
+	mldr.getTotalDifficultyCalled = true
+	mldr.getTotalDifficultyPassedHash = hash
+	mldr.getTotalDifficultyPassedNum = number
+	return mldr.returnTotalDifficulty
+}
+
 func (mldr *MockLevelDatabaseReader) AssertGetBlockCalledWith(hash common.Hash, number uint64) {
 	Expect(mldr.getBlockCalled).To(BeTrue())
 	Expect(mldr.getBlockPassedHash).To(Equal(hash))
@@ -137,6 +183,23 @@ func (mldr *MockLevelDatabaseReader) AssertGetCanonicalHashCalledWith(number uin
 	Expect(mldr.getCanonicalHashPassedNumber).To(Equal(number))
 }
 
+func (mldr *MockLevelDatabaseReader) AssertGetChainConfigCalledWith(genesisHash common.Hash) {
+	Expect(mldr.getChainConfigCalled).To(BeTrue())
+	Expect(mldr.getChainConfigPassedHash).To(Equal(genesisHash))
+}
+
+func (mldr *MockLevelDatabaseReader) AssertGetHeaderCalledWith(hash common.Hash, number uint64) {
+	Expect(mldr.getHeaderCalled).To(BeTrue())
+	Expect(mldr.getHeaderPassedHash).To(Equal(hash))
+	Expect(mldr.getHeaderPassedNumber).To(Equal(number))
+}
+
 func (mldr *MockLevelDatabaseReader) AssertGetHeadBlockHashCalled() {
 	Expect(mldr.getHeadBlockHashCalled).To(BeTrue())
 }
+
+func (mldr *MockLevelDatabaseReader) AssertGetTotalDifficultyCalledWith(hash common.Hash, number uint64) {
+	Expect(mldr.getTotalDifficultyCalled).To(BeTrue())
+	Expect(mldr.getTotalDifficultyPassedHash).To(Equal(hash))
+	Expect(mldr.getTotalDifficultyPassedNum).To(Equal(number))
+}
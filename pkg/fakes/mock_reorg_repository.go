@@ -0,0 +1,70 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package fakes
+
+import (
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+)
+
+type MockReorgRepository struct {
+	createReorgCalled       bool
+	createReorgPassedReorgs []core.Reorg
+	createReorgPassedBlocks []core.Block
+	createReorgReturnErr    error
+	getReorgsReturnReorgs   []core.Reorg
+}
+
+func NewMockReorgRepository() *MockReorgRepository {
+	return &MockReorgRepository{}
+}
+
+func (repository *MockReorgRepository) SetCreateReorgReturnErr(err error) {
+	repository.createReorgReturnErr = err
+}
+
+func (repository *MockReorgRepository) SetGetReorgsReturnReorgs(reorgs []core.Reorg) {
+	repository.getReorgsReturnReorgs = reorgs
+}
+
+func (repository *MockReorgRepository) CreateReorg(reorg core.Reorg, replacements []core.Block) (int64, error) {
+	repository.createReorgCalled = true
+	repository.createReorgPassedReorgs = append(repository.createReorgPassedReorgs, reorg)
+	repository.createReorgPassedBlocks = append(repository.createReorgPassedBlocks, replacements...)
+	return int64(len(repository.createReorgPassedReorgs)), repository.createReorgReturnErr
+}
+
+func (repository *MockReorgRepository) GetReorgs(afterId int64) ([]core.Reorg, error) {
+	return repository.getReorgsReturnReorgs, nil
+}
+
+func (repository *MockReorgRepository) AssertCreateReorgNotCalled() {
+	Expect(repository.createReorgCalled).To(BeFalse())
+}
+
+func (repository *MockReorgRepository) AssertCreateReorgCalledWith(reorgs []core.Reorg) {
+	Expect(repository.createReorgCalled).To(BeTrue())
+	Expect(repository.createReorgPassedReorgs).To(Equal(reorgs))
+}
+
+func (repository *MockReorgRepository) AssertCreateReorgReplacedBlockNumbers(blockNumbers []int64) {
+	var passedNumbers []int64
+	for _, block := range repository.createReorgPassedBlocks {
+		passedNumbers = append(passedNumbers, block.Number)
+	}
+	Expect(passedNumbers).To(Equal(blockNumbers))
+}
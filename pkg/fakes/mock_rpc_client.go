@@ -18,34 +18,94 @@ package fakes
 
 import (
 	"context"
+	"encoding/json"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/rpc"
 	. "github.com/onsi/gomega"
 
 	"github.com/vulcanize/vulcanizedb/pkg/core"
 )
 
 type MockRpcClient struct {
-	callContextErr   error
-	ipcPath          string
-	nodeType         core.NodeType
-	passedContext    context.Context
-	passedMethod     string
-	passedResult     interface{}
-	returnPOAHeader  core.POAHeader
-	supportedModules map[string]string
+	batchCallErr        error
+	batchCallElemErr    error
+	callContextErr      error
+	clientVersion       string
+	ipcPath             string
+	nodeType            core.NodeType
+	passedBatch         []rpc.BatchElem
+	passedContext       context.Context
+	passedMethod        string
+	passedResult        interface{}
+	returnPOAHeader     core.POAHeader
+	returnRawBlock      json.RawMessage
+	supportedModules    map[string]string
+	transactionReceipts map[common.Hash]*types.Receipt
 }
 
 func NewMockRpcClient() *MockRpcClient {
-	return &MockRpcClient{}
+	return &MockRpcClient{
+		transactionReceipts: make(map[common.Hash]*types.Receipt),
+	}
+}
+
+func (client *MockRpcClient) SetBatchCallErr(err error) {
+	client.batchCallErr = err
+}
+
+func (client *MockRpcClient) SetBatchCallElemErr(err error) {
+	client.batchCallElemErr = err
+}
+
+func (client *MockRpcClient) SetTransactionReceipts(receipts []*types.Receipt) {
+	for _, receipt := range receipts {
+		client.transactionReceipts[receipt.TxHash] = receipt
+	}
+}
+
+func (client *MockRpcClient) BatchCall(batch []rpc.BatchElem) error {
+	client.passedBatch = batch
+	if client.batchCallErr != nil {
+		return client.batchCallErr
+	}
+	for i, elem := range batch {
+		batch[i].Error = client.batchCallElemErr
+		if p, ok := elem.Result.(**types.Header); ok && elem.Method == "eth_getUncleByBlockHashAndIndex" {
+			*p = &types.Header{Number: big.NewInt(int64(i))}
+			continue
+		}
+		if elem.Method == "eth_getTransactionByBlockHashAndIndex" {
+			json.Unmarshal([]byte(`{"from":"0x0000000000000000000000000000000000000123"}`), elem.Result)
+			continue
+		}
+		if elem.Method != "eth_getTransactionReceipt" {
+			continue
+		}
+		if p, ok := elem.Result.(**types.Receipt); ok {
+			txHash := elem.Args[0].(common.Hash)
+			if receipt, ok := client.transactionReceipts[txHash]; ok {
+				*p = receipt
+			} else {
+				*p = &types.Receipt{TxHash: txHash}
+			}
+		}
+	}
+	return nil
 }
 
 func (client *MockRpcClient) SetIpcPath(ipcPath string) {
 	client.ipcPath = ipcPath
 }
 
+func (client *MockRpcClient) SetClientVersion(clientVersion string) {
+	client.clientVersion = clientVersion
+}
+
 func (client *MockRpcClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
 	client.passedContext = ctx
 	client.passedResult = result
@@ -63,6 +123,9 @@ func (client *MockRpcClient) CallContext(ctx context.Context, result interface{}
 		if p, ok := result.(*core.POAHeader); ok {
 			*p = client.returnPOAHeader
 		}
+		if p, ok := result.(*json.RawMessage); ok {
+			*p = client.returnRawBlock
+		}
 		if client.callContextErr != nil {
 			return client.callContextErr
 		}
@@ -82,6 +145,10 @@ func (client *MockRpcClient) CallContext(ctx context.Context, result interface{}
 		if p, ok := result.(*string); ok {
 			*p = "enode://ParityNode@172.17.0.1:30303"
 		}
+	case "web3_clientVersion":
+		if p, ok := result.(*string); ok {
+			*p = client.clientVersion
+		}
 	case "net_version":
 		if p, ok := result.(*string); ok {
 			*p = "1234"
@@ -110,8 +177,37 @@ func (client *MockRpcClient) SetReturnPOAHeader(header core.POAHeader) {
 	client.returnPOAHeader = header
 }
 
+// SetReturnRawBlock sets the eth_getBlockByNumber response, with the header's fields,
+// totalDifficulty when given, and transactions and uncle hashes
+func (client *MockRpcClient) SetReturnRawBlock(header *types.Header, totalDifficulty *big.Int, transactions []*types.Transaction, uncleHashes []common.Hash) {
+	fields := map[string]interface{}{}
+	encodedHeader, err := json.Marshal(header)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(json.Unmarshal(encodedHeader, &fields)).To(Succeed())
+	if totalDifficulty != nil {
+		fields["totalDifficulty"] = (*hexutil.Big)(totalDifficulty)
+	}
+	if transactions == nil {
+		transactions = []*types.Transaction{}
+	}
+	if uncleHashes == nil {
+		uncleHashes = []common.Hash{}
+	}
+	fields["transactions"] = transactions
+	fields["uncles"] = uncleHashes
+	client.returnRawBlock, err = json.Marshal(fields)
+	Expect(err).NotTo(HaveOccurred())
+}
+
 func (client *MockRpcClient) AssertCallContextCalledWith(ctx context.Context, result interface{}, method string) {
 	Expect(client.passedContext).To(Equal(ctx))
 	Expect(client.passedResult).To(BeAssignableToTypeOf(result))
 	Expect(client.passedMethod).To(Equal(method))
 }
+
+func (client *MockRpcClient) AssertBatchCallCalledWith(method string, batchSize int) {
+	Expect(len(client.passedBatch)).To(Equal(batchSize))
+	for _, elem := range client.passedBatch {
+		Expect(elem.Method).To(Equal(method))
+	}
+}
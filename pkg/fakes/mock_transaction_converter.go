@@ -17,6 +17,8 @@
 package fakes
 
 import (
+	"sync"
+
 	"github.com/ethereum/go-ethereum/core/types"
 	. "github.com/onsi/gomega"
 
@@ -24,6 +26,7 @@ import (
 )
 
 type MockTransactionConverter struct {
+	mutex                                       sync.Mutex
 	convertTransactionsToCoreCalled             bool
 	convertTransactionsToCorePassedBlock        *types.Block
 	convertTransactionsToCoreReturnTransactions []core.Transaction
@@ -45,6 +48,8 @@ func (mtc *MockTransactionConverter) SetConvertTransactionsToCoreReturnVals(tran
 }
 
 func (mtc *MockTransactionConverter) ConvertTransactionsToCore(gethBlock *types.Block) ([]core.Transaction, error) {
+	mtc.mutex.Lock()
+	defer mtc.mutex.Unlock()
 	mtc.convertTransactionsToCoreCalled = true
 	mtc.convertTransactionsToCorePassedBlock = gethBlock
 	return mtc.convertTransactionsToCoreReturnTransactions, mtc.convertTransactionsToCoreReturnError
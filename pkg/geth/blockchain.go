@@ -17,13 +17,16 @@
 package geth
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
 	"golang.org/x/net/context"
 
 	"github.com/vulcanize/vulcanizedb/pkg/core"
@@ -33,29 +36,51 @@ import (
 var ErrEmptyHeader = errors.New("empty header returned over RPC")
 
 type BlockChain struct {
-	blockConverter  vulcCommon.BlockConverter
-	ethClient       core.EthClient
-	headerConverter vulcCommon.HeaderConverter
-	node            core.Node
-	rpcClient       core.RpcClient
+	blockConverter       vulcCommon.BlockConverter
+	ethClient            core.EthClient
+	headerConverter      vulcCommon.HeaderConverter
+	node                 core.Node
+	rpcClient            core.RpcClient
+	transactionConverter vulcCommon.TransactionConverter
 }
 
 func NewBlockChain(ethClient core.EthClient, rpcClient core.RpcClient, node core.Node, converter vulcCommon.TransactionConverter) *BlockChain {
+	rewardSchedule, ok := core.RewardScheduleForNetwork(node.NetworkID)
+	if !ok {
+		rewardSchedule = core.MainnetRewardSchedule
+	}
 	return &BlockChain{
-		blockConverter:  vulcCommon.NewBlockConverter(converter),
-		ethClient:       ethClient,
-		headerConverter: vulcCommon.HeaderConverter{},
-		node:            node,
-		rpcClient:       rpcClient,
+		blockConverter:       vulcCommon.NewBlockConverter(converter, rewardSchedule),
+		ethClient:            ethClient,
+		headerConverter:      vulcCommon.HeaderConverter{},
+		node:                 node,
+		rpcClient:            rpcClient,
+		transactionConverter: converter,
 	}
 }
 
+// SetRewardSchedule overrides the block reward schedule chosen from the node's network id
+func (blockChain *BlockChain) SetRewardSchedule(rewardSchedule core.RewardSchedule) {
+	blockChain.blockConverter = vulcCommon.NewBlockConverter(blockChain.transactionConverter, rewardSchedule)
+}
+
+// GetBlockByNumber fetches the block with its transactions in a single eth_getBlockByNumber call,
+// reading the total difficulty from the same response, and its uncles in one batch
 func (blockChain *BlockChain) GetBlockByNumber(blockNumber int64) (block core.Block, err error) {
-	gethBlock, err := blockChain.ethClient.BlockByNumber(context.Background(), big.NewInt(blockNumber))
+	raw, err := blockChain.getRawBlock(blockNumber, true)
+	if err != nil {
+		return block, err
+	}
+	gethBlock, err := blockChain.decodeBlock(raw)
 	if err != nil {
 		return block, err
 	}
-	return blockChain.blockConverter.ToCoreBlock(gethBlock)
+	block, err = blockChain.blockConverter.ToCoreBlock(gethBlock)
+	if err != nil {
+		return block, err
+	}
+	block.TotalDifficulty, err = totalDifficulty(raw)
+	return block, err
 }
 
 func (blockChain *BlockChain) GetHeaderByNumber(blockNumber int64) (header core.Header, err error) {
@@ -66,11 +91,86 @@ func (blockChain *BlockChain) GetHeaderByNumber(blockNumber int64) (header core.
 }
 
 func (blockChain *BlockChain) getPOWHeader(blockNumber int64) (header core.Header, err error) {
-	gethHeader, err := blockChain.ethClient.HeaderByNumber(context.Background(), big.NewInt(blockNumber))
+	raw, err := blockChain.getRawBlock(blockNumber, false)
 	if err != nil {
 		return header, err
 	}
-	return blockChain.headerConverter.Convert(gethHeader, gethHeader.Hash().String())
+	var gethHeader types.Header
+	err = json.Unmarshal(raw, &gethHeader)
+	if err != nil {
+		return header, err
+	}
+	header, err = blockChain.headerConverter.Convert(&gethHeader, gethHeader.Hash().String())
+	if err != nil {
+		return header, err
+	}
+	header.TotalDifficulty, err = totalDifficulty(raw)
+	return header, err
+}
+
+func (blockChain *BlockChain) getRawBlock(blockNumber int64, includeTransactions bool) (json.RawMessage, error) {
+	var raw json.RawMessage
+	blockNumberArg := hexutil.EncodeBig(big.NewInt(blockNumber))
+	err := blockChain.rpcClient.CallContext(context.Background(), &raw, "eth_getBlockByNumber", blockNumberArg, includeTransactions)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, ethereum.NotFound
+	}
+	return raw, nil
+}
+
+// decodeBlock builds a block from an eth_getBlockByNumber response with full transactions,
+// fetching the uncles, which the response only lists by hash, as ethclient does
+func (blockChain *BlockChain) decodeBlock(raw json.RawMessage) (*types.Block, error) {
+	var head types.Header
+	err := json.Unmarshal(raw, &head)
+	if err != nil {
+		return nil, err
+	}
+	var body struct {
+		Hash         common.Hash          `json:"hash"`
+		Transactions []*types.Transaction `json:"transactions"`
+		UncleHashes  []common.Hash        `json:"uncles"`
+	}
+	err = json.Unmarshal(raw, &body)
+	if err != nil {
+		return nil, err
+	}
+	uncles := make([]*types.Header, len(body.UncleHashes))
+	if len(uncles) > 0 {
+		batch := make([]rpc.BatchElem, len(uncles))
+		for i := range batch {
+			batch[i] = rpc.BatchElem{
+				Method: "eth_getUncleByBlockHashAndIndex",
+				Args:   []interface{}{body.Hash, hexutil.EncodeUint64(uint64(i))},
+				Result: &uncles[i],
+			}
+		}
+		err = blockChain.rpcClient.BatchCall(batch)
+		if err != nil {
+			return nil, err
+		}
+		for i, elem := range batch {
+			if elem.Error != nil {
+				return nil, elem.Error
+			}
+			if uncles[i] == nil {
+				return nil, fmt.Errorf("got null header for uncle %d of block %s", i, body.Hash.Hex())
+			}
+		}
+	}
+	return types.NewBlockWithHeader(&head).WithBody(body.Transactions, uncles), nil
+}
+
+func totalDifficulty(raw json.RawMessage) (string, error) {
+	var result core.BlockTotalDifficulty
+	err := json.Unmarshal(raw, &result)
+	if err != nil || result.TotalDifficulty == nil {
+		return "", err
+	}
+	return result.TotalDifficulty.ToInt().String(), nil
 }
 
 func (blockChain *BlockChain) getPOAHeader(blockNumber int64) (header core.Header, err error) {
@@ -84,7 +184,7 @@ func (blockChain *BlockChain) getPOAHeader(blockNumber int64) (header core.Heade
 	if POAHeader.Number == nil {
 		return header, ErrEmptyHeader
 	}
-	return blockChain.headerConverter.Convert(&types.Header{
+	header, err = blockChain.headerConverter.Convert(&types.Header{
 		ParentHash:  POAHeader.ParentHash,
 		UncleHash:   POAHeader.UncleHash,
 		Coinbase:    POAHeader.Coinbase,
@@ -99,6 +199,13 @@ func (blockChain *BlockChain) getPOAHeader(blockNumber int64) (header core.Heade
 		Time:        POAHeader.Time.ToInt(),
 		Extra:       POAHeader.Extra,
 	}, POAHeader.Hash.String())
+	if err != nil {
+		return header, err
+	}
+	if POAHeader.TotalDifficulty != nil {
+		header.TotalDifficulty = POAHeader.TotalDifficulty.ToInt().String()
+	}
+	return header, nil
 }
 
 func (blockChain *BlockChain) GetLogs(contract core.Contract, startingBlockNumber, endingBlockNumber *big.Int) ([]core.Log, error) {
@@ -133,6 +240,10 @@ func (blockChain *BlockChain) LastBlock() *big.Int {
 	return block.Number
 }
 
+func (blockChain *BlockChain) SubscribeNewHead(ctx context.Context, heads chan<- *types.Header) (ethereum.Subscription, error) {
+	return blockChain.ethClient.SubscribeNewHead(ctx, heads)
+}
+
 func (blockChain *BlockChain) Node() core.Node {
 	return blockChain.node
 }
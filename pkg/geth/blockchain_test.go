@@ -18,6 +18,7 @@ package geth_test
 
 import (
 	"context"
+	"encoding/json"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum"
@@ -33,6 +34,11 @@ import (
 	"github.com/vulcanize/vulcanizedb/pkg/geth/converters/cold_db"
 )
 
+// rpcHeader sets the fields a node always returns, which the header's JSON decoding requires
+func rpcHeader(number int64) *types.Header {
+	return &types.Header{Number: big.NewInt(number), Difficulty: big.NewInt(1), Time: big.NewInt(0), Extra: []byte{}}
+}
+
 var _ = Describe("Geth blockchain", func() {
 	var mockClient *fakes.MockEthClient
 	var mockRpcClient *fakes.MockRpcClient
@@ -47,18 +53,44 @@ var _ = Describe("Geth blockchain", func() {
 	})
 
 	Describe("getting a block", func() {
-		It("fetches block from ethClient", func() {
-			mockClient.SetBlockByNumberReturnBlock(types.NewBlockWithHeader(&types.Header{}))
-			blockNumber := int64(100)
+		It("fetches the block with its transactions in a single call", func() {
+			mockRpcClient.SetReturnRawBlock(rpcHeader(100), nil, nil, nil)
 
-			_, err := blockChain.GetBlockByNumber(blockNumber)
+			block, err := blockChain.GetBlockByNumber(100)
 
 			Expect(err).NotTo(HaveOccurred())
-			mockClient.AssertBlockByNumberCalledWith(context.Background(), big.NewInt(blockNumber))
+			Expect(block.Number).To(Equal(int64(100)))
+			mockRpcClient.AssertCallContextCalledWith(context.Background(), &json.RawMessage{}, "eth_getBlockByNumber")
 		})
 
-		It("returns err if ethClient returns err", func() {
-			mockClient.SetBlockByNumberErr(fakes.FakeError)
+		It("reads the block's total difficulty from the same response", func() {
+			mockRpcClient.SetReturnRawBlock(rpcHeader(100), big.NewInt(5000), nil, nil)
+
+			coreBlock, err := blockChain.GetBlockByNumber(100)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(coreBlock.TotalDifficulty).To(Equal("5000"))
+		})
+
+		It("fetches the block's uncles in one batch", func() {
+			uncleHashes := []common.Hash{common.HexToHash("0x1"), common.HexToHash("0x2")}
+			mockRpcClient.SetReturnRawBlock(rpcHeader(100), nil, nil, uncleHashes)
+
+			block, err := blockChain.GetBlockByNumber(100)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(block.Uncles).To(HaveLen(2))
+			mockRpcClient.AssertBatchCallCalledWith("eth_getUncleByBlockHashAndIndex", 2)
+		})
+
+		It("returns not found if the node has no such block", func() {
+			_, err := blockChain.GetBlockByNumber(100)
+
+			Expect(err).To(MatchError(ethereum.NotFound))
+		})
+
+		It("returns err if rpcClient returns err", func() {
+			mockRpcClient.SetCallContextErr(fakes.FakeError)
 
 			_, err := blockChain.GetBlockByNumber(100)
 
@@ -69,18 +101,27 @@ var _ = Describe("Geth blockchain", func() {
 
 	Describe("getting a header", func() {
 		Describe("default/mainnet", func() {
-			It("fetches header from ethClient", func() {
-				blockNumber := int64(100)
-				mockClient.SetHeaderByNumberReturnHeader(&types.Header{Number: big.NewInt(blockNumber)})
+			It("fetches the header from rpcClient", func() {
+				mockRpcClient.SetReturnRawBlock(rpcHeader(100), nil, nil, nil)
 
-				_, err := blockChain.GetHeaderByNumber(blockNumber)
+				header, err := blockChain.GetHeaderByNumber(100)
 
 				Expect(err).NotTo(HaveOccurred())
-				mockClient.AssertHeaderByNumberCalledWith(context.Background(), big.NewInt(blockNumber))
+				Expect(header.BlockNumber).To(Equal(int64(100)))
+				mockRpcClient.AssertCallContextCalledWith(context.Background(), &json.RawMessage{}, "eth_getBlockByNumber")
 			})
 
-			It("returns err if ethClient returns err", func() {
-				mockClient.SetHeaderByNumberErr(fakes.FakeError)
+			It("reads the header's total difficulty from the same response", func() {
+				mockRpcClient.SetReturnRawBlock(rpcHeader(100), big.NewInt(5000), nil, nil)
+
+				header, err := blockChain.GetHeaderByNumber(100)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(header.TotalDifficulty).To(Equal("5000"))
+			})
+
+			It("returns err if rpcClient returns err", func() {
+				mockRpcClient.SetCallContextErr(fakes.FakeError)
 
 				_, err := blockChain.GetHeaderByNumber(100)
 
@@ -102,6 +143,19 @@ var _ = Describe("Geth blockchain", func() {
 				mockRpcClient.AssertCallContextCalledWith(context.Background(), &vulcCore.POAHeader{}, "eth_getBlockByNumber")
 			})
 
+			It("includes the total difficulty returned with the header", func() {
+				node.NetworkID = vulcCore.KOVAN_NETWORK_ID
+				blockNumber := hexutil.Big(*big.NewInt(123))
+				totalDifficulty := hexutil.Big(*big.NewInt(5000))
+				mockRpcClient.SetReturnPOAHeader(vulcCore.POAHeader{Number: &blockNumber, TotalDifficulty: &totalDifficulty})
+				blockChain = geth.NewBlockChain(mockClient, mockRpcClient, node, cold_db.NewColdDbTransactionConverter())
+
+				header, err := blockChain.GetHeaderByNumber(100)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(header.TotalDifficulty).To(Equal("5000"))
+			})
+
 			It("returns err if rpcClient returns err", func() {
 				node.NetworkID = vulcCore.KOVAN_NETWORK_ID
 				mockRpcClient.SetCallContextErr(fakes.FakeError)
@@ -206,4 +260,24 @@ var _ = Describe("Geth blockchain", func() {
 			Expect(result).To(Equal(big.NewInt(blockNumber)))
 		})
 	})
+
+	Describe("subscribing to new heads", func() {
+		It("subscribes with ethClient", func() {
+			heads := make(chan *types.Header)
+
+			_, err := blockChain.SubscribeNewHead(context.Background(), heads)
+
+			Expect(err).NotTo(HaveOccurred())
+			mockClient.AssertSubscribeNewHeadCalledWith(heads)
+		})
+
+		It("returns err if ethClient returns err", func() {
+			mockClient.SetSubscribeNewHeadErr(fakes.FakeError)
+
+			_, err := blockChain.SubscribeNewHead(context.Background(), make(chan *types.Header))
+
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(MatchError(fakes.FakeError))
+		})
+	})
 })
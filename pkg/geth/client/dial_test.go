@@ -0,0 +1,60 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package client_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/geth/client"
+)
+
+var _ = Describe("Dialing an rpc endpoint", func() {
+	It("sends the configured headers to http endpoints", func() {
+		var authorization string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorization = r.Header.Get("Authorization")
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"1"}`))
+		}))
+		defer server.Close()
+
+		rpcClient, err := client.Dial(server.URL, map[string]string{"authorization": "Bearer token"})
+		Expect(err).NotTo(HaveOccurred())
+		var version string
+		err = rpcClient.Call(&version, "net_version")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(version).To(Equal("1"))
+		Expect(authorization).To(Equal("Bearer token"))
+	})
+
+	It("rejects headers other than origin for websocket endpoints", func() {
+		_, err := client.Dial("ws://localhost:8546", map[string]string{"Authorization": "Bearer token"})
+
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("rejects unsupported schemes", func() {
+		_, err := client.Dial("ftp://localhost", nil)
+
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -49,6 +49,10 @@ func (client EthClient) HeaderByNumber(ctx context.Context, number *big.Int) (*t
 	return client.client.HeaderByNumber(ctx, number)
 }
 
+func (client EthClient) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return client.client.SubscribeNewHead(ctx, ch)
+}
+
 func (client EthClient) TransactionSender(ctx context.Context, tx *types.Transaction, block common.Hash, index uint) (common.Address, error) {
 	return client.client.TransactionSender(ctx, tx, block, index)
 }
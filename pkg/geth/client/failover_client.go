@@ -0,0 +1,318 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+)
+
+const (
+	methodNotFoundCode = -32601
+	healthCheckTimeout = 5 * time.Second
+)
+
+type Endpoint struct {
+	Name      string
+	EthClient core.EthClient
+	RpcClient core.RpcClient
+	// Maximum number of requests per second sent to the endpoint, unlimited when 0
+	RateLimit float64
+}
+
+// FailoverClient implements core.EthClient and core.RpcClient on top of several endpoints.
+// Requests go to the first healthy endpoint in the configured order and fail over to the
+// next one on connection errors. Endpoints are marked unhealthy when a request fails or,
+// during health checks, when their head lags the best known head by more than maxHeadLag.
+type FailoverClient struct {
+	endpoints  []*endpoint
+	maxHeadLag int64
+}
+
+type endpoint struct {
+	Endpoint
+	limiter *rateLimiter
+	mutex   sync.RWMutex
+	healthy bool
+}
+
+func NewFailoverClient(endpoints []Endpoint, maxHeadLag int64) *FailoverClient {
+	client := &FailoverClient{maxHeadLag: maxHeadLag}
+	for _, e := range endpoints {
+		client.endpoints = append(client.endpoints, &endpoint{
+			Endpoint: e,
+			limiter:  newRateLimiter(e.RateLimit),
+			healthy:  true,
+		})
+	}
+	return client
+}
+
+// MonitorHealth checks the health of all endpoints every interval in the background
+func (client *FailoverClient) MonitorHealth(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			client.CheckHealth()
+		}
+	}()
+}
+
+func (client *FailoverClient) CheckHealth() {
+	var best int64
+	heads := make([]int64, len(client.endpoints))
+	errs := make([]error, len(client.endpoints))
+	for i, e := range client.endpoints {
+		heads[i], errs[i] = e.latestHead()
+		if errs[i] == nil && heads[i] > best {
+			best = heads[i]
+		}
+	}
+	for i, e := range client.endpoints {
+		if errs[i] != nil {
+			e.setHealthy(false, errs[i].Error())
+			continue
+		}
+		if best-heads[i] > client.maxHeadLag {
+			e.setHealthy(false, fmt.Sprintf("head lagging %d blocks behind", best-heads[i]))
+			continue
+		}
+		e.setHealthy(true, "")
+	}
+}
+
+func (client *FailoverClient) BlockByNumber(ctx context.Context, number *big.Int) (block *types.Block, err error) {
+	err = client.do(1, func(e *endpoint) error {
+		block, err = e.EthClient.BlockByNumber(ctx, number)
+		return err
+	})
+	return block, err
+}
+
+func (client *FailoverClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) (result []byte, err error) {
+	err = client.do(1, func(e *endpoint) error {
+		result, err = e.EthClient.CallContract(ctx, msg, blockNumber)
+		return err
+	})
+	return result, err
+}
+
+func (client *FailoverClient) FilterLogs(ctx context.Context, q ethereum.FilterQuery) (logs []types.Log, err error) {
+	err = client.do(1, func(e *endpoint) error {
+		logs, err = e.EthClient.FilterLogs(ctx, q)
+		return err
+	})
+	return logs, err
+}
+
+func (client *FailoverClient) HeaderByNumber(ctx context.Context, number *big.Int) (header *types.Header, err error) {
+	err = client.do(1, func(e *endpoint) error {
+		header, err = e.EthClient.HeaderByNumber(ctx, number)
+		return err
+	})
+	return header, err
+}
+
+func (client *FailoverClient) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (subscription ethereum.Subscription, err error) {
+	err = client.do(1, func(e *endpoint) error {
+		subscription, err = e.EthClient.SubscribeNewHead(ctx, ch)
+		if err == rpc.ErrNotificationsUnsupported {
+			return errTryNext{err}
+		}
+		return err
+	})
+	return subscription, err
+}
+
+func (client *FailoverClient) TransactionSender(ctx context.Context, tx *types.Transaction, block common.Hash, index uint) (sender common.Address, err error) {
+	err = client.do(1, func(e *endpoint) error {
+		sender, err = e.EthClient.TransactionSender(ctx, tx, block, index)
+		return err
+	})
+	return sender, err
+}
+
+func (client *FailoverClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (receipt *types.Receipt, err error) {
+	err = client.do(1, func(e *endpoint) error {
+		receipt, err = e.EthClient.TransactionReceipt(ctx, txHash)
+		return err
+	})
+	return receipt, err
+}
+
+func (client *FailoverClient) BatchCall(batch []rpc.BatchElem) error {
+	return client.do(len(batch), func(e *endpoint) error {
+		return e.RpcClient.BatchCall(batch)
+	})
+}
+
+func (client *FailoverClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return client.do(1, func(e *endpoint) error {
+		return e.RpcClient.CallContext(ctx, result, method, args...)
+	})
+}
+
+func (client *FailoverClient) IpcPath() string {
+	endpoints := client.orderedEndpoints()
+	if len(endpoints) == 0 {
+		return ""
+	}
+	return endpoints[0].RpcClient.IpcPath()
+}
+
+func (client *FailoverClient) SupportedModules() (modules map[string]string, err error) {
+	err = client.do(1, func(e *endpoint) error {
+		modules, err = e.RpcClient.SupportedModules()
+		return err
+	})
+	return modules, err
+}
+
+// errTryNext marks errors on which the next endpoint is tried without marking the endpoint unhealthy
+type errTryNext struct {
+	error
+}
+
+func (client *FailoverClient) do(requests int, call func(e *endpoint) error) error {
+	var err error
+	for _, e := range client.orderedEndpoints() {
+		e.limiter.wait(requests)
+		err = call(e)
+		switch classify(err) {
+		case done:
+			e.setHealthy(true, "")
+			return err
+		case tryNext:
+			if next, ok := err.(errTryNext); ok {
+				err = next.error
+			}
+		case failOver:
+			e.setHealthy(false, err.Error())
+		}
+	}
+	return err
+}
+
+type outcome int
+
+const (
+	done outcome = iota
+	tryNext
+	failOver
+)
+
+func classify(err error) outcome {
+	if err == nil || err == context.Canceled {
+		return done
+	}
+	if _, ok := err.(errTryNext); ok || err == ethereum.NotFound {
+		return tryNext
+	}
+	if rpcErr, ok := err.(rpc.Error); ok {
+		// errors returned by the node itself, only an unavailable method is worth retrying elsewhere
+		if rpcErr.ErrorCode() == methodNotFoundCode {
+			return tryNext
+		}
+		return done
+	}
+	return failOver
+}
+
+// orderedEndpoints returns healthy endpoints in configured order, followed by unhealthy ones as a last resort
+func (client *FailoverClient) orderedEndpoints() []*endpoint {
+	var healthy, unhealthy []*endpoint
+	for _, e := range client.endpoints {
+		if e.isHealthy() {
+			healthy = append(healthy, e)
+		} else {
+			unhealthy = append(unhealthy, e)
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+func (e *endpoint) latestHead() (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+	e.limiter.wait(1)
+	header, err := e.EthClient.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	return header.Number.Int64(), nil
+}
+
+func (e *endpoint) isHealthy() bool {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	return e.healthy
+}
+
+func (e *endpoint) setHealthy(healthy bool, reason string) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if e.healthy == healthy {
+		return
+	}
+	e.healthy = healthy
+	if healthy {
+		log.Printf("rpc endpoint %s is healthy again\n", e.Name)
+	} else {
+		log.Printf("rpc endpoint %s is unhealthy, failing over: %s\n", e.Name, reason)
+	}
+}
+
+type rateLimiter struct {
+	mutex    sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(requestsPerSecond float64) *rateLimiter {
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / requestsPerSecond)}
+}
+
+// wait blocks until the given number of requests can be sent without exceeding the rate limit
+func (limiter *rateLimiter) wait(requests int) {
+	if limiter == nil {
+		return
+	}
+	limiter.mutex.Lock()
+	now := time.Now()
+	if limiter.next.Before(now) {
+		limiter.next = now
+	}
+	delay := limiter.next.Sub(now)
+	limiter.next = limiter.next.Add(time.Duration(requests) * limiter.interval)
+	limiter.mutex.Unlock()
+	time.Sleep(delay)
+}
@@ -0,0 +1,142 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package client_test
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+	"github.com/vulcanize/vulcanizedb/pkg/geth/client"
+)
+
+type nodeError struct{}
+
+func (nodeError) Error() string  { return "execution reverted" }
+func (nodeError) ErrorCode() int { return -32000 }
+
+var _ = Describe("Failover client", func() {
+	var (
+		primary   *fakes.MockEthClient
+		secondary *fakes.MockEthClient
+		endpoints []client.Endpoint
+	)
+
+	BeforeEach(func() {
+		log.SetOutput(ioutil.Discard)
+		primary = fakes.NewMockEthClient()
+		primary.SetHeaderByNumberReturnHeader(&types.Header{Number: big.NewInt(100)})
+		secondary = fakes.NewMockEthClient()
+		secondary.SetHeaderByNumberReturnHeader(&types.Header{Number: big.NewInt(200)})
+		endpoints = []client.Endpoint{
+			{Name: "primary", EthClient: primary, RpcClient: fakes.NewMockRpcClient()},
+			{Name: "secondary", EthClient: secondary, RpcClient: fakes.NewMockRpcClient()},
+		}
+	})
+
+	AfterEach(func() {
+		log.SetOutput(os.Stdout)
+	})
+
+	It("sends requests to the first endpoint", func() {
+		failoverClient := client.NewFailoverClient(endpoints, 5)
+
+		header, err := failoverClient.HeaderByNumber(context.Background(), nil)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(header.Number.Int64()).To(Equal(int64(100)))
+	})
+
+	It("fails over to the next endpoint on errors", func() {
+		primary.SetHeaderByNumberErr(fakes.FakeError)
+		failoverClient := client.NewFailoverClient(endpoints, 5)
+
+		header, err := failoverClient.HeaderByNumber(context.Background(), nil)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(header.Number.Int64()).To(Equal(int64(200)))
+	})
+
+	It("returns the last error when all endpoints fail", func() {
+		primary.SetHeaderByNumberErr(fakes.FakeError)
+		secondary.SetHeaderByNumberErr(fakes.FakeError)
+		failoverClient := client.NewFailoverClient(endpoints, 5)
+
+		_, err := failoverClient.HeaderByNumber(context.Background(), nil)
+
+		Expect(err).To(MatchError(fakes.FakeError))
+	})
+
+	It("does not fail over on errors returned by the node", func() {
+		primary.SetCallContractErr(nodeError{})
+		secondary.SetCallContractReturnBytes([]byte{1})
+		failoverClient := client.NewFailoverClient(endpoints, 5)
+
+		_, err := failoverClient.CallContract(context.Background(), ethereum.CallMsg{}, nil)
+
+		Expect(err).To(MatchError(nodeError{}))
+	})
+
+	It("skips endpoints whose head lags behind", func() {
+		failoverClient := client.NewFailoverClient(endpoints, 5)
+
+		failoverClient.CheckHealth()
+		header, err := failoverClient.HeaderByNumber(context.Background(), nil)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(header.Number.Int64()).To(Equal(int64(200)))
+	})
+
+	It("fails over batch calls", func() {
+		failing := fakes.NewMockRpcClient()
+		failing.SetBatchCallErr(fakes.FakeError)
+		working := fakes.NewMockRpcClient()
+		endpoints[0].RpcClient = failing
+		endpoints[1].RpcClient = working
+		failoverClient := client.NewFailoverClient(endpoints, 5)
+		batch := []rpc.BatchElem{{Method: "eth_getTransactionReceipt", Args: []interface{}{common.Hash{}}, Result: new(*types.Receipt)}}
+
+		err := failoverClient.BatchCall(batch)
+
+		Expect(err).NotTo(HaveOccurred())
+		working.AssertBatchCallCalledWith("eth_getTransactionReceipt", 1)
+	})
+
+	It("limits the request rate per endpoint", func() {
+		endpoints[0].RateLimit = 20
+		failoverClient := client.NewFailoverClient(endpoints, 5)
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			_, err := failoverClient.HeaderByNumber(context.Background(), nil)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		Expect(time.Since(start)).To(BeNumerically(">=", 100*time.Millisecond))
+	})
+})
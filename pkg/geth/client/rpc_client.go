@@ -34,7 +34,11 @@ func NewRpcClient(client *rpc.Client, ipcPath string) RpcClient {
 }
 
 func (client RpcClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
-	return client.client.CallContext(ctx, result, method, args)
+	return client.client.CallContext(ctx, result, method, args...)
+}
+
+func (client RpcClient) BatchCall(batch []rpc.BatchElem) error {
+	return client.client.BatchCall(batch)
 }
 
 func (client RpcClient) IpcPath() string {
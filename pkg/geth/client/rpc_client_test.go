@@ -0,0 +1,85 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/geth/client"
+)
+
+var _ = Describe("Rpc client", func() {
+	It("sends batch calls in a single request", func() {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			body, _ := ioutil.ReadAll(r.Body)
+			var batch []map[string]interface{}
+			Expect(json.Unmarshal(body, &batch)).To(Succeed())
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[{"jsonrpc":"2.0","id":1,"result":"0x1"},{"jsonrpc":"2.0","id":2,"result":"0x2"}]`))
+		}))
+		defer server.Close()
+		rawRpcClient, err := client.Dial(server.URL, nil)
+		Expect(err).NotTo(HaveOccurred())
+		rpcClient := client.NewRpcClient(rawRpcClient, server.URL)
+		var first, second string
+		batch := []rpc.BatchElem{
+			{Method: "eth_getTransactionReceipt", Args: []interface{}{"0x1"}, Result: &first},
+			{Method: "eth_getTransactionReceipt", Args: []interface{}{"0x2"}, Result: &second},
+		}
+
+		err = rpcClient.BatchCall(batch)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requests).To(Equal(1))
+		Expect(first).To(Equal("0x1"))
+		Expect(second).To(Equal("0x2"))
+	})
+
+	It("sends call arguments as positional params", func() {
+		var params []interface{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			var request struct {
+				Params []interface{} `json:"params"`
+			}
+			Expect(json.Unmarshal(body, &request)).To(Succeed())
+			params = request.Params
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":null}`))
+		}))
+		defer server.Close()
+		rawRpcClient, err := client.Dial(server.URL, nil)
+		Expect(err).NotTo(HaveOccurred())
+		rpcClient := client.NewRpcClient(rawRpcClient, server.URL)
+		var result json.RawMessage
+
+		err = rpcClient.CallContext(context.Background(), &result, "eth_getBlockByNumber", "0x64", false)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(params).To(Equal([]interface{}{"0x64", false}))
+	})
+})
@@ -0,0 +1,87 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cold_import
+
+import (
+	"fmt"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/ethereum"
+	"github.com/vulcanize/vulcanizedb/pkg/geth/converters/common"
+)
+
+// ColdHeaderImporter populates the headers used by light sync transformers straight from LevelDB
+type ColdHeaderImporter struct {
+	config           ColdImportConfig
+	converter        common.HeaderConverter
+	ethDB            ethereum.Database
+	finalityDepth    int64
+	headerRepository datastore.HeaderRepository
+	headerWriter     datastore.HeaderWriter
+}
+
+func NewColdHeaderImporter(ethDB ethereum.Database, headerRepository datastore.HeaderRepository, headerWriter datastore.HeaderWriter, finalityDepth int64, config ColdImportConfig) *ColdHeaderImporter {
+	return &ColdHeaderImporter{
+		config:           config.withDefaults(),
+		converter:        common.HeaderConverter{},
+		ethDB:            ethDB,
+		finalityDepth:    finalityDepth,
+		headerRepository: headerRepository,
+		headerWriter:     headerWriter,
+	}
+}
+
+// Execute imports the missing headers in the range through the same pipeline as blocks, writing
+// them a batch at a time. Headers already stored for the node are skipped, so an interrupted
+// import picks up where it stopped.
+func (ci *ColdHeaderImporter) Execute(startingBlockNumber int64, endingBlockNumber int64, nodeId string) error {
+	missingBlockNumbers := ci.headerRepository.MissingBlockNumbers(startingBlockNumber, endingBlockNumber, nodeId)
+	err := runImport(ci.config, missingBlockNumbers, importStages{
+		read:    ci.readHeader,
+		convert: ci.convertHeader,
+		write:   ci.writeHeaders,
+	}, nil)
+	if err != nil {
+		return err
+	}
+	return ci.headerRepository.SetHeadersStatus(endingBlockNumber, ci.finalityDepth)
+}
+
+func (ci *ColdHeaderImporter) readHeader(job *importJob) {
+	hash := ci.ethDB.GetBlockHash(job.blockNumber)
+	job.header = ci.ethDB.GetHeader(hash, job.blockNumber)
+	if job.header == nil {
+		job.err = fmt.Errorf("header %d not found in ethereum database", job.blockNumber)
+		return
+	}
+	job.td = ci.ethDB.GetTotalDifficulty(hash, job.blockNumber)
+}
+
+func (ci *ColdHeaderImporter) convertHeader(job *importJob) {
+	job.coreHeader, job.err = ci.converter.Convert(job.header, job.header.Hash().Hex())
+	if job.td != nil {
+		job.coreHeader.TotalDifficulty = job.td.String()
+	}
+}
+
+func (ci *ColdHeaderImporter) writeHeaders(batch []importJob) error {
+	headers := make([]core.Header, len(batch))
+	for i, job := range batch {
+		headers[i] = job.coreHeader
+	}
+	return ci.headerWriter.WriteHeaders(headers)
+}
@@ -0,0 +1,97 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cold_import_test
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+	"github.com/vulcanize/vulcanizedb/pkg/geth/cold_import"
+)
+
+var _ = Describe("Geth cold header importer", func() {
+	var (
+		mockEthereumDatabase *fakes.MockEthereumDatabase
+		mockHeaderRepository *fakes.MockHeaderRepository
+		mockHeaderWriter     *fakes.MockHeaderWriter
+		importer             *cold_import.ColdHeaderImporter
+	)
+
+	BeforeEach(func() {
+		mockEthereumDatabase = fakes.NewMockEthereumDatabase()
+		mockHeaderRepository = fakes.NewMockHeaderRepository()
+		mockHeaderWriter = fakes.NewMockHeaderWriter()
+		importer = cold_import.NewColdHeaderImporter(mockEthereumDatabase, mockHeaderRepository, mockHeaderWriter, 20, cold_import.DefaultColdImportConfig)
+	})
+
+	It("imports missing headers from level db", func() {
+		mockHeaderRepository.SetMissingBlockNumbers([]int64{123})
+		mockEthereumDatabase.SetReturnHash([]byte{1, 2, 3, 4, 5})
+		mockEthereumDatabase.SetReturnHeader(&types.Header{Number: big.NewInt(123), Time: big.NewInt(1500000000)})
+
+		err := importer.Execute(120, 125, "node_id")
+
+		Expect(err).NotTo(HaveOccurred())
+		mockEthereumDatabase.AssertGetBlockHashCalledWith(123)
+		mockEthereumDatabase.AssertGetHeaderCalledWith([]int64{123})
+		mockHeaderWriter.AssertWriteHeadersCallCountEquals(1)
+		mockHeaderWriter.AssertWriteHeadersCalledWithBlockNumbers([]int64{123})
+	})
+
+	It("writes headers in batches", func() {
+		mockHeaderRepository.SetMissingBlockNumbers([]int64{121, 122, 123, 124, 125})
+		mockEthereumDatabase.SetReturnHash([]byte{1, 2, 3, 4, 5})
+		mockEthereumDatabase.SetReturnHeader(&types.Header{Number: big.NewInt(123), Time: big.NewInt(1500000000)})
+		importer = cold_import.NewColdHeaderImporter(mockEthereumDatabase, mockHeaderRepository, mockHeaderWriter, 20, cold_import.ColdImportConfig{Workers: 3, BatchSize: 2})
+
+		err := importer.Execute(120, 125, "node_id")
+
+		Expect(err).NotTo(HaveOccurred())
+		mockHeaderWriter.AssertWriteHeadersCallCountEquals(3)
+	})
+
+	It("sets is_final status on imported headers", func() {
+		mockHeaderRepository.SetMissingBlockNumbers([]int64{})
+
+		err := importer.Execute(120, 125, "node_id")
+
+		Expect(err).NotTo(HaveOccurred())
+		mockHeaderRepository.AssertSetHeadersStatusCalledWith(125, 20)
+	})
+
+	It("returns an error if a header is missing from level db", func() {
+		mockHeaderRepository.SetMissingBlockNumbers([]int64{123})
+
+		err := importer.Execute(120, 125, "node_id")
+
+		Expect(err).To(HaveOccurred())
+		mockHeaderWriter.AssertWriteHeadersCallCountEquals(0)
+	})
+
+	It("returns an error if writing headers fails", func() {
+		mockHeaderRepository.SetMissingBlockNumbers([]int64{123})
+		mockEthereumDatabase.SetReturnHeader(&types.Header{Number: big.NewInt(123), Time: big.NewInt(1500000000)})
+		mockHeaderWriter.SetWriteHeadersReturnErr(fakes.FakeError)
+
+		err := importer.Execute(120, 125, "node_id")
+
+		Expect(err).To(MatchError(fakes.FakeError))
+	})
+})
@@ -13,60 +13,85 @@
 
 // You should have received a copy of the GNU Affero General Public License
 // along with this program.  If not, see <http://www.gnu.org/licenses/>.
-
 package cold_import
 
 import (
+	"log"
+
 	"github.com/vulcanize/vulcanizedb/pkg/datastore"
 	"github.com/vulcanize/vulcanizedb/pkg/datastore/ethereum"
 	"github.com/vulcanize/vulcanizedb/pkg/geth/converters/common"
 )
 
+type ColdImportConfig struct {
+	// Number of concurrent readers against the ethereum database, and of converters
+	Workers int
+	// Number of blocks written together, and between checkpoints and progress reports
+	BatchSize int
+}
+
+var DefaultColdImportConfig = ColdImportConfig{
+	Workers:   4,
+	BatchSize: 1000,
+}
+
 type ColdImporter struct {
-	blockRepository   datastore.BlockRepository
-	converter         common.BlockConverter
-	ethDB             ethereum.Database
-	receiptRepository datastore.ReceiptRepository
+	blockRepository      datastore.BlockRepository
+	blockWriter          datastore.BlockWriter
+	checkpointRepository datastore.CheckpointRepository
+	config               ColdImportConfig
+	converter            common.BlockConverter
+	ethDB                ethereum.Database
+	finalityDepth        int64
 }
 
-func NewColdImporter(ethDB ethereum.Database, blockRepository datastore.BlockRepository, receiptRepository datastore.ReceiptRepository, converter common.BlockConverter) *ColdImporter {
+func NewColdImporter(ethDB ethereum.Database, blockRepository datastore.BlockRepository, blockWriter datastore.BlockWriter, checkpointRepository datastore.CheckpointRepository, converter common.BlockConverter, finalityDepth int64, config ColdImportConfig) *ColdImporter {
 	return &ColdImporter{
-		blockRepository:   blockRepository,
-		converter:         converter,
-		ethDB:             ethDB,
-		receiptRepository: receiptRepository,
+		blockRepository:      blockRepository,
+		blockWriter:          blockWriter,
+		checkpointRepository: checkpointRepository,
+		config:               config.withDefaults(),
+		converter:            converter,
+		ethDB:                ethDB,
+		finalityDepth:        finalityDepth,
 	}
 }
 
+// Execute imports the missing blocks in the range, resuming after the last checkpoint of an
+// earlier import that began at the same starting block number
 func (ci *ColdImporter) Execute(startingBlockNumber int64, endingBlockNumber int64, nodeId string) error {
-	missingBlocks := ci.blockRepository.MissingBlockNumbers(startingBlockNumber, endingBlockNumber, nodeId)
-	for _, n := range missingBlocks {
-		hash := ci.ethDB.GetBlockHash(n)
-
-		blockId, err := ci.createBlocksAndTransactions(hash, n)
+	checkpoint, err := ci.checkpointRepository.GetCheckpoint(nodeId, startingBlockNumber)
+	if err != nil {
+		return err
+	}
+	resumeBlockNumber := startingBlockNumber
+	if checkpoint >= startingBlockNumber {
+		log.Printf("Resuming cold import after checkpoint at block %d\n", checkpoint)
+		resumeBlockNumber = checkpoint + 1
+	}
+	if resumeBlockNumber <= endingBlockNumber {
+		missingBlocks := ci.blockRepository.MissingBlockNumbers(resumeBlockNumber, endingBlockNumber, nodeId)
+		err = ci.importBlocks(missingBlocks, func(lastBlockNumber int64) error {
+			return ci.checkpointRepository.SetCheckpoint(nodeId, startingBlockNumber, lastBlockNumber)
+		})
 		if err != nil {
 			return err
 		}
-		err = ci.createReceiptsAndLogs(hash, n, blockId)
+		err = ci.checkpointRepository.SetCheckpoint(nodeId, startingBlockNumber, endingBlockNumber)
 		if err != nil {
 			return err
 		}
 	}
-	ci.blockRepository.SetBlocksStatus(endingBlockNumber)
+	ci.blockRepository.SetBlocksStatus(endingBlockNumber, ci.finalityDepth)
 	return nil
 }
 
-func (ci *ColdImporter) createBlocksAndTransactions(hash []byte, i int64) (int64, error) {
-	block := ci.ethDB.GetBlock(hash, i)
-	coreBlock, err := ci.converter.ToCoreBlock(block)
-	if err != nil {
-		return 0, err
+func (config ColdImportConfig) withDefaults() ColdImportConfig {
+	if config.Workers < 1 {
+		config.Workers = 1
 	}
-	return ci.blockRepository.CreateOrUpdateBlock(coreBlock)
-}
-
-func (ci *ColdImporter) createReceiptsAndLogs(hash []byte, number int64, blockId int64) error {
-	receipts := ci.ethDB.GetBlockReceipts(hash, number)
-	coreReceipts := common.ToCoreReceipts(receipts)
-	return ci.receiptRepository.CreateReceiptsAndLogs(blockId, coreReceipts)
+	if config.BatchSize < 1 {
+		config.BatchSize = DefaultColdImportConfig.BatchSize
+	}
+	return config
 }
@@ -17,11 +17,14 @@
 package cold_import_test
 
 import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
-	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+	"github.com/vulcanize/vulcanizedb/pkg/core"
 	"github.com/vulcanize/vulcanizedb/pkg/fakes"
 	"github.com/vulcanize/vulcanizedb/pkg/geth/cold_import"
 	vulcCommon "github.com/vulcanize/vulcanizedb/pkg/geth/converters/common"
@@ -41,9 +44,9 @@ var _ = Describe("Geth cold importer", func() {
 	It("only populates missing blocks", func() {
 		mockEthereumDatabase := fakes.NewMockEthereumDatabase()
 		mockBlockRepository := fakes.NewMockBlockRepository()
-		mockReceiptRepository := fakes.NewMockReceiptRepository()
+		mockBlockWriter := fakes.NewMockBlockWriter()
 		mockTransactionConverter := fakes.NewMockTransactionConverter()
-		blockConverter := vulcCommon.NewBlockConverter(mockTransactionConverter)
+		blockConverter := vulcCommon.NewBlockConverter(mockTransactionConverter, core.MainnetRewardSchedule)
 
 		nodeId := "node_id"
 		startingBlockNumber := int64(120)
@@ -53,7 +56,7 @@ var _ = Describe("Geth cold importer", func() {
 		mockBlockRepository.SetMissingBlockNumbersReturnArray([]int64{missingBlockNumber})
 		mockEthereumDatabase.SetReturnHash(fakeHash)
 		mockEthereumDatabase.SetReturnBlock(fakeGethBlock)
-		importer := cold_import.NewColdImporter(mockEthereumDatabase, mockBlockRepository, mockReceiptRepository, blockConverter)
+		importer := cold_import.NewColdImporter(mockEthereumDatabase, mockBlockRepository, mockBlockWriter, fakes.NewMockCheckpointRepository(), blockConverter, 20, cold_import.DefaultColdImportConfig)
 
 		importer.Execute(startingBlockNumber, endingBlockNumber, nodeId)
 
@@ -65,16 +68,16 @@ var _ = Describe("Geth cold importer", func() {
 	It("fetches missing blocks from level db and persists them to pg", func() {
 		mockEthereumDatabase := fakes.NewMockEthereumDatabase()
 		mockBlockRepository := fakes.NewMockBlockRepository()
-		mockReceiptRepository := fakes.NewMockReceiptRepository()
+		mockBlockWriter := fakes.NewMockBlockWriter()
 		mockTransactionConverter := fakes.NewMockTransactionConverter()
-		blockConverter := vulcCommon.NewBlockConverter(mockTransactionConverter)
+		blockConverter := vulcCommon.NewBlockConverter(mockTransactionConverter, core.MainnetRewardSchedule)
 
 		blockNumber := int64(123)
 		fakeHash := []byte{1, 2, 3, 4, 5}
 		mockBlockRepository.SetMissingBlockNumbersReturnArray([]int64{blockNumber})
 		mockEthereumDatabase.SetReturnHash(fakeHash)
 		mockEthereumDatabase.SetReturnBlock(fakeGethBlock)
-		importer := cold_import.NewColdImporter(mockEthereumDatabase, mockBlockRepository, mockReceiptRepository, blockConverter)
+		importer := cold_import.NewColdImporter(mockEthereumDatabase, mockBlockRepository, mockBlockWriter, fakes.NewMockCheckpointRepository(), blockConverter, 20, cold_import.DefaultColdImportConfig)
 
 		importer.Execute(blockNumber, blockNumber, "node_id")
 
@@ -83,15 +86,38 @@ var _ = Describe("Geth cold importer", func() {
 		mockTransactionConverter.AssertConvertTransactionsToCoreCalledWith(fakeGethBlock)
 		convertedBlock, err := blockConverter.ToCoreBlock(fakeGethBlock)
 		Expect(err).NotTo(HaveOccurred())
-		mockBlockRepository.AssertCreateOrUpdateBlockCalledWith(convertedBlock)
+		mockBlockWriter.AssertWriteBlocksCalledWith([]core.Block{convertedBlock})
+	})
+
+	It("persists the total difficulty read from level db", func() {
+		mockEthereumDatabase := fakes.NewMockEthereumDatabase()
+		mockBlockRepository := fakes.NewMockBlockRepository()
+		mockBlockWriter := fakes.NewMockBlockWriter()
+		mockTransactionConverter := fakes.NewMockTransactionConverter()
+		blockConverter := vulcCommon.NewBlockConverter(mockTransactionConverter, core.MainnetRewardSchedule)
+
+		blockNumber := int64(123)
+		mockBlockRepository.SetMissingBlockNumbersReturnArray([]int64{blockNumber})
+		mockEthereumDatabase.SetReturnHash([]byte{1, 2, 3, 4, 5})
+		mockEthereumDatabase.SetReturnBlock(fakeGethBlock)
+		mockEthereumDatabase.SetReturnTotalDifficulty(big.NewInt(1000))
+		importer := cold_import.NewColdImporter(mockEthereumDatabase, mockBlockRepository, mockBlockWriter, fakes.NewMockCheckpointRepository(), blockConverter, 20, cold_import.DefaultColdImportConfig)
+
+		err := importer.Execute(blockNumber, blockNumber, "node_id")
+
+		Expect(err).NotTo(HaveOccurred())
+		convertedBlock, err := blockConverter.ToCoreBlock(fakeGethBlock)
+		Expect(err).NotTo(HaveOccurred())
+		convertedBlock.TotalDifficulty = "1000"
+		mockBlockWriter.AssertWriteBlocksCalledWith([]core.Block{convertedBlock})
 	})
 
 	It("sets is_final status on populated blocks", func() {
 		mockEthereumDatabase := fakes.NewMockEthereumDatabase()
 		mockBlockRepository := fakes.NewMockBlockRepository()
-		mockReceiptRepository := fakes.NewMockReceiptRepository()
+		mockBlockWriter := fakes.NewMockBlockWriter()
 		mockTransactionConverter := fakes.NewMockTransactionConverter()
-		blockConverter := vulcCommon.NewBlockConverter(mockTransactionConverter)
+		blockConverter := vulcCommon.NewBlockConverter(mockTransactionConverter, core.MainnetRewardSchedule)
 
 		startingBlockNumber := int64(120)
 		endingBlockNumber := int64(125)
@@ -99,51 +125,108 @@ var _ = Describe("Geth cold importer", func() {
 		mockBlockRepository.SetMissingBlockNumbersReturnArray([]int64{startingBlockNumber})
 		mockEthereumDatabase.SetReturnHash(fakeHash)
 		mockEthereumDatabase.SetReturnBlock(fakeGethBlock)
-		importer := cold_import.NewColdImporter(mockEthereumDatabase, mockBlockRepository, mockReceiptRepository, blockConverter)
+		importer := cold_import.NewColdImporter(mockEthereumDatabase, mockBlockRepository, mockBlockWriter, fakes.NewMockCheckpointRepository(), blockConverter, 20, cold_import.DefaultColdImportConfig)
 
 		importer.Execute(startingBlockNumber, endingBlockNumber, "node_id")
 
-		mockBlockRepository.AssertSetBlockStatusCalledWith(endingBlockNumber)
+		mockBlockRepository.AssertSetBlockStatusCalledWith(endingBlockNumber, 20)
 	})
 
-	It("fetches receipts from level db and persists them to pg", func() {
+	It("attaches receipts from level db to their transactions", func() {
 		mockEthereumDatabase := fakes.NewMockEthereumDatabase()
 		mockBlockRepository := fakes.NewMockBlockRepository()
-		mockReceiptRepository := fakes.NewMockReceiptRepository()
+		mockBlockWriter := fakes.NewMockBlockWriter()
 		mockTransactionConverter := fakes.NewMockTransactionConverter()
-		blockConverter := vulcCommon.NewBlockConverter(mockTransactionConverter)
+		txHash := common.HexToHash("0x123")
+		mockTransactionConverter.SetConvertTransactionsToCoreReturnVals([]core.Transaction{{Hash: txHash.Hex()}}, nil)
+		blockConverter := vulcCommon.NewBlockConverter(mockTransactionConverter, core.MainnetRewardSchedule)
 
 		blockNumber := int64(123)
-		blockId := int64(999)
-		mockBlockRepository.SetCreateOrUpdateBlockReturnVals(blockId, nil)
-		fakeReceipts := types.Receipts{{}}
+		fakeReceipts := types.Receipts{{TxHash: txHash, Logs: []*types.Log{}}}
 		mockBlockRepository.SetMissingBlockNumbersReturnArray([]int64{blockNumber})
 		mockEthereumDatabase.SetReturnBlock(fakeGethBlock)
 		mockEthereumDatabase.SetReturnReceipts(fakeReceipts)
-		importer := cold_import.NewColdImporter(mockEthereumDatabase, mockBlockRepository, mockReceiptRepository, blockConverter)
+		importer := cold_import.NewColdImporter(mockEthereumDatabase, mockBlockRepository, mockBlockWriter, fakes.NewMockCheckpointRepository(), blockConverter, 20, cold_import.DefaultColdImportConfig)
 
-		importer.Execute(blockNumber, blockNumber, "node_id")
+		err := importer.Execute(blockNumber, blockNumber, "node_id")
 
-		expectedReceipts := vulcCommon.ToCoreReceipts(fakeReceipts)
-		mockReceiptRepository.AssertCreateReceiptsAndLogsCalledWith(blockId, expectedReceipts)
+		Expect(err).NotTo(HaveOccurred())
+		expectedBlock, err := blockConverter.ToCoreBlock(fakeGethBlock)
+		Expect(err).NotTo(HaveOccurred())
+		expectedBlock.Transactions[0].Receipt = vulcCommon.ToCoreReceipt(fakeReceipts[0])
+		mockBlockWriter.AssertWriteBlocksCalledWith([]core.Block{expectedBlock})
 	})
 
-	It("does not fetch receipts if block already exists", func() {
+	It("checkpoints progress after every batch", func() {
 		mockEthereumDatabase := fakes.NewMockEthereumDatabase()
 		mockBlockRepository := fakes.NewMockBlockRepository()
-		mockReceiptRepository := fakes.NewMockReceiptRepository()
+		mockBlockWriter := fakes.NewMockBlockWriter()
+		mockCheckpointRepository := fakes.NewMockCheckpointRepository()
 		mockTransactionConverter := fakes.NewMockTransactionConverter()
-		blockConverter := vulcCommon.NewBlockConverter(mockTransactionConverter)
+		blockConverter := vulcCommon.NewBlockConverter(mockTransactionConverter, core.MainnetRewardSchedule)
 
-		blockNumber := int64(123)
-		mockBlockRepository.SetMissingBlockNumbersReturnArray([]int64{})
+		mockBlockRepository.SetMissingBlockNumbersReturnArray([]int64{1, 2, 3, 4, 5})
 		mockEthereumDatabase.SetReturnBlock(fakeGethBlock)
-		mockBlockRepository.SetCreateOrUpdateBlockReturnVals(0, repositories.ErrBlockExists)
-		importer := cold_import.NewColdImporter(mockEthereumDatabase, mockBlockRepository, mockReceiptRepository, blockConverter)
+		config := cold_import.ColdImportConfig{Workers: 3, BatchSize: 2}
+		importer := cold_import.NewColdImporter(mockEthereumDatabase, mockBlockRepository, mockBlockWriter, mockCheckpointRepository, blockConverter, 20, config)
 
-		err := importer.Execute(blockNumber, blockNumber, "node_id")
+		err := importer.Execute(1, 6, "node_id")
 
 		Expect(err).NotTo(HaveOccurred())
-		mockReceiptRepository.AssertCreateReceiptsAndLogsNotCalled()
+		mockBlockWriter.AssertWriteBlocksCallCountEquals(3)
+		mockBlockWriter.AssertWriteBlocksCalledWithBlockNumbers([]int64{0, 0, 0, 0, 0})
+		mockCheckpointRepository.AssertSetCheckpointCalledWith([]int64{2, 4, 5, 6})
+	})
+
+	It("resumes after the last checkpoint", func() {
+		mockEthereumDatabase := fakes.NewMockEthereumDatabase()
+		mockBlockRepository := fakes.NewMockBlockRepository()
+		mockBlockWriter := fakes.NewMockBlockWriter()
+		mockCheckpointRepository := fakes.NewMockCheckpointRepository()
+		mockCheckpointRepository.SetGetCheckpointReturnNumber(3)
+		mockTransactionConverter := fakes.NewMockTransactionConverter()
+		blockConverter := vulcCommon.NewBlockConverter(mockTransactionConverter, core.MainnetRewardSchedule)
+		mockBlockRepository.SetMissingBlockNumbersReturnArray([]int64{})
+		importer := cold_import.NewColdImporter(mockEthereumDatabase, mockBlockRepository, mockBlockWriter, mockCheckpointRepository, blockConverter, 20, cold_import.DefaultColdImportConfig)
+
+		err := importer.Execute(1, 6, "node_id")
+
+		Expect(err).NotTo(HaveOccurred())
+		mockBlockRepository.AssertMissingBlockNumbersCalledWith(4, 6, "node_id")
+	})
+
+	It("returns an error without checkpointing when a block is missing from level db", func() {
+		mockEthereumDatabase := fakes.NewMockEthereumDatabase()
+		mockBlockRepository := fakes.NewMockBlockRepository()
+		mockBlockWriter := fakes.NewMockBlockWriter()
+		mockCheckpointRepository := fakes.NewMockCheckpointRepository()
+		mockTransactionConverter := fakes.NewMockTransactionConverter()
+		blockConverter := vulcCommon.NewBlockConverter(mockTransactionConverter, core.MainnetRewardSchedule)
+		mockBlockRepository.SetMissingBlockNumbersReturnArray([]int64{1, 2})
+		importer := cold_import.NewColdImporter(mockEthereumDatabase, mockBlockRepository, mockBlockWriter, mockCheckpointRepository, blockConverter, 20, cold_import.DefaultColdImportConfig)
+
+		err := importer.Execute(1, 2, "node_id")
+
+		Expect(err).To(HaveOccurred())
+		mockBlockWriter.AssertWriteBlocksNotCalled()
+		mockCheckpointRepository.AssertSetCheckpointCalledWith(nil)
+	})
+
+	It("returns an error without checkpointing when writing a batch fails", func() {
+		mockEthereumDatabase := fakes.NewMockEthereumDatabase()
+		mockBlockRepository := fakes.NewMockBlockRepository()
+		mockBlockWriter := fakes.NewMockBlockWriter()
+		mockBlockWriter.SetWriteBlocksReturnErr(fakes.FakeError)
+		mockCheckpointRepository := fakes.NewMockCheckpointRepository()
+		mockTransactionConverter := fakes.NewMockTransactionConverter()
+		blockConverter := vulcCommon.NewBlockConverter(mockTransactionConverter, core.MainnetRewardSchedule)
+		mockBlockRepository.SetMissingBlockNumbersReturnArray([]int64{1, 2})
+		mockEthereumDatabase.SetReturnBlock(fakeGethBlock)
+		importer := cold_import.NewColdImporter(mockEthereumDatabase, mockBlockRepository, mockBlockWriter, mockCheckpointRepository, blockConverter, 20, cold_import.DefaultColdImportConfig)
+
+		err := importer.Execute(1, 2, "node_id")
+
+		Expect(err).To(MatchError(fakes.FakeError))
+		mockCheckpointRepository.AssertSetCheckpointCalledWith(nil)
 	})
 })
@@ -18,23 +18,32 @@ package cold_import
 
 import (
 	"errors"
+	"path/filepath"
+
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+
 	"github.com/vulcanize/vulcanizedb/pkg/core"
 	"github.com/vulcanize/vulcanizedb/pkg/crypto"
 	"github.com/vulcanize/vulcanizedb/pkg/fs"
-	"strings"
 )
 
-const (
-	ColdImportClientName         = "LevelDbColdImport"
-	ColdImportNetworkId  float64 = 1
-)
+const ColdImportClientName = "LevelDbColdImport"
 
 var (
-	NoChainDataErr = errors.New("Level DB path does not include chaindata extension.")
-	NoGethRootErr  = errors.New("Level DB path does not include root path to geth.")
+	NoChainDataErr      = errors.New("Level DB path does not include chaindata extension.")
+	NoGethRootErr       = errors.New("Level DB path does not include root path to geth.")
+	UnknownNetworkIdErr = errors.New("Could not determine network id from stored chain config or genesis block.")
 )
 
+// Networks recognised by genesis hash, for chaindata that predates geth
+// storing its chain config.
+var networkIdsByGenesisHash = map[common.Hash]float64{
+	params.MainnetGenesisHash: core.MAINNET_NETWORK_ID,
+	params.TestnetGenesisHash: core.ROPSTEN_NETWORK_ID,
+	params.RinkebyGenesisHash: core.RINKEBY_NETWORK_ID,
+}
+
 type ColdImportNodeBuilder struct {
 	reader fs.Reader
 	parser crypto.PublicKeyParser
@@ -44,12 +53,17 @@ func NewColdImportNodeBuilder(reader fs.Reader, parser crypto.PublicKeyParser) C
 	return ColdImportNodeBuilder{reader: reader, parser: parser}
 }
 
-func (cinb ColdImportNodeBuilder) GetNode(genesisBlock []byte, levelPath string) (core.Node, error) {
+func (cinb ColdImportNodeBuilder) GetNode(genesisBlock []byte, chainConfig *params.ChainConfig, levelPath string) (core.Node, error) {
 	var coldNode core.Node
 	nodeKeyPath, err := getNodeKeyPath(levelPath)
 	if err != nil {
 		return coldNode, err
 	}
+	genesisBlockHash := common.BytesToHash(genesisBlock)
+	networkId, err := getNetworkId(genesisBlockHash, chainConfig)
+	if err != nil {
+		return coldNode, err
+	}
 	nodeKey, err := cinb.reader.Read(nodeKeyPath)
 	if err != nil {
 		return coldNode, err
@@ -58,27 +72,38 @@ func (cinb ColdImportNodeBuilder) GetNode(genesisBlock []byte, levelPath string)
 	if err != nil {
 		return coldNode, err
 	}
-	genesisBlockHash := common.BytesToHash(genesisBlock).String()
 	coldNode = core.Node{
-		GenesisBlock: genesisBlockHash,
-		NetworkID:    ColdImportNetworkId,
+		GenesisBlock: genesisBlockHash.String(),
+		NetworkID:    networkId,
 		ID:           nodeId,
 		ClientName:   ColdImportClientName,
 	}
 	return coldNode, nil
 }
 
+// getNetworkId uses the chain ID from the stored chain config, which matches
+// the network ID for the public networks geth ships with.
+func getNetworkId(genesisBlockHash common.Hash, chainConfig *params.ChainConfig) (float64, error) {
+	if chainConfig != nil && chainConfig.ChainID != nil {
+		return float64(chainConfig.ChainID.Int64()), nil
+	}
+	networkId, ok := networkIdsByGenesisHash[genesisBlockHash]
+	if !ok {
+		return 0, UnknownNetworkIdErr
+	}
+	return networkId, nil
+}
+
+// getNodeKeyPath expects levelPath to be <geth root>/chaindata, with the
+// node key stored at <geth root>/nodekey.
 func getNodeKeyPath(levelPath string) (string, error) {
-	chaindataExtension := "chaindata"
-	if !strings.Contains(levelPath, chaindataExtension) {
+	cleanPath := filepath.Clean(levelPath)
+	if filepath.Base(cleanPath) != "chaindata" {
 		return "", NoChainDataErr
 	}
-	chaindataExtensionLength := len(chaindataExtension)
-	gethRootPathLength := len(levelPath) - chaindataExtensionLength
-	if gethRootPathLength <= chaindataExtensionLength {
+	gethRootPath := filepath.Dir(cleanPath)
+	if gethRootPath == "." || gethRootPath == string(filepath.Separator) {
 		return "", NoGethRootErr
 	}
-	gethRootPath := levelPath[:gethRootPathLength]
-	nodeKeyPath := gethRootPath + "nodekey"
-	return nodeKeyPath, nil
+	return filepath.Join(gethRootPath, "nodekey"), nil
 }
@@ -18,7 +18,10 @@ package cold_import_test
 
 import (
 	"errors"
+	"math/big"
+
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/vulcanize/vulcanizedb/pkg/fakes"
@@ -26,6 +29,8 @@ import (
 )
 
 var _ = Describe("Cold importer node builder", func() {
+	var ropstenChainConfig = &params.ChainConfig{ChainID: big.NewInt(3)}
+
 	Describe("when level path is not valid", func() {
 		It("returns error if no chaindata extension", func() {
 			gethPath := "path/to/geth"
@@ -33,7 +38,7 @@ var _ = Describe("Cold importer node builder", func() {
 			mockParser := fakes.NewMockCryptoParser()
 			nodeBuilder := cold_import.NewColdImportNodeBuilder(mockReader, mockParser)
 
-			_, err := nodeBuilder.GetNode([]byte{1, 2, 3, 4, 5}, gethPath)
+			_, err := nodeBuilder.GetNode([]byte{1, 2, 3, 4, 5}, ropstenChainConfig, gethPath)
 
 			Expect(err).To(HaveOccurred())
 			Expect(err).To(MatchError(cold_import.NoChainDataErr))
@@ -45,11 +50,57 @@ var _ = Describe("Cold importer node builder", func() {
 			mockParser := fakes.NewMockCryptoParser()
 			nodeBuilder := cold_import.NewColdImportNodeBuilder(mockReader, mockParser)
 
-			_, err := nodeBuilder.GetNode([]byte{1, 2, 3, 4, 5}, chaindataPath)
+			_, err := nodeBuilder.GetNode([]byte{1, 2, 3, 4, 5}, ropstenChainConfig, chaindataPath)
 
 			Expect(err).To(HaveOccurred())
 			Expect(err).To(MatchError(cold_import.NoGethRootErr))
 		})
+
+		It("returns error if chaindata is only part of a directory name", func() {
+			mockReader := fakes.NewMockFsReader()
+			mockParser := fakes.NewMockCryptoParser()
+			nodeBuilder := cold_import.NewColdImportNodeBuilder(mockReader, mockParser)
+
+			_, err := nodeBuilder.GetNode([]byte{1, 2, 3, 4, 5}, ropstenChainConfig, "path/to/chaindata-backup/geth")
+
+			Expect(err).To(MatchError(cold_import.NoChainDataErr))
+		})
+	})
+
+	Describe("when level path has a trailing separator", func() {
+		It("reads the node key from the geth root", func() {
+			mockReader := fakes.NewMockFsReader()
+			mockParser := fakes.NewMockCryptoParser()
+			nodeBuilder := cold_import.NewColdImportNodeBuilder(mockReader, mockParser)
+
+			_, err := nodeBuilder.GetNode([]byte{1, 2, 3, 4, 5}, ropstenChainConfig, "path/to/geth/chaindata/")
+
+			Expect(err).NotTo(HaveOccurred())
+			mockReader.AssertReadCalledWith("path/to/geth/nodekey")
+		})
+	})
+
+	Describe("resolving the network id", func() {
+		It("falls back to known genesis hashes without a stored chain config", func() {
+			mockReader := fakes.NewMockFsReader()
+			mockParser := fakes.NewMockCryptoParser()
+			nodeBuilder := cold_import.NewColdImportNodeBuilder(mockReader, mockParser)
+
+			result, err := nodeBuilder.GetNode(params.RinkebyGenesisHash.Bytes(), nil, "path/to/geth/chaindata")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.NetworkID).To(Equal(float64(4)))
+		})
+
+		It("returns error if the network cannot be determined", func() {
+			mockReader := fakes.NewMockFsReader()
+			mockParser := fakes.NewMockCryptoParser()
+			nodeBuilder := cold_import.NewColdImportNodeBuilder(mockReader, mockParser)
+
+			_, err := nodeBuilder.GetNode([]byte{1, 2, 3, 4, 5}, &params.ChainConfig{}, "path/to/geth/chaindata")
+
+			Expect(err).To(MatchError(cold_import.UnknownNetworkIdErr))
+		})
 	})
 
 	Describe("when reader fails", func() {
@@ -60,7 +111,7 @@ var _ = Describe("Cold importer node builder", func() {
 			mockParser := fakes.NewMockCryptoParser()
 			nodeBuilder := cold_import.NewColdImportNodeBuilder(mockReader, mockParser)
 
-			_, err := nodeBuilder.GetNode([]byte{1, 2, 3, 4, 5}, "path/to/geth/chaindata")
+			_, err := nodeBuilder.GetNode([]byte{1, 2, 3, 4, 5}, ropstenChainConfig, "path/to/geth/chaindata")
 
 			Expect(err).To(HaveOccurred())
 			Expect(err).To(MatchError(fakeError))
@@ -75,7 +126,7 @@ var _ = Describe("Cold importer node builder", func() {
 			mockParser.SetReturnErr(fakeErr)
 			nodeBuilder := cold_import.NewColdImportNodeBuilder(mockReader, mockParser)
 
-			_, err := nodeBuilder.GetNode([]byte{1, 2, 3, 4, 5}, "path/to/geth/chaindata")
+			_, err := nodeBuilder.GetNode([]byte{1, 2, 3, 4, 5}, ropstenChainConfig, "path/to/geth/chaindata")
 
 			Expect(err).To(HaveOccurred())
 			Expect(err).To(MatchError(fakeErr))
@@ -96,7 +147,7 @@ var _ = Describe("Cold importer node builder", func() {
 			mockParser.SetReturnVal(fakePublicKeyString)
 			nodeBuilder := cold_import.NewColdImportNodeBuilder(mockReader, mockParser)
 
-			result, err := nodeBuilder.GetNode(fakeGenesisBlock, fakeLevelPath)
+			result, err := nodeBuilder.GetNode(fakeGenesisBlock, ropstenChainConfig, fakeLevelPath)
 
 			Expect(err).NotTo(HaveOccurred())
 			mockReader.AssertReadCalledWith(fakeNodeKeyPath)
@@ -106,7 +157,7 @@ var _ = Describe("Cold importer node builder", func() {
 			expectedGenesisBlock := common.BytesToHash(fakeGenesisBlock).String()
 			Expect(result.GenesisBlock).To(Equal(expectedGenesisBlock))
 			Expect(result.ID).To(Equal(fakePublicKeyString))
-			Expect(result.NetworkID).To(Equal(cold_import.ColdImportNetworkId))
+			Expect(result.NetworkID).To(Equal(float64(3)))
 		})
 	})
 
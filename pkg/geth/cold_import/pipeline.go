@@ -0,0 +1,218 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cold_import
+
+import (
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/geth/converters/common"
+)
+
+// importJob carries one block number through the read, convert and write stages
+type importJob struct {
+	index       int
+	blockNumber int64
+	block       *types.Block
+	header      *types.Header
+	receipts    types.Receipts
+	td          *big.Int
+	coreBlock   core.Block
+	coreHeader  core.Header
+	err         error
+}
+
+type checkpointFunc func(lastBlockNumber int64) error
+
+// importStages are the steps an import applies to each block number. read and convert run
+// concurrently on jobs without an error; write receives each batch in block number order.
+type importStages struct {
+	read    func(job *importJob)
+	convert func(job *importJob)
+	write   func(batch []importJob) error
+}
+
+// runImport reads and converts block numbers concurrently and writes them in block number order,
+// one batch at a time, checkpointing after every batch when checkpoint is given. The number of
+// jobs in flight is bounded so that a slow block cannot make the writer buffer the rest of the range.
+func runImport(config ColdImportConfig, blockNumbers []int64, stages importStages, checkpoint checkpointFunc) error {
+	if len(blockNumbers) == 0 {
+		return nil
+	}
+	done := make(chan struct{})
+	defer close(done)
+	slots := make(chan struct{}, config.Workers*config.BatchSize)
+	jobs := make(chan importJob)
+	read := runStage(done, config.Workers, jobs, stages.read)
+	converted := runStage(done, config.Workers, read, stages.convert)
+
+	go func() {
+		defer close(jobs)
+		for i, blockNumber := range blockNumbers {
+			select {
+			case slots <- struct{}{}:
+			case <-done:
+				return
+			}
+			select {
+			case jobs <- importJob{index: i, blockNumber: blockNumber}:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	progress := newImportProgress(len(blockNumbers))
+	pending := make(map[int]importJob)
+	batch := make([]importJob, 0, config.BatchSize)
+	next := 0
+	for job := range converted {
+		pending[job.index] = job
+		for {
+			job, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			if job.err != nil {
+				return job.err
+			}
+			batch = append(batch, job)
+			next++
+			if next%config.BatchSize == 0 || next == len(blockNumbers) {
+				err := stages.write(batch)
+				if err != nil {
+					return err
+				}
+				for range batch {
+					<-slots
+				}
+				batch = batch[:0]
+				if checkpoint != nil {
+					err = checkpoint(job.blockNumber)
+					if err != nil {
+						return err
+					}
+				}
+				progress.report(next, job.blockNumber)
+			}
+		}
+	}
+	return nil
+}
+
+func runStage(done <-chan struct{}, workers int, in <-chan importJob, stage func(job *importJob)) <-chan importJob {
+	out := make(chan importJob)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range in {
+				if job.err == nil {
+					stage(&job)
+				}
+				select {
+				case out <- job:
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+func (ci *ColdImporter) importBlocks(blockNumbers []int64, checkpoint checkpointFunc) error {
+	return runImport(ci.config, blockNumbers, importStages{
+		read:    ci.readBlock,
+		convert: ci.convertBlock,
+		write:   ci.writeBlocks,
+	}, checkpoint)
+}
+
+func (ci *ColdImporter) readBlock(job *importJob) {
+	hash := ci.ethDB.GetBlockHash(job.blockNumber)
+	job.block = ci.ethDB.GetBlock(hash, job.blockNumber)
+	if job.block == nil {
+		job.err = fmt.Errorf("block %d not found in ethereum database", job.blockNumber)
+		return
+	}
+	job.receipts = ci.ethDB.GetBlockReceipts(hash, job.blockNumber)
+	job.td = ci.ethDB.GetTotalDifficulty(hash, job.blockNumber)
+}
+
+func (ci *ColdImporter) convertBlock(job *importJob) {
+	job.coreBlock, job.err = ci.converter.ToCoreBlock(job.block)
+	if job.td != nil {
+		job.coreBlock.TotalDifficulty = job.td.String()
+	}
+	attachReceipts(&job.coreBlock, common.ToCoreReceipts(job.receipts))
+}
+
+func (ci *ColdImporter) writeBlocks(batch []importJob) error {
+	blocks := make([]core.Block, len(batch))
+	for i, job := range batch {
+		blocks[i] = job.coreBlock
+	}
+	return ci.blockWriter.WriteBlocks(blocks)
+}
+
+// attachReceipts sets each transaction's receipt, which LevelDB stores separately from the block
+func attachReceipts(block *core.Block, receipts []core.Receipt) {
+	if len(block.Transactions) == 0 {
+		return
+	}
+	receiptsByTxHash := make(map[string]core.Receipt, len(receipts))
+	for _, receipt := range receipts {
+		receiptsByTxHash[receipt.TxHash] = receipt
+	}
+	transactions := make([]core.Transaction, len(block.Transactions))
+	for i, transaction := range block.Transactions {
+		if receipt, ok := receiptsByTxHash[transaction.Hash]; ok {
+			transaction.Receipt = receipt
+		}
+		transactions[i] = transaction
+	}
+	block.Transactions = transactions
+}
+
+type importProgress struct {
+	started time.Time
+	total   int
+}
+
+func newImportProgress(total int) importProgress {
+	return importProgress{started: time.Now(), total: total}
+}
+
+func (progress importProgress) report(imported int, blockNumber int64) {
+	elapsed := time.Since(progress.started)
+	rate := float64(imported) / elapsed.Seconds()
+	remaining := time.Duration(float64(progress.total-imported) / rate * float64(time.Second))
+	log.Printf("Imported through block %d (%d of %d, %.1f blocks/s, ETA %s)\n",
+		blockNumber, imported, progress.total, rate, remaining.Round(time.Second))
+}
@@ -44,7 +44,7 @@ func (cdtc *ColdDbTransactionConverter) ConvertTransactionsToCore(gethBlock *typ
 			if err != nil {
 				return err
 			}
-			coreTransaction := transToCoreTrans(transaction, &sender)
+			coreTransaction := transToCoreTrans(transaction, &sender, transactionIndex)
 			coreTransactions[transactionIndex] = coreTransaction
 			return nil
 		})
@@ -63,7 +63,7 @@ func getSigner(tx *types.Transaction) types.Signer {
 	return types.HomesteadSigner{}
 }
 
-func transToCoreTrans(transaction *types.Transaction, from *common.Address) core.Transaction {
+func transToCoreTrans(transaction *types.Transaction, from *common.Address, index uint) core.Transaction {
 	data := hexutil.Encode(transaction.Data())
 	return core.Transaction{
 		Hash:     transaction.Hash().Hex(),
@@ -71,7 +71,8 @@ func transToCoreTrans(transaction *types.Transaction, from *common.Address) core
 		To:       strings.ToLower(addressToHex(transaction.To())),
 		From:     strings.ToLower(addressToHex(from)),
 		GasLimit: transaction.Gas(),
-		GasPrice: transaction.GasPrice().Int64(),
+		GasPrice: transaction.GasPrice().String(),
+		TxIndex:  int64(index),
 		Value:    transaction.Value().String(),
 		Data:     data,
 	}
@@ -24,11 +24,12 @@ import (
 )
 
 type BlockConverter struct {
+	rewardSchedule       core.RewardSchedule
 	transactionConverter TransactionConverter
 }
 
-func NewBlockConverter(transactionConverter TransactionConverter) BlockConverter {
-	return BlockConverter{transactionConverter: transactionConverter}
+func NewBlockConverter(transactionConverter TransactionConverter, rewardSchedule core.RewardSchedule) BlockConverter {
+	return BlockConverter{rewardSchedule: rewardSchedule, transactionConverter: transactionConverter}
 }
 
 func (bc BlockConverter) ToCoreBlock(gethBlock *types.Block) (core.Block, error) {
@@ -38,7 +39,7 @@ func (bc BlockConverter) ToCoreBlock(gethBlock *types.Block) (core.Block, error)
 	}
 
 	coreBlock := core.Block{
-		Difficulty:   gethBlock.Difficulty().Int64(),
+		Difficulty:   gethBlock.Difficulty().String(),
 		ExtraData:    hexutil.Encode(gethBlock.Extra()),
 		GasLimit:     gethBlock.GasLimit(),
 		GasUsed:      gethBlock.GasUsed(),
@@ -52,7 +53,21 @@ func (bc BlockConverter) ToCoreBlock(gethBlock *types.Block) (core.Block, error)
 		Transactions: transactions,
 		UncleHash:    gethBlock.UncleHash().Hex(),
 	}
-	coreBlock.Reward = CalcBlockReward(coreBlock, gethBlock.Uncles())
-	coreBlock.UnclesReward = CalcUnclesReward(coreBlock, gethBlock.Uncles())
+	coreBlock.Reward = CalcBlockReward(bc.rewardSchedule, coreBlock, gethBlock.Uncles()).String()
+	coreBlock.UnclesReward = CalcUnclesReward(bc.rewardSchedule, coreBlock, gethBlock.Uncles()).String()
+	coreBlock.Uncles = bc.toCoreUncles(coreBlock, gethBlock.Uncles())
 	return coreBlock, nil
 }
+
+func (bc BlockConverter) toCoreUncles(block core.Block, uncles []*types.Header) []core.Uncle {
+	var coreUncles []core.Uncle
+	for _, uncle := range uncles {
+		coreUncles = append(coreUncles, core.Uncle{
+			Hash:   uncle.Hash().Hex(),
+			Number: uncle.Number.Int64(),
+			Miner:  strings.ToLower(uncle.Coinbase.Hex()),
+			Reward: CalcUncleReward(bc.rewardSchedule, block, uncle).String(),
+		})
+	}
+	return coreUncles
+}
@@ -21,13 +21,17 @@ import (
 	"log"
 	"math/big"
 	"os"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"github.com/vulcanize/vulcanizedb/pkg/core"
 	"github.com/vulcanize/vulcanizedb/pkg/fakes"
 	vulcCommon "github.com/vulcanize/vulcanizedb/pkg/geth/converters/common"
 	"github.com/vulcanize/vulcanizedb/pkg/geth/converters/rpc"
@@ -36,7 +40,8 @@ import (
 var _ = Describe("Conversion of GethBlock to core.Block", func() {
 
 	It("converts basic Block metadata", func() {
-		difficulty := big.NewInt(1)
+		// exceeds int64
+		difficulty, _ := new(big.Int).SetString("10000000000000000000000", 10)
 		gasLimit := uint64(100000)
 		gasUsed := uint64(100000)
 		miner := common.HexToAddress("0x0000000000000000000000000000000000000123")
@@ -58,14 +63,14 @@ var _ = Describe("Conversion of GethBlock to core.Block", func() {
 			UncleHash:  common.Hash{128},
 		}
 		block := types.NewBlock(&header, []*types.Transaction{}, []*types.Header{}, []*types.Receipt{})
-		client := fakes.NewMockEthClient()
-		transactionConverter := rpc.NewRpcTransactionConverter(client)
-		blockConverter := vulcCommon.NewBlockConverter(transactionConverter)
+		rpcClient := fakes.NewMockRpcClient()
+		transactionConverter := rpc.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
+		blockConverter := vulcCommon.NewBlockConverter(transactionConverter, core.MainnetRewardSchedule)
 
 		coreBlock, err := blockConverter.ToCoreBlock(block)
 
 		Expect(err).ToNot(HaveOccurred())
-		Expect(coreBlock.Difficulty).To(Equal(difficulty.Int64()))
+		Expect(coreBlock.Difficulty).To(Equal("10000000000000000000000"))
 		Expect(coreBlock.GasLimit).To(Equal(gasLimit))
 		Expect(coreBlock.Miner).To(Equal(miner.Hex()))
 		Expect(coreBlock.GasUsed).To(Equal(gasUsed))
@@ -100,8 +105,8 @@ var _ = Describe("Conversion of GethBlock to core.Block", func() {
 			}
 			receipts := []*types.Receipt{&receipt}
 
-			client := fakes.NewMockEthClient()
-			client.SetTransactionReceipts(receipts)
+			rpcClient := fakes.NewMockRpcClient()
+			rpcClient.SetTransactionReceipts(receipts)
 
 			number := int64(1071819)
 			header := types.Header{
@@ -109,13 +114,14 @@ var _ = Describe("Conversion of GethBlock to core.Block", func() {
 			}
 			uncles := []*types.Header{{Number: big.NewInt(1071817)}, {Number: big.NewInt(1071818)}}
 			block := types.NewBlock(&header, transactions, uncles, []*types.Receipt{&receipt})
-			transactionConverter := rpc.NewRpcTransactionConverter(client)
-			blockConverter := vulcCommon.NewBlockConverter(transactionConverter)
+			transactionConverter := rpc.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
+			blockConverter := vulcCommon.NewBlockConverter(transactionConverter, core.MainnetRewardSchedule)
 
 			coreBlock, err := blockConverter.ToCoreBlock(block)
 
 			Expect(err).ToNot(HaveOccurred())
-			Expect(vulcCommon.CalcBlockReward(coreBlock, block.Uncles())).To(Equal(5.31355))
+			Expect(vulcCommon.CalcBlockReward(core.MainnetRewardSchedule, coreBlock, block.Uncles()).String()).To(Equal("5313550000000000000"))
+			Expect(coreBlock.Reward).To(Equal("5313550000000000000"))
 		})
 
 		It("calculates the uncles reward for a block", func() {
@@ -144,15 +150,42 @@ var _ = Describe("Conversion of GethBlock to core.Block", func() {
 			}
 			block := types.NewBlock(&header, transactions, uncles, receipts)
 
-			client := fakes.NewMockEthClient()
-			client.SetTransactionReceipts(receipts)
-			transactionConverter := rpc.NewRpcTransactionConverter(client)
-			blockConverter := vulcCommon.NewBlockConverter(transactionConverter)
+			rpcClient := fakes.NewMockRpcClient()
+			rpcClient.SetTransactionReceipts(receipts)
+			transactionConverter := rpc.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
+			blockConverter := vulcCommon.NewBlockConverter(transactionConverter, core.MainnetRewardSchedule)
 
 			coreBlock, err := blockConverter.ToCoreBlock(block)
 
 			Expect(err).ToNot(HaveOccurred())
-			Expect(vulcCommon.CalcUnclesReward(coreBlock, block.Uncles())).To(Equal(6.875))
+			Expect(vulcCommon.CalcUnclesReward(core.MainnetRewardSchedule, coreBlock, block.Uncles()).String()).To(Equal("6875000000000000000"))
+			Expect(coreBlock.UnclesReward).To(Equal("6875000000000000000"))
+		})
+
+		It("attributes each uncle's reward to its miner", func() {
+			header := types.Header{
+				Number: big.NewInt(int64(1071819)),
+			}
+			uncles := []*types.Header{
+				{Number: big.NewInt(1071816), Coinbase: common.HexToAddress("0x0000000000000000000000000000000000000abc")},
+				{Number: big.NewInt(1071817), Coinbase: common.HexToAddress("0x0000000000000000000000000000000000000def")},
+			}
+			block := types.NewBlock(&header, []*types.Transaction{}, uncles, []*types.Receipt{})
+			rpcClient := fakes.NewMockRpcClient()
+			transactionConverter := rpc.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
+			blockConverter := vulcCommon.NewBlockConverter(transactionConverter, core.MainnetRewardSchedule)
+
+			coreBlock, err := blockConverter.ToCoreBlock(block)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(coreBlock.Uncles)).To(Equal(2))
+			Expect(coreBlock.Uncles[0].Hash).To(Equal(uncles[0].Hash().Hex()))
+			Expect(coreBlock.Uncles[0].Number).To(Equal(int64(1071816)))
+			Expect(coreBlock.Uncles[0].Miner).To(Equal("0x0000000000000000000000000000000000000abc"))
+			Expect(coreBlock.Uncles[0].Reward).To(Equal("3125000000000000000"))
+			Expect(coreBlock.Uncles[1].Miner).To(Equal("0x0000000000000000000000000000000000000def"))
+			Expect(coreBlock.Uncles[1].Reward).To(Equal("3750000000000000000"))
+			Expect(coreBlock.UnclesReward).To(Equal("6875000000000000000"))
 		})
 
 		It("decreases the static block reward from 5 to 3 for blocks after block 4,269,999", func() {
@@ -193,15 +226,85 @@ var _ = Describe("Conversion of GethBlock to core.Block", func() {
 			var uncles []*types.Header
 			block := types.NewBlock(&header, transactions, uncles, receipts)
 
-			client := fakes.NewMockEthClient()
-			client.SetTransactionReceipts(receipts)
-			transactionConverter := rpc.NewRpcTransactionConverter(client)
-			blockConverter := vulcCommon.NewBlockConverter(transactionConverter)
+			rpcClient := fakes.NewMockRpcClient()
+			rpcClient.SetTransactionReceipts(receipts)
+			transactionConverter := rpc.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
+			blockConverter := vulcCommon.NewBlockConverter(transactionConverter, core.MainnetRewardSchedule)
+
+			coreBlock, err := blockConverter.ToCoreBlock(block)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(vulcCommon.CalcBlockReward(core.MainnetRewardSchedule, coreBlock, block.Uncles()).String()).To(Equal("3024990672000000000"))
+		})
+
+		It("decreases the static block reward from 3 to 2 from Constantinople", func() {
+			header := types.Header{Number: big.NewInt(7280000)}
+			uncles := []*types.Header{{Number: big.NewInt(7279999)}}
+			block := types.NewBlock(&header, []*types.Transaction{}, uncles, []*types.Receipt{})
+			rpcClient := fakes.NewMockRpcClient()
+			transactionConverter := rpc.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
+			blockConverter := vulcCommon.NewBlockConverter(transactionConverter, core.MainnetRewardSchedule)
 
 			coreBlock, err := blockConverter.ToCoreBlock(block)
 
 			Expect(err).ToNot(HaveOccurred())
-			Expect(vulcCommon.CalcBlockReward(coreBlock, block.Uncles())).To(Equal(3.024990672))
+			Expect(coreBlock.Reward).To(Equal("2062500000000000000"))
+			Expect(coreBlock.UnclesReward).To(Equal("1750000000000000000"))
+		})
+
+		It("only pays transaction fees on proof of authority networks", func() {
+			transaction := types.NewTransaction(
+				uint64(1),
+				common.HexToAddress("0x108fedb097c1dcfed441480170144d8e19bb217f"),
+				big.NewInt(0),
+				uint64(90000),
+				big.NewInt(1000000000),
+				[]byte{})
+			receipt := types.Receipt{
+				TxHash:  transaction.Hash(),
+				GasUsed: uint64(21000),
+			}
+			receipts := []*types.Receipt{&receipt}
+			header := types.Header{Number: big.NewInt(100)}
+			block := types.NewBlock(&header, []*types.Transaction{transaction}, []*types.Header{}, receipts)
+			rpcClient := fakes.NewMockRpcClient()
+			rpcClient.SetTransactionReceipts(receipts)
+			transactionConverter := rpc.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
+			blockConverter := vulcCommon.NewBlockConverter(transactionConverter, core.ProofOfAuthorityRewardSchedule)
+
+			coreBlock, err := blockConverter.ToCoreBlock(block)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(coreBlock.Reward).To(Equal("21000000000000"))
+			Expect(coreBlock.UnclesReward).To(Equal("0"))
+		})
+
+		It("calculates transaction fees for gas prices that exceed int64", func() {
+			gasPrice, _ := new(big.Int).SetString("10000000000000000000", 10)
+			transaction := types.NewTransaction(
+				uint64(1),
+				common.HexToAddress("0x108fedb097c1dcfed441480170144d8e19bb217f"),
+				big.NewInt(0),
+				uint64(90000),
+				gasPrice,
+				[]byte{})
+			receipt := types.Receipt{
+				TxHash:  transaction.Hash(),
+				GasUsed: uint64(21000),
+			}
+			receipts := []*types.Receipt{&receipt}
+			header := types.Header{Number: big.NewInt(100)}
+			block := types.NewBlock(&header, []*types.Transaction{transaction}, []*types.Header{}, receipts)
+			rpcClient := fakes.NewMockRpcClient()
+			rpcClient.SetTransactionReceipts(receipts)
+			transactionConverter := rpc.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
+			blockConverter := vulcCommon.NewBlockConverter(transactionConverter, core.ProofOfAuthorityRewardSchedule)
+
+			coreBlock, err := blockConverter.ToCoreBlock(block)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(coreBlock.Transactions[0].GasPrice).To(Equal("10000000000000000000"))
+			Expect(coreBlock.Reward).To(Equal("210000000000000000000000"))
 		})
 	})
 
@@ -209,9 +312,9 @@ var _ = Describe("Conversion of GethBlock to core.Block", func() {
 		It("is empty", func() {
 			header := types.Header{}
 			block := types.NewBlock(&header, []*types.Transaction{}, []*types.Header{}, []*types.Receipt{})
-			client := fakes.NewMockEthClient()
-			transactionConverter := rpc.NewRpcTransactionConverter(client)
-			blockConverter := vulcCommon.NewBlockConverter(transactionConverter)
+			rpcClient := fakes.NewMockRpcClient()
+			transactionConverter := rpc.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
+			blockConverter := vulcCommon.NewBlockConverter(transactionConverter, core.MainnetRewardSchedule)
 
 			coreBlock, err := blockConverter.ToCoreBlock(block)
 
@@ -238,8 +341,8 @@ var _ = Describe("Conversion of GethBlock to core.Block", func() {
 				TxHash:            gethTransaction.Hash(),
 			}
 
-			client := fakes.NewMockEthClient()
-			client.SetTransactionReceipts([]*types.Receipt{gethReceipt})
+			rpcClient := fakes.NewMockRpcClient()
+			rpcClient.SetTransactionReceipts([]*types.Receipt{gethReceipt})
 
 			header := types.Header{}
 			block := types.NewBlock(
@@ -248,8 +351,8 @@ var _ = Describe("Conversion of GethBlock to core.Block", func() {
 				[]*types.Header{},
 				[]*types.Receipt{gethReceipt},
 			)
-			transactionConverter := rpc.NewRpcTransactionConverter(client)
-			blockConverter := vulcCommon.NewBlockConverter(transactionConverter)
+			transactionConverter := rpc.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
+			blockConverter := vulcCommon.NewBlockConverter(transactionConverter, core.MainnetRewardSchedule)
 
 			coreBlock, err := blockConverter.ToCoreBlock(block)
 
@@ -260,7 +363,7 @@ var _ = Describe("Conversion of GethBlock to core.Block", func() {
 			Expect(coreTransaction.To).To(Equal(gethTransaction.To().Hex()))
 			Expect(coreTransaction.From).To(Equal("0x0000000000000000000000000000000000000123"))
 			Expect(coreTransaction.GasLimit).To(Equal(gethTransaction.Gas()))
-			Expect(coreTransaction.GasPrice).To(Equal(gethTransaction.GasPrice().Int64()))
+			Expect(coreTransaction.GasPrice).To(Equal(gethTransaction.GasPrice().String()))
 			Expect(coreTransaction.Value).To(Equal(gethTransaction.Value().String()))
 			Expect(coreTransaction.Nonce).To(Equal(gethTransaction.Nonce()))
 
@@ -269,6 +372,70 @@ var _ = Describe("Conversion of GethBlock to core.Block", func() {
 			Expect(coreReceipt).To(Equal(expectedReceipt))
 		})
 
+		It("recovers senders from signatures and fetches receipts in one batch", func() {
+			key, err := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+			Expect(err).NotTo(HaveOccurred())
+			signer := types.NewEIP155Signer(big.NewInt(1))
+			var transactions []*types.Transaction
+			for nonce := uint64(0); nonce < 2; nonce++ {
+				transaction, err := types.SignTx(types.NewTransaction(nonce, common.Address{1}, big.NewInt(10), uint64(21000), big.NewInt(3), nil), signer, key)
+				Expect(err).NotTo(HaveOccurred())
+				transactions = append(transactions, transaction)
+			}
+			rpcClient := fakes.NewMockRpcClient()
+			block := types.NewBlock(&types.Header{Number: big.NewInt(5000000)}, transactions, []*types.Header{}, []*types.Receipt{})
+			transactionConverter := rpc.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
+			blockConverter := vulcCommon.NewBlockConverter(transactionConverter, core.MainnetRewardSchedule)
+
+			coreBlock, err := blockConverter.ToCoreBlock(block)
+
+			Expect(err).ToNot(HaveOccurred())
+			sender := strings.ToLower(crypto.PubkeyToAddress(key.PublicKey).Hex())
+			Expect(coreBlock.Transactions[0].From).To(Equal(sender))
+			Expect(coreBlock.Transactions[1].From).To(Equal(sender))
+			Expect(coreBlock.Transactions[0].TxIndex).To(Equal(int64(0)))
+			Expect(coreBlock.Transactions[1].TxIndex).To(Equal(int64(1)))
+			rpcClient.AssertBatchCallCalledWith("eth_getTransactionReceipt", 2)
+		})
+
+		It("recovers pre-homestead senders with high s values", func() {
+			key, err := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+			Expect(err).NotTo(HaveOccurred())
+			signer := types.FrontierSigner{}
+			transaction := types.NewTransaction(0, common.Address{1}, big.NewInt(10), uint64(21000), big.NewInt(3), nil)
+			signature, err := crypto.Sign(signer.Hash(transaction).Bytes(), key)
+			Expect(err).NotTo(HaveOccurred())
+			// flip s into the upper half of the curve order, which homestead rejects
+			s := new(big.Int).Sub(crypto.S256().Params().N, new(big.Int).SetBytes(signature[32:64]))
+			copy(signature[32:64], common.LeftPadBytes(s.Bytes(), 32))
+			signature[64] ^= 1
+			transaction, err = transaction.WithSignature(signer, signature)
+			Expect(err).NotTo(HaveOccurred())
+			rpcClient := fakes.NewMockRpcClient()
+			block := types.NewBlock(&types.Header{Number: big.NewInt(1)}, []*types.Transaction{transaction}, []*types.Header{}, []*types.Receipt{})
+			transactionConverter := rpc.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
+
+			transactions, err := transactionConverter.ConvertTransactionsToCore(block)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(transactions[0].From).To(Equal(strings.ToLower(crypto.PubkeyToAddress(key.PublicKey).Hex())))
+		})
+
+		It("fetches every sender from the node on unknown networks", func() {
+			key, err := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+			Expect(err).NotTo(HaveOccurred())
+			transaction, err := types.SignTx(types.NewTransaction(0, common.Address{1}, big.NewInt(10), uint64(21000), big.NewInt(3), nil), types.HomesteadSigner{}, key)
+			Expect(err).NotTo(HaveOccurred())
+			rpcClient := fakes.NewMockRpcClient()
+			block := types.NewBlock(&types.Header{Number: big.NewInt(1)}, []*types.Transaction{transaction}, []*types.Header{}, []*types.Receipt{})
+			transactionConverter := rpc.NewRpcTransactionConverter(rpcClient, nil)
+
+			transactions, err := transactionConverter.ConvertTransactionsToCore(block)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(transactions[0].From).To(Equal("0x0000000000000000000000000000000000000123"))
+		})
+
 		It("has an empty 'To' field when transaction creates a new contract", func() {
 			gethTransaction := types.NewContractCreation(
 				uint64(10000),
@@ -285,8 +452,8 @@ var _ = Describe("Conversion of GethBlock to core.Block", func() {
 				ContractAddress:   common.HexToAddress("0x1023342345"),
 			}
 
-			client := fakes.NewMockEthClient()
-			client.SetTransactionReceipts([]*types.Receipt{gethReceipt})
+			rpcClient := fakes.NewMockRpcClient()
+			rpcClient.SetTransactionReceipts([]*types.Receipt{gethReceipt})
 
 			block := types.NewBlock(
 				&types.Header{},
@@ -294,8 +461,8 @@ var _ = Describe("Conversion of GethBlock to core.Block", func() {
 				[]*types.Header{},
 				[]*types.Receipt{gethReceipt},
 			)
-			transactionConverter := rpc.NewRpcTransactionConverter(client)
-			blockConverter := vulcCommon.NewBlockConverter(transactionConverter)
+			transactionConverter := rpc.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
+			blockConverter := vulcCommon.NewBlockConverter(transactionConverter, core.MainnetRewardSchedule)
 
 			coreBlock, err := blockConverter.ToCoreBlock(block)
 
@@ -340,11 +507,23 @@ var _ = Describe("Conversion of GethBlock to core.Block", func() {
 			defer log.SetOutput(os.Stdout)
 		})
 
-		It("returns an error when transaction sender call fails", func() {
-			client := fakes.NewMockEthClient()
-			client.SetTransactionSenderErr(fakes.FakeError)
-			transactionConverter := rpc.NewRpcTransactionConverter(client)
-			blockConverter := vulcCommon.NewBlockConverter(transactionConverter)
+		It("returns an error when fetching a transaction sender fails", func() {
+			rpcClient := fakes.NewMockRpcClient()
+			rpcClient.SetBatchCallElemErr(fakes.FakeError)
+			transactionConverter := rpc.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
+			blockConverter := vulcCommon.NewBlockConverter(transactionConverter, core.MainnetRewardSchedule)
+
+			_, err := blockConverter.ToCoreBlock(block)
+
+			Expect(err).To(MatchError(fakes.FakeError))
+			rpcClient.AssertBatchCallCalledWith("eth_getTransactionByBlockHashAndIndex", 1)
+		})
+
+		It("returns an error when a receipt in the batch fails", func() {
+			rpcClient := fakes.NewMockRpcClient()
+			rpcClient.SetBatchCallElemErr(fakes.FakeError)
+			transactionConverter := rpc.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
+			blockConverter := vulcCommon.NewBlockConverter(transactionConverter, core.MainnetRewardSchedule)
 
 			_, err := blockConverter.ToCoreBlock(block)
 
@@ -352,10 +531,10 @@ var _ = Describe("Conversion of GethBlock to core.Block", func() {
 		})
 
 		It("returns an error when transaction receipt call fails", func() {
-			client := fakes.NewMockEthClient()
-			client.SetTransactionReceiptErr(fakes.FakeError)
-			transactionConverter := rpc.NewRpcTransactionConverter(client)
-			blockConverter := vulcCommon.NewBlockConverter(transactionConverter)
+			rpcClient := fakes.NewMockRpcClient()
+			rpcClient.SetBatchCallErr(fakes.FakeError)
+			transactionConverter := rpc.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
+			blockConverter := vulcCommon.NewBlockConverter(transactionConverter, core.MainnetRewardSchedule)
 
 			_, err := blockConverter.ToCoreBlock(block)
 
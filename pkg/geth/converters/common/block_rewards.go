@@ -13,58 +13,58 @@
 
 // You should have received a copy of the GNU Affero General Public License
 // along with this program.  If not, see <http://www.gnu.org/licenses/>.
-
 package common
 
 import (
+	"math/big"
+
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/params"
 	"github.com/vulcanize/vulcanizedb/pkg/core"
 )
 
-func CalcUnclesReward(block core.Block, uncles []*types.Header) float64 {
-	var unclesReward float64
+// Rewards are calculated in wei to avoid the rounding errors of floating point ether
+
+func CalcUnclesReward(schedule core.RewardSchedule, block core.Block, uncles []*types.Header) *big.Int {
+	unclesReward := big.NewInt(0)
 	for _, uncle := range uncles {
-		blockNumber := block.Number
-		staticBlockReward := float64(staticRewardByBlockNumber(blockNumber))
-		unclesReward += (1.0 + float64(uncle.Number.Int64()-block.Number)/8.0) * staticBlockReward
+		unclesReward.Add(unclesReward, CalcUncleReward(schedule, block, uncle))
 	}
 	return unclesReward
 }
 
-func CalcBlockReward(block core.Block, uncles []*types.Header) float64 {
-	blockNumber := block.Number
-	staticBlockReward := staticRewardByBlockNumber(blockNumber)
+// CalcUncleReward is the reward paid to the miner of an uncle included in block
+func CalcUncleReward(schedule core.RewardSchedule, block core.Block, uncle *types.Header) *big.Int {
+	staticBlockReward := schedule.StaticReward(block.Number)
+	uncleReward := new(big.Int).Add(uncle.Number, big.NewInt(8))
+	uncleReward.Sub(uncleReward, big.NewInt(block.Number))
+	uncleReward.Mul(uncleReward, staticBlockReward)
+	return uncleReward.Div(uncleReward, big.NewInt(8))
+}
+
+func CalcBlockReward(schedule core.RewardSchedule, block core.Block, uncles []*types.Header) *big.Int {
+	staticBlockReward := schedule.StaticReward(block.Number)
 	transactionFees := calcTransactionFees(block)
-	uncleInclusionRewards := calcUncleInclusionRewards(block, uncles)
-	return transactionFees + uncleInclusionRewards + staticBlockReward
+	uncleInclusionRewards := calcUncleInclusionRewards(schedule, block, uncles)
+	blockReward := new(big.Int).Add(transactionFees, uncleInclusionRewards)
+	return blockReward.Add(blockReward, staticBlockReward)
 }
 
-func calcTransactionFees(block core.Block) float64 {
-	var transactionFees float64
+func calcTransactionFees(block core.Block) *big.Int {
+	transactionFees := big.NewInt(0)
 	for _, transaction := range block.Transactions {
 		receipt := transaction.Receipt
-		transactionFees += float64(uint64(transaction.GasPrice) * receipt.GasUsed)
+		gasPrice, ok := new(big.Int).SetString(transaction.GasPrice, 10)
+		if !ok {
+			continue
+		}
+		gasUsed := new(big.Int).SetUint64(receipt.GasUsed)
+		transactionFees.Add(transactionFees, gasUsed.Mul(gasUsed, gasPrice))
 	}
-	return transactionFees / params.Ether
+	return transactionFees
 }
 
-func calcUncleInclusionRewards(block core.Block, uncles []*types.Header) float64 {
-	var uncleInclusionRewards float64
-	staticBlockReward := staticRewardByBlockNumber(block.Number)
-	for range uncles {
-		uncleInclusionRewards += staticBlockReward * 1 / 32
-	}
-	return uncleInclusionRewards
-}
-
-func staticRewardByBlockNumber(blockNumber int64) float64 {
-	var staticBlockReward float64
-	//https://blog.ethereum.org/2017/10/12/byzantium-hf-announcement/
-	if blockNumber >= 4370000 {
-		staticBlockReward = 3
-	} else {
-		staticBlockReward = 5
-	}
-	return staticBlockReward
+func calcUncleInclusionRewards(schedule core.RewardSchedule, block core.Block, uncles []*types.Header) *big.Int {
+	staticBlockReward := schedule.StaticReward(block.Number)
+	uncleInclusionReward := new(big.Int).Div(staticBlockReward, big.NewInt(32))
+	return uncleInclusionReward.Mul(uncleInclusionReward, big.NewInt(int64(len(uncles))))
 }
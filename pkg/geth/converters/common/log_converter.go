@@ -49,9 +49,11 @@ func ToCoreLog(gethLog types.Log) core.Log {
 	return core.Log{
 		Address:     strings.ToLower(gethLog.Address.Hex()),
 		BlockNumber: int64(gethLog.BlockNumber),
+		BlockHash:   gethLog.BlockHash.Hex(),
 		Topics:      hexTopics,
 		TxHash:      gethLog.TxHash.Hex(),
 		Index:       int64(gethLog.Index),
 		Data:        hexutil.Encode(gethLog.Data),
+		Removed:     gethLog.Removed,
 	}
 }
@@ -48,6 +48,7 @@ var _ = Describe("Conversion of GethLog to core.Log", func() {
 
 		expected := core.Log{
 			Address:     strings.ToLower(gethLog.Address.Hex()),
+			BlockHash:   gethLog.BlockHash.Hex(),
 			BlockNumber: int64(gethLog.BlockNumber),
 			Data:        hexutil.Encode(gethLog.Data),
 			TxHash:      gethLog.TxHash.Hex(),
@@ -61,12 +62,25 @@ var _ = Describe("Conversion of GethLog to core.Log", func() {
 		coreLog := vulcCommon.ToCoreLog(gethLog)
 
 		Expect(coreLog.Address).To(Equal(expected.Address))
+		Expect(coreLog.BlockHash).To(Equal(expected.BlockHash))
 		Expect(coreLog.BlockNumber).To(Equal(expected.BlockNumber))
 		Expect(coreLog.Data).To(Equal(expected.Data))
 		Expect(coreLog.Index).To(Equal(expected.Index))
 		Expect(coreLog.Topics[0]).To(Equal(expected.Topics[0]))
 		Expect(coreLog.Topics[1]).To(Equal(expected.Topics[1]))
 		Expect(coreLog.TxHash).To(Equal(expected.TxHash))
+		Expect(coreLog.Removed).To(BeFalse())
+	})
+
+	It("keeps the removed flag of logs dropped by a reorg", func() {
+		gethLog := types.Log{
+			BlockHash: common.HexToHash("0x656c34545f90a730a19008c0e7a7cd4fb3895064b48d6d69761bd5abad681056"),
+			Removed:   true,
+		}
+
+		coreLog := vulcCommon.ToCoreLog(gethLog)
+
+		Expect(coreLog.Removed).To(BeTrue())
 	})
 
 	It("converts geth log array to array of internal logs", func() {
@@ -17,69 +17,130 @@
 package rpc
 
 import (
-	"context"
+	"fmt"
 	"log"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
-	"golang.org/x/sync/errgroup"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/vulcanize/vulcanizedb/pkg/core"
 	vulcCommon "github.com/vulcanize/vulcanizedb/pkg/geth/converters/common"
 )
 
 type RpcTransactionConverter struct {
-	client core.EthClient
+	rpcClient   core.RpcClient
+	chainConfig *params.ChainConfig
 }
 
-func NewRpcTransactionConverter(client core.EthClient) *RpcTransactionConverter {
-	return &RpcTransactionConverter{client: client}
+// NewRpcTransactionConverter recovers transaction senders with the signer the chain config
+// prescribes for each block. Without a chain config, every sender is fetched from the node.
+func NewRpcTransactionConverter(rpcClient core.RpcClient, chainConfig *params.ChainConfig) *RpcTransactionConverter {
+	return &RpcTransactionConverter{rpcClient: rpcClient, chainConfig: chainConfig}
 }
 
 func (rtc *RpcTransactionConverter) ConvertTransactionsToCore(gethBlock *types.Block) ([]core.Transaction, error) {
-	var g errgroup.Group
-	coreTransactions := make([]core.Transaction, len(gethBlock.Transactions()))
-
-	for gethTransactionIndex, gethTransaction := range gethBlock.Transactions() {
-		//https://golang.org/doc/faq#closures_and_goroutines
-		transaction := gethTransaction
-		transactionIndex := uint(gethTransactionIndex)
-		g.Go(func() error {
-			from, err := rtc.client.TransactionSender(context.Background(), transaction, gethBlock.Hash(), transactionIndex)
-			if err != nil {
-				log.Println("transaction sender: ", err)
-				return err
-			}
-			coreTransaction := transToCoreTrans(transaction, &from)
-			coreTransaction, err = rtc.appendReceiptToTransaction(coreTransaction)
-			if err != nil {
-				log.Println("receipt: ", err)
-				return err
-			}
-			coreTransactions[transactionIndex] = coreTransaction
-			return nil
-		})
+	gethTransactions := gethBlock.Transactions()
+	coreTransactions := make([]core.Transaction, len(gethTransactions))
+	senders := make([]common.Address, len(gethTransactions))
+	var unrecovered []int
+	for i, transaction := range gethTransactions {
+		if rtc.chainConfig == nil {
+			unrecovered = append(unrecovered, i)
+			continue
+		}
+		sender, err := types.Sender(types.MakeSigner(rtc.chainConfig, gethBlock.Number()), transaction)
+		if err != nil {
+			unrecovered = append(unrecovered, i)
+			continue
+		}
+		senders[i] = sender
 	}
-	if err := g.Wait(); err != nil {
-		log.Println("transactions: ", err)
+	err := rtc.fetchSenders(gethBlock.Hash(), unrecovered, senders)
+	if err != nil {
+		log.Println("transaction senders: ", err)
+		return coreTransactions, err
+	}
+	for i, transaction := range gethTransactions {
+		coreTransactions[i] = transToCoreTrans(transaction, &senders[i], uint(i))
+	}
+	err = rtc.appendReceiptsToTransactions(coreTransactions)
+	if err != nil {
+		log.Println("receipts: ", err)
 		return coreTransactions, err
 	}
 	return coreTransactions, nil
 }
 
-func (rtc *RpcTransactionConverter) appendReceiptToTransaction(transaction core.Transaction) (core.Transaction, error) {
-	gethReceipt, err := rtc.client.TransactionReceipt(context.Background(), common.HexToHash(transaction.Hash))
+type rpcTransactionSender struct {
+	From *common.Address `json:"from"`
+}
+
+// fetchSenders asks the node, in a single batch request, for the senders of the transactions
+// at the given indexes whose signatures could not be recovered locally
+func (rtc *RpcTransactionConverter) fetchSenders(blockHash common.Hash, indexes []int, senders []common.Address) error {
+	if len(indexes) == 0 {
+		return nil
+	}
+	results := make([]rpcTransactionSender, len(indexes))
+	batch := make([]rpc.BatchElem, len(indexes))
+	for i, index := range indexes {
+		batch[i] = rpc.BatchElem{
+			Method: "eth_getTransactionByBlockHashAndIndex",
+			Args:   []interface{}{blockHash, hexutil.Uint64(index)},
+			Result: &results[i],
+		}
+	}
+	err := rtc.rpcClient.BatchCall(batch)
+	if err != nil {
+		return err
+	}
+	for i, elem := range batch {
+		if elem.Error != nil {
+			return elem.Error
+		}
+		if results[i].From == nil {
+			return fmt.Errorf("transaction %d not found in block %s", indexes[i], blockHash.Hex())
+		}
+		senders[indexes[i]] = *results[i].From
+	}
+	return nil
+}
+
+// appendReceiptsToTransactions fetches the receipts for all transactions in a single batch request
+func (rtc *RpcTransactionConverter) appendReceiptsToTransactions(transactions []core.Transaction) error {
+	if len(transactions) == 0 {
+		return nil
+	}
+	gethReceipts := make([]*types.Receipt, len(transactions))
+	batch := make([]rpc.BatchElem, len(transactions))
+	for i, transaction := range transactions {
+		batch[i] = rpc.BatchElem{
+			Method: "eth_getTransactionReceipt",
+			Args:   []interface{}{common.HexToHash(transaction.Hash)},
+			Result: &gethReceipts[i],
+		}
+	}
+	err := rtc.rpcClient.BatchCall(batch)
 	if err != nil {
-		return transaction, err
+		return err
+	}
+	for i, elem := range batch {
+		if elem.Error != nil {
+			return elem.Error
+		}
+		if gethReceipts[i] == nil {
+			return fmt.Errorf("receipt not found for transaction %s", transactions[i].Hash)
+		}
+		transactions[i].Receipt = vulcCommon.ToCoreReceipt(gethReceipts[i])
 	}
-	receipt := vulcCommon.ToCoreReceipt(gethReceipt)
-	transaction.Receipt = receipt
-	return transaction, nil
+	return nil
 }
 
-func transToCoreTrans(transaction *types.Transaction, from *common.Address) core.Transaction {
+func transToCoreTrans(transaction *types.Transaction, from *common.Address, index uint) core.Transaction {
 	data := hexutil.Encode(transaction.Data())
 	return core.Transaction{
 		Hash:     transaction.Hash().Hex(),
@@ -87,7 +148,8 @@ func transToCoreTrans(transaction *types.Transaction, from *common.Address) core
 		To:       strings.ToLower(addressToHex(transaction.To())),
 		From:     strings.ToLower(addressToHex(from)),
 		GasLimit: transaction.Gas(),
-		GasPrice: transaction.GasPrice().Int64(),
+		GasPrice: transaction.GasPrice().String(),
+		TxIndex:  int64(index),
 		Value:    transaction.Value().String(),
 		Data:     data,
 	}
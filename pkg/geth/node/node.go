@@ -18,6 +18,9 @@ package node
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"net/url"
 
 	"strconv"
 
@@ -57,8 +60,14 @@ type GanacheClient struct {
 	PropertiesReader
 }
 
+// MakeNode reads the node's properties, detecting the node type from the client
 func MakeNode(rpcClient core.RpcClient) core.Node {
-	pr := makePropertiesReader(rpcClient)
+	return MakeNodeOfType(rpcClient, getNodeType(rpcClient))
+}
+
+// MakeNodeOfType reads the node's properties for an explicitly configured node type
+func MakeNodeOfType(rpcClient core.RpcClient, nodeType core.NodeType) core.Node {
+	pr := makePropertiesReader(rpcClient, nodeType)
 	id, name := pr.NodeInfo()
 	return core.Node{
 		GenesisBlock: pr.GenesisBlock(),
@@ -68,8 +77,20 @@ func MakeNode(rpcClient core.RpcClient) core.Node {
 	}
 }
 
-func makePropertiesReader(client core.RpcClient) IPropertiesReader {
-	switch getNodeType(client) {
+// CheckChain returns an error unless the client serves the node's chain, identified by its
+// genesis block and network id
+func CheckChain(rpcClient core.RpcClient, node core.Node) error {
+	reader := PropertiesReader{client: rpcClient}
+	genesisBlock, networkID := reader.GenesisBlock(), reader.NetworkId()
+	if genesisBlock != node.GenesisBlock || networkID != node.NetworkID {
+		return fmt.Errorf("genesis block %s and network id %v differ from %s and %v",
+			genesisBlock, networkID, node.GenesisBlock, node.NetworkID)
+	}
+	return nil
+}
+
+func makePropertiesReader(client core.RpcClient, nodeType core.NodeType) IPropertiesReader {
+	switch nodeType {
 	case core.GETH:
 		return GethClient{PropertiesReader: PropertiesReader{client: client}}
 	case core.PARITY:
@@ -84,19 +105,72 @@ func makePropertiesReader(client core.RpcClient) IPropertiesReader {
 }
 
 func getNodeType(client core.RpcClient) core.NodeType {
-	if strings.Contains(client.IpcPath(), "infura") {
-		return core.INFURA
+	modules, _ := client.SupportedModules()
+	if _, ok := modules["admin"]; ok {
+		return core.GETH
 	}
-	if strings.Contains(client.IpcPath(), "127.0.0.1") || strings.Contains(client.IpcPath(), "localhost") {
+	var clientVersion string
+	client.CallContext(context.Background(), &clientVersion, "web3_clientVersion")
+	clientVersion = strings.ToLower(clientVersion)
+	if strings.Contains(clientVersion, "testrpc") || strings.Contains(clientVersion, "ganache") {
 		return core.GANACHE
 	}
-	modules, _ := client.SupportedModules()
-	if _, ok := modules["admin"]; ok {
+	if strings.HasPrefix(clientVersion, "geth") {
+		// geth only exposes the admin module over IPC by default, so it cannot tell a hosted node
+		// such as infura from a local one reached over HTTP; the endpoint's host can
+		if isHostedEndpoint(client.IpcPath()) {
+			return core.INFURA
+		}
 		return core.GETH
 	}
 	return core.PARITY
 }
 
+var privateNetworks = mustParseCIDRs("10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "fc00::/7")
+
+// isHostedEndpoint reports whether the configured endpoint is an HTTP or WebSocket url whose host
+// is neither a loopback nor a private network address; IPC paths are always local
+func isHostedEndpoint(endpoint string) bool {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return false
+	}
+	switch u.Scheme {
+	case "http", "https", "ws", "wss":
+	default:
+		return false
+	}
+	host := u.Hostname()
+	if host == "localhost" {
+		return false
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return true
+	}
+	if ip.IsLoopback() {
+		return false
+	}
+	for _, network := range privateNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+	return networks
+}
+
 func (reader PropertiesReader) NetworkId() float64 {
 	var version string
 	err := reader.client.CallContext(context.Background(), &version, "net_version")
@@ -85,6 +85,20 @@ var _ = Describe("Node Info", func() {
 		Expect(n.NetworkID).To(Equal(float64(1234)))
 	})
 
+	It("accepts clients serving the node's chain", func() {
+		client := fakes.NewMockRpcClient()
+		n := core.Node{GenesisBlock: EmpytHeaderHash, NetworkID: 1234}
+
+		Expect(node.CheckChain(client, n)).To(Succeed())
+	})
+
+	It("rejects clients serving another chain", func() {
+		client := fakes.NewMockRpcClient()
+		n := core.Node{GenesisBlock: EmpytHeaderHash, NetworkID: 1}
+
+		Expect(node.CheckChain(client, n)).NotTo(Succeed())
+	})
+
 	It("returns geth ID and client name for geth node", func() {
 		client := fakes.NewMockRpcClient()
 		supportedModules := make(map[string]string)
@@ -98,22 +112,59 @@ var _ = Describe("Node Info", func() {
 
 	It("returns infura ID and client name for infura node", func() {
 		client := fakes.NewMockRpcClient()
-		client.SetIpcPath("infura/path")
-		n := node.MakeNode(client)
+		n := node.MakeNodeOfType(client, core.INFURA)
 		Expect(n.ID).To(Equal("infura"))
 		Expect(n.ClientName).To(Equal("infura"))
 	})
 
 	It("returns local id and client name for Local node", func() {
 		client := fakes.NewMockRpcClient()
-		client.SetIpcPath("127.0.0.1")
-		n := node.MakeNode(client)
+		n := node.MakeNodeOfType(client, core.GANACHE)
 		Expect(n.ID).To(Equal("ganache"))
 		Expect(n.ClientName).To(Equal("ganache"))
+	})
+
+	It("uses the configured node type instead of detecting it", func() {
+		client := fakes.NewMockRpcClient()
+		supportedModules := make(map[string]string)
+		supportedModules["admin"] = "ok"
+		client.SetSupporedModules(supportedModules)
+
+		n := node.MakeNodeOfType(client, core.PARITY)
+		Expect(n.ID).To(Equal("ParityNode"))
+		Expect(n.ClientName).To(Equal("Parity/v1.2.3/"))
+	})
 
-		client.SetIpcPath("localhost")
-		n = node.MakeNode(client)
+	It("detects ganache from the client version", func() {
+		client := fakes.NewMockRpcClient()
+		client.SetClientVersion("EthereumJS TestRPC/v2.1.0/ethereum-js")
+		n := node.MakeNode(client)
 		Expect(n.ID).To(Equal("ganache"))
-		Expect(n.ClientName).To(Equal("ganache"))
+	})
+
+	It("detects a hosted geth node without the admin module from its endpoint", func() {
+		client := fakes.NewMockRpcClient()
+		client.SetIpcPath("https://mainnet.infura.io/v3/project")
+		client.SetClientVersion("Geth/v1.8.18-stable/linux-amd64/go1.11")
+		n := node.MakeNode(client)
+		Expect(n.ID).To(Equal("infura"))
+	})
+
+	It("treats geth without the admin module on a local endpoint as geth", func() {
+		for _, endpoint := range []string{"http://127.0.0.1:8545", "ws://localhost:8546", "http://192.168.1.20:8545", "/home/user/.ethereum/geth.ipc"} {
+			client := fakes.NewMockRpcClient()
+			client.SetIpcPath(endpoint)
+			client.SetClientVersion("Geth/v1.8.18-stable/linux-amd64/go1.11")
+			n := node.MakeNode(client)
+			Expect(n.ID).NotTo(Equal("infura"), endpoint)
+			Expect(n.ClientName).To(Equal("Geth/v1.7"), endpoint)
+		}
+	})
+
+	It("does not infer the node type from the client path", func() {
+		client := fakes.NewMockRpcClient()
+		client.SetIpcPath("http://127.0.0.1:8545")
+		n := node.MakeNode(client)
+		Expect(n.ID).To(Equal("ParityNode"))
 	})
 })
@@ -0,0 +1,138 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package history
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+type BackFillConfig struct {
+	// Number of concurrent requests made against the node
+	Workers int
+	// Number of block numbers retrieved before results are persisted
+	BatchSize int
+	// Number of times a failed retrieval is retried before the block number is skipped
+	MaxRetries int
+	// Delay before the first retry, doubled on every subsequent attempt
+	RetryDelay time.Duration
+}
+
+var DefaultBackFillConfig = BackFillConfig{
+	Workers:    4,
+	BatchSize:  100,
+	MaxRetries: 3,
+	RetryDelay: time.Second,
+}
+
+// retrieveFunc fetches the data for a block number from the chain and returns
+// a function that persists it, so that persistence can happen in block order
+type retrieveFunc func(blockNumber int64) (persistFunc, error)
+type persistFunc func() error
+
+// flushFunc is called after each batch has been persisted, for callers that stage a batch
+// and write it at once
+type flushFunc func() error
+
+type retrieval struct {
+	persist persistFunc
+	err     error
+}
+
+// backFill retrieves block numbers concurrently in batches and persists each batch in order.
+// Block numbers that cannot be retrieved after all retries are logged and skipped so that they
+// are picked up again as missing on the next pass; persistence errors halt the back fill.
+func backFill(config BackFillConfig, blockNumbers []int64, retrieve retrieveFunc, flush flushFunc) (int, error) {
+	config = config.withDefaults()
+	populated := 0
+	for start := 0; start < len(blockNumbers); start += config.BatchSize {
+		end := start + config.BatchSize
+		if end > len(blockNumbers) {
+			end = len(blockNumbers)
+		}
+		batch := blockNumbers[start:end]
+		results := retrieveBatch(config, batch, retrieve)
+		persisted := 0
+		for i, result := range results {
+			if result.err != nil {
+				log.Printf("failed to retrieve block number %d: %v\n", batch[i], result.err)
+				continue
+			}
+			err := result.persist()
+			if err != nil {
+				return populated, err
+			}
+			persisted++
+		}
+		if flush != nil {
+			err := flush()
+			if err != nil {
+				return populated, err
+			}
+		}
+		populated += persisted
+		log.Printf("Backfilled through block %d (%d of %d)\n", batch[len(batch)-1], end, len(blockNumbers))
+	}
+	return populated, nil
+}
+
+func retrieveBatch(config BackFillConfig, batch []int64, retrieve retrieveFunc) []retrieval {
+	results := make([]retrieval, len(batch))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < config.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				persist, err := retrieveWithRetry(config, batch[i], retrieve)
+				results[i] = retrieval{persist: persist, err: err}
+			}
+		}()
+	}
+	for i := range batch {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
+
+func retrieveWithRetry(config BackFillConfig, blockNumber int64, retrieve retrieveFunc) (persistFunc, error) {
+	delay := config.RetryDelay
+	persist, err := retrieve(blockNumber)
+	for attempt := 0; err != nil && attempt < config.MaxRetries; attempt++ {
+		time.Sleep(delay)
+		delay *= 2
+		persist, err = retrieve(blockNumber)
+	}
+	return persist, err
+}
+
+func (config BackFillConfig) withDefaults() BackFillConfig {
+	if config.Workers < 1 {
+		config.Workers = DefaultBackFillConfig.Workers
+	}
+	if config.BatchSize < 1 {
+		config.BatchSize = DefaultBackFillConfig.BatchSize
+	}
+	if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	}
+	return config
+}
@@ -17,6 +17,8 @@
 package history
 
 import (
+	"log"
+
 	"github.com/vulcanize/vulcanizedb/pkg/core"
 	"github.com/vulcanize/vulcanizedb/pkg/datastore"
 )
@@ -24,22 +26,39 @@ import (
 type BlockValidator struct {
 	blockchain      core.BlockChain
 	blockRepository datastore.BlockRepository
+	reorgDetector   ReorgDetector
 	windowSize      int
+	finalityDepth   int64
 }
 
-func NewBlockValidator(blockchain core.BlockChain, blockRepository datastore.BlockRepository, windowSize int) *BlockValidator {
+func NewBlockValidator(blockchain core.BlockChain, blockRepository datastore.BlockRepository, reorgRepository datastore.ReorgRepository, windowSize int, finalityDepth int64) *BlockValidator {
 	return &BlockValidator{
 		blockchain:      blockchain,
 		blockRepository: blockRepository,
+		reorgDetector:   NewReorgDetector(blockchain, blockRepository, reorgRepository),
 		windowSize:      windowSize,
+		finalityDepth:   finalityDepth,
 	}
 }
 
 func (bv BlockValidator) ValidateBlocks() ValidationWindow {
 	window := MakeValidationWindow(bv.blockchain, bv.windowSize)
+	bv.detectReorg(window.UpperBound)
 	blockNumbers := MakeRange(window.LowerBound, window.UpperBound)
 	RetrieveAndUpdateBlocks(bv.blockchain, bv.blockRepository, blockNumbers)
 	lastBlock := bv.blockchain.LastBlock().Int64()
-	bv.blockRepository.SetBlocksStatus(lastBlock)
+	bv.blockRepository.SetBlocksStatus(lastBlock, bv.finalityDepth)
 	return window
 }
+
+func (bv BlockValidator) detectReorg(blockNumber int64) {
+	head, err := bv.blockchain.GetBlockByNumber(blockNumber)
+	if err != nil {
+		log.Printf("failed to retrieve block number: %d\n", blockNumber)
+		return
+	}
+	_, err = bv.reorgDetector.DetectReorg(head)
+	if err != nil {
+		log.Println("Error resolving reorg: ", err)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"github.com/vulcanize/vulcanizedb/pkg/core"
 	"github.com/vulcanize/vulcanizedb/pkg/fakes"
 	"github.com/vulcanize/vulcanizedb/pkg/history"
 	"math/big"
@@ -31,7 +32,7 @@ var _ = Describe("Blocks validator", func() {
 		blockChain := fakes.NewMockBlockChain()
 		blockChain.SetLastBlock(big.NewInt(7))
 		blocksRepository := fakes.NewMockBlockRepository()
-		validator := history.NewBlockValidator(blockChain, blocksRepository, 2)
+		validator := history.NewBlockValidator(blockChain, blocksRepository, fakes.NewMockReorgRepository(), 2, 20)
 
 		window := validator.ValidateBlocks()
 
@@ -39,6 +40,37 @@ var _ = Describe("Blocks validator", func() {
 		blocksRepository.AssertCreateOrUpdateBlockCallCountEquals(3)
 	})
 
+	It("sets the status of blocks using the finality depth", func() {
+		blockChain := fakes.NewMockBlockChain()
+		blockChain.SetLastBlock(big.NewInt(7))
+		blocksRepository := fakes.NewMockBlockRepository()
+		validator := history.NewBlockValidator(blockChain, blocksRepository, fakes.NewMockReorgRepository(), 2, 5)
+
+		validator.ValidateBlocks()
+
+		blocksRepository.AssertSetBlockStatusCalledWith(7, 5)
+	})
+
+	It("records a reorg when the head does not descend from the stored chain", func() {
+		blockChain := fakes.NewMockBlockChain()
+		blockChain.SetLastBlock(big.NewInt(7))
+		blockChain.SetGetBlockByNumberReturnBlocks([]core.Block{
+			{Number: 6, Hash: "new6", ParentHash: "hash5"},
+			{Number: 7, Hash: "new7", ParentHash: "new6"},
+		})
+		blocksRepository := fakes.NewMockBlockRepository()
+		blocksRepository.SetGetBlockReturnBlocks([]core.Block{
+			{Number: 5, Hash: "hash5"},
+			{Number: 6, Hash: "old6", ParentHash: "hash5"},
+		})
+		reorgRepository := fakes.NewMockReorgRepository()
+		validator := history.NewBlockValidator(blockChain, blocksRepository, reorgRepository, 2, 20)
+
+		validator.ValidateBlocks()
+
+		reorgRepository.AssertCreateReorgCalledWith([]core.Reorg{{BlockNumber: 6, Depth: 1, OldHash: "old6", NewHash: "new6"}})
+	})
+
 	It("returns the number of largest block", func() {
 		blockChain := fakes.NewMockBlockChain()
 		blockChain.SetLastBlock(big.NewInt(3))
@@ -0,0 +1,149 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"text/template"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
+)
+
+const HeaderChainReportTemplate = `Validated header chain |{{.LowerBound}}|--|{{.UpperBound}}|
+Filled {{len .MissingBlockNumbers}} missing headers, replaced {{len .ReplacedBlockNumbers}} forked headers
+
+`
+
+var ParsedHeaderChainReportTemplate = *template.Must(template.New("headerChainReport").Parse(HeaderChainReportTemplate))
+
+const headerChainChunkSize = 1000
+
+// ErrHeaderChainChanged is returned when the node's header at a block number no longer links to its
+// validated child, e.g. after a reorg during validation; the next run revalidates from the new head
+var ErrHeaderChainChanged = errors.New("header chain changed during validation")
+
+type HeaderChainReport struct {
+	LowerBound           int64
+	UpperBound           int64
+	MissingBlockNumbers  []int64
+	ReplacedBlockNumbers []int64
+}
+
+func (report HeaderChainReport) Log(out io.Writer) {
+	ParsedHeaderChainReportTemplate.Execute(out, report)
+}
+
+// HeaderChainValidator walks stored headers from the chain head down through their parent hashes,
+// inserting headers that are missing and replacing headers that are not ancestors of the head.
+// Once a range has been validated, later runs stop as soon as they reach it with an intact link.
+type HeaderChainValidator struct {
+	blockChain          core.BlockChain
+	headerRepository    datastore.HeaderRepository
+	lastValidatedNumber int64
+}
+
+func NewHeaderChainValidator(blockChain core.BlockChain, headerRepository datastore.HeaderRepository) *HeaderChainValidator {
+	return &HeaderChainValidator{
+		blockChain:          blockChain,
+		headerRepository:    headerRepository,
+		lastValidatedNumber: -1,
+	}
+}
+
+func (validator *HeaderChainValidator) ValidateHeaderChain(startingBlockNumber, endingBlockNumber int64) (HeaderChainReport, error) {
+	report := HeaderChainReport{LowerBound: startingBlockNumber, UpperBound: endingBlockNumber}
+	head, err := validator.blockChain.GetHeaderByNumber(endingBlockNumber)
+	if err != nil {
+		return report, err
+	}
+	expectedHash := head.Hash
+	for chunkEnd := endingBlockNumber; chunkEnd >= startingBlockNumber; chunkEnd -= headerChainChunkSize {
+		chunkStart := chunkEnd - headerChainChunkSize + 1
+		if chunkStart < startingBlockNumber {
+			chunkStart = startingBlockNumber
+		}
+		storedHeaders, err := validator.getHeadersByNumber(chunkStart, chunkEnd)
+		if err != nil {
+			return report, err
+		}
+		for blockNumber := chunkEnd; blockNumber >= chunkStart; blockNumber-- {
+			header, stored := storedHeaders[blockNumber]
+			if stored && header.Hash == expectedHash && blockNumber <= validator.lastValidatedNumber {
+				validator.lastValidatedNumber = endingBlockNumber
+				return report, nil
+			}
+			if !stored || header.Hash != expectedHash {
+				header, err = validator.repairHeader(blockNumber, expectedHash)
+				if err != nil {
+					return report, err
+				}
+				if stored {
+					report.ReplacedBlockNumbers = append(report.ReplacedBlockNumbers, blockNumber)
+				} else {
+					report.MissingBlockNumbers = append(report.MissingBlockNumbers, blockNumber)
+				}
+			}
+			expectedHash, err = parentHash(header)
+			if err != nil {
+				return report, err
+			}
+		}
+	}
+	validator.lastValidatedNumber = endingBlockNumber
+	return report, nil
+}
+
+func (validator *HeaderChainValidator) getHeadersByNumber(startingBlockNumber, endingBlockNumber int64) (map[int64]core.Header, error) {
+	headers, err := validator.headerRepository.GetHeaders(startingBlockNumber, endingBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	headersByNumber := make(map[int64]core.Header, len(headers))
+	for _, header := range headers {
+		headersByNumber[header.BlockNumber] = header
+	}
+	return headersByNumber, nil
+}
+
+func (validator *HeaderChainValidator) repairHeader(blockNumber int64, expectedHash string) (core.Header, error) {
+	header, err := validator.blockChain.GetHeaderByNumber(blockNumber)
+	if err != nil {
+		return header, err
+	}
+	if header.Hash != expectedHash {
+		return header, fmt.Errorf("%v: header %d is %s, expected %s", ErrHeaderChainChanged, blockNumber, header.Hash, expectedHash)
+	}
+	_, err = validator.headerRepository.CreateOrUpdateHeader(header)
+	if err != nil && err != repositories.ErrValidHeaderExists {
+		return header, err
+	}
+	return header, nil
+}
+
+func parentHash(header core.Header) (string, error) {
+	var raw struct {
+		ParentHash common.Hash `json:"parentHash"`
+	}
+	err := json.Unmarshal(header.Raw, &raw)
+	return raw.ParentHash.Hex(), err
+}
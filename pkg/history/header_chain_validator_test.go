@@ -0,0 +1,147 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package history_test
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+	"github.com/vulcanize/vulcanizedb/pkg/history"
+)
+
+func hash(name string) string {
+	return common.BytesToHash([]byte(name)).Hex()
+}
+
+func headerWithParent(blockNumber int64, name, parentName string) core.Header {
+	return core.Header{
+		BlockNumber: blockNumber,
+		Hash:        hash(name),
+		Raw:         []byte(fmt.Sprintf(`{"parentHash":"%s"}`, hash(parentName))),
+	}
+}
+
+var _ = Describe("Header chain validator", func() {
+	var (
+		blockChain       *fakes.MockBlockChain
+		headerRepository *fakes.MockHeaderRepository
+		validator        *history.HeaderChainValidator
+	)
+
+	BeforeEach(func() {
+		blockChain = fakes.NewMockBlockChain()
+		headerRepository = fakes.NewMockHeaderRepository()
+		validator = history.NewHeaderChainValidator(blockChain, headerRepository)
+	})
+
+	It("does not modify a continuous header chain", func() {
+		headers := []core.Header{
+			headerWithParent(1, "one", "zero"),
+			headerWithParent(2, "two", "one"),
+			headerWithParent(3, "three", "two"),
+		}
+		headerRepository.SetGetHeadersReturnHeaders(headers)
+		blockChain.SetGetHeaderByNumberReturnHeaders(headers)
+
+		report, err := validator.ValidateHeaderChain(1, 3)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.MissingBlockNumbers).To(BeEmpty())
+		Expect(report.ReplacedBlockNumbers).To(BeEmpty())
+		headerRepository.AssertCreateOrUpdateHeaderCallCountAndPassedBlockNumbers(0, nil)
+	})
+
+	It("fills gaps in the header chain", func() {
+		headerRepository.SetGetHeadersReturnHeaders([]core.Header{
+			headerWithParent(1, "one", "zero"),
+			headerWithParent(3, "three", "two"),
+		})
+		blockChain.SetGetHeaderByNumberReturnHeaders([]core.Header{
+			headerWithParent(2, "two", "one"),
+			headerWithParent(3, "three", "two"),
+		})
+
+		report, err := validator.ValidateHeaderChain(1, 3)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.MissingBlockNumbers).To(Equal([]int64{2}))
+		headerRepository.AssertCreateOrUpdateHeaderCallCountAndPassedBlockNumbers(1, []int64{2})
+	})
+
+	It("replaces forked headers below the validation window", func() {
+		headerRepository.SetGetHeadersReturnHeaders([]core.Header{
+			headerWithParent(1, "one", "zero"),
+			headerWithParent(2, "uncle two", "one"),
+			headerWithParent(3, "uncle three", "uncle two"),
+			headerWithParent(4, "four", "three"),
+		})
+		blockChain.SetGetHeaderByNumberReturnHeaders([]core.Header{
+			headerWithParent(2, "two", "one"),
+			headerWithParent(3, "three", "two"),
+			headerWithParent(4, "four", "three"),
+		})
+
+		report, err := validator.ValidateHeaderChain(1, 4)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.ReplacedBlockNumbers).To(Equal([]int64{3, 2}))
+		headerRepository.AssertCreateOrUpdateHeaderCallCountAndPassedBlockNumbers(2, []int64{3, 2})
+	})
+
+	It("does not store a header that does not link to its validated child", func() {
+		headerRepository.SetGetHeadersReturnHeaders([]core.Header{
+			headerWithParent(1, "one", "zero"),
+			headerWithParent(2, "uncle two", "one"),
+		})
+		blockChain.SetGetHeaderByNumberReturnHeaders([]core.Header{
+			headerWithParent(2, "reorged two", "one"),
+			headerWithParent(3, "three", "two"),
+		})
+
+		_, err := validator.ValidateHeaderChain(1, 3)
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(history.ErrHeaderChainChanged.Error()))
+		headerRepository.AssertCreateOrUpdateHeaderCallCountAndPassedBlockNumbers(1, []int64{3})
+	})
+
+	It("returns an error if a stored header cannot be decoded", func() {
+		headers := []core.Header{{BlockNumber: 1, Hash: hash("one"), Raw: []byte("not json")}}
+		headerRepository.SetGetHeadersReturnHeaders(headers)
+		blockChain.SetGetHeaderByNumberReturnHeaders(headers)
+
+		_, err := validator.ValidateHeaderChain(1, 1)
+
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("logs the report", func() {
+		report := history.HeaderChainReport{LowerBound: 1, UpperBound: 4, ReplacedBlockNumbers: []int64{3, 2}}
+		expectedMessage := &bytes.Buffer{}
+		history.ParsedHeaderChainReportTemplate.Execute(expectedMessage, report)
+		actualMessage := &bytes.Buffer{}
+
+		report.Log(actualMessage)
+
+		Expect(actualMessage).To(Equal(expectedMessage))
+	})
+})
@@ -17,6 +17,8 @@
 package history
 
 import (
+	"log"
+
 	"github.com/vulcanize/vulcanizedb/pkg/core"
 	"github.com/vulcanize/vulcanizedb/pkg/datastore"
 )
@@ -25,13 +27,15 @@ type HeaderValidator struct {
 	blockChain       core.BlockChain
 	headerRepository datastore.HeaderRepository
 	windowSize       int
+	finalityDepth    int64
 }
 
-func NewHeaderValidator(blockChain core.BlockChain, repository datastore.HeaderRepository, windowSize int) HeaderValidator {
+func NewHeaderValidator(blockChain core.BlockChain, repository datastore.HeaderRepository, windowSize int, finalityDepth int64) HeaderValidator {
 	return HeaderValidator{
 		blockChain:       blockChain,
 		headerRepository: repository,
 		windowSize:       windowSize,
+		finalityDepth:    finalityDepth,
 	}
 }
 
@@ -39,5 +43,9 @@ func (validator HeaderValidator) ValidateHeaders() ValidationWindow {
 	window := MakeValidationWindow(validator.blockChain, validator.windowSize)
 	blockNumbers := MakeRange(window.LowerBound, window.UpperBound)
 	RetrieveAndUpdateHeaders(validator.blockChain, validator.headerRepository, blockNumbers)
+	err := validator.headerRepository.SetHeadersStatus(window.UpperBound, validator.finalityDepth)
+	if err != nil {
+		log.Println("Error setting header status: ", err)
+	}
 	return window
 }
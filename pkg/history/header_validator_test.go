@@ -29,10 +29,21 @@ var _ = Describe("Header validator", func() {
 		headerRepository.SetMissingBlockNumbers([]int64{})
 		blockChain := fakes.NewMockBlockChain()
 		blockChain.SetLastBlock(big.NewInt(3))
-		validator := history.NewHeaderValidator(blockChain, headerRepository, 2)
+		validator := history.NewHeaderValidator(blockChain, headerRepository, 2, 20)
 
 		validator.ValidateHeaders()
 
 		headerRepository.AssertCreateOrUpdateHeaderCallCountAndPassedBlockNumbers(3, []int64{1, 2, 3})
 	})
+
+	It("sets the status of headers using the finality depth", func() {
+		headerRepository := fakes.NewMockHeaderRepository()
+		blockChain := fakes.NewMockBlockChain()
+		blockChain.SetLastBlock(big.NewInt(3))
+		validator := history.NewHeaderValidator(blockChain, headerRepository, 2, 1)
+
+		validator.ValidateHeaders()
+
+		headerRepository.AssertSetHeadersStatusCalledWith(3, 1)
+	})
 })
@@ -0,0 +1,86 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package history
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+type HeadSubscriber interface {
+	SubscribeNewHead(ctx context.Context, heads chan<- *types.Header) (ethereum.Subscription, error)
+}
+
+// NewHeads signals on the returned channel whenever the node reports a new chain head.
+// If the connection does not support subscriptions (e.g. http) or the subscription fails,
+// it falls back to signalling every pollingInterval, resubscribing on each tick where possible.
+// Signals that are not consumed yet are coalesced.
+func NewHeads(subscriber HeadSubscriber, pollingInterval time.Duration) <-chan struct{} {
+	signals := make(chan struct{}, 1)
+	go trackHeads(subscriber, pollingInterval, signals)
+	return signals
+}
+
+func trackHeads(subscriber HeadSubscriber, pollingInterval time.Duration, signals chan<- struct{}) {
+	ticker := time.NewTicker(pollingInterval)
+	defer ticker.Stop()
+	subscriptionsSupported := true
+	lastErr := ""
+	for {
+		if subscriptionsSupported {
+			err := followNewHeads(subscriber, signals)
+			if err == rpc.ErrNotificationsUnsupported {
+				log.Printf("new head subscriptions are not supported by the node, polling every %v\n", pollingInterval)
+				subscriptionsSupported = false
+			} else if err != nil && err.Error() != lastErr {
+				log.Printf("new head subscription failed, polling every %v until resubscribed: %v\n", pollingInterval, err)
+				lastErr = err.Error()
+			}
+		}
+		<-ticker.C
+		signal(signals)
+	}
+}
+
+func followNewHeads(subscriber HeadSubscriber, signals chan<- struct{}) error {
+	heads := make(chan *types.Header)
+	subscription, err := subscriber.SubscribeNewHead(context.Background(), heads)
+	if err != nil {
+		return err
+	}
+	defer subscription.Unsubscribe()
+	for {
+		select {
+		case <-heads:
+			signal(signals)
+		case err := <-subscription.Err():
+			return err
+		}
+	}
+}
+
+func signal(signals chan<- struct{}) {
+	select {
+	case signals <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,69 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package history_test
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+	"github.com/vulcanize/vulcanizedb/pkg/history"
+)
+
+var _ = Describe("Tracking new heads", func() {
+	var blockChain *fakes.MockBlockChain
+
+	BeforeEach(func() {
+		blockChain = fakes.NewMockBlockChain()
+	})
+
+	It("signals when the subscription delivers a new head", func() {
+		blockChain.SetNewHeads([]*types.Header{{Number: big.NewInt(1)}})
+
+		newHeads := history.NewHeads(blockChain, time.Hour)
+
+		Eventually(newHeads).Should(Receive())
+	})
+
+	It("falls back to polling when subscriptions are not supported", func() {
+		blockChain.SetSubscribeNewHeadErr(rpc.ErrNotificationsUnsupported)
+
+		newHeads := history.NewHeads(blockChain, 10*time.Millisecond)
+
+		Eventually(newHeads).Should(Receive())
+		Eventually(newHeads).Should(Receive())
+	})
+
+	It("polls while the subscription fails", func() {
+		blockChain.SetSubscribeNewHeadErr(fakes.FakeError)
+
+		newHeads := history.NewHeads(blockChain, 10*time.Millisecond)
+
+		Eventually(newHeads).Should(Receive())
+	})
+
+	It("does not signal without new heads before the polling interval", func() {
+		newHeads := history.NewHeads(blockChain, time.Hour)
+
+		Consistently(newHeads, 50*time.Millisecond).ShouldNot(Receive())
+	})
+})
@@ -23,13 +23,45 @@ import (
 	"github.com/vulcanize/vulcanizedb/pkg/datastore"
 )
 
-func PopulateMissingBlocks(blockchain core.BlockChain, blockRepository datastore.BlockRepository, startingBlockNumber int64) int {
+// PopulateMissingBlocks retrieves missing blocks in batches and writes each batch at once
+func PopulateMissingBlocks(blockchain core.BlockChain, blockRepository datastore.BlockRepository, blockWriter datastore.BlockWriter, startingBlockNumber int64, config BackFillConfig) (int, error) {
 	lastBlock := blockchain.LastBlock().Int64()
 	blockRange := blockRepository.MissingBlockNumbers(startingBlockNumber, lastBlock, blockchain.Node().ID)
 	log.SetPrefix("")
 	log.Printf("Backfilling %d blocks\n\n", len(blockRange))
-	RetrieveAndUpdateBlocks(blockchain, blockRepository, blockRange)
-	return len(blockRange)
+	var batch []core.Block
+	return backFill(config, blockRange, func(blockNumber int64) (persistFunc, error) {
+		block, err := blockchain.GetBlockByNumber(blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		return func() error {
+			batch = append(batch, block)
+			return nil
+		}, nil
+	}, func() error {
+		err := blockWriter.WriteBlocks(batch)
+		batch = nil
+		return err
+	})
+}
+
+// RefreshFlaggedBlocks re-fetches blocks flagged as needing a refresh, e.g. those stored with
+// int64 values that wrapped around, and corrects their values in place
+func RefreshFlaggedBlocks(blockchain core.BlockChain, blockRepository datastore.BlockRepository, config BackFillConfig) (int, error) {
+	blockNumbers := blockRepository.BlockNumbersNeedingRefresh(blockchain.Node().ID)
+	if len(blockNumbers) > 0 {
+		log.Printf("Refreshing %d blocks\n", len(blockNumbers))
+	}
+	return backFill(config, blockNumbers, func(blockNumber int64) (persistFunc, error) {
+		block, err := blockchain.GetBlockByNumber(blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		return func() error {
+			return blockRepository.RefreshBlock(block)
+		}, nil
+	}, nil)
 }
 
 func RetrieveAndUpdateBlocks(blockchain core.BlockChain, blockRepository datastore.BlockRepository, blockNumbers []int64) int {
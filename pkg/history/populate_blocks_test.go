@@ -25,10 +25,14 @@ import (
 )
 
 var _ = Describe("Populating blocks", func() {
-	var blockRepository *fakes.MockBlockRepository
+	var (
+		blockRepository *fakes.MockBlockRepository
+		blockWriter     *fakes.MockBlockWriter
+	)
 
 	BeforeEach(func() {
 		blockRepository = fakes.NewMockBlockRepository()
+		blockWriter = fakes.NewMockBlockWriter()
 	})
 
 	It("fills in the only missing block (BlockNumber 1)", func() {
@@ -36,11 +40,11 @@ var _ = Describe("Populating blocks", func() {
 		blockChain.SetLastBlock(big.NewInt(2))
 		blockRepository.SetMissingBlockNumbersReturnArray([]int64{2})
 
-		blocksAdded := history.PopulateMissingBlocks(blockChain, blockRepository, 1)
-		_, err := blockRepository.GetBlock(1)
+		blocksAdded, err := history.PopulateMissingBlocks(blockChain, blockRepository, blockWriter, 1, history.DefaultBackFillConfig)
 
+		Expect(err).NotTo(HaveOccurred())
 		Expect(blocksAdded).To(Equal(1))
-		Expect(err).ToNot(HaveOccurred())
+		blockWriter.AssertWriteBlocksCalledWithBlockNumbers([]int64{2})
 	})
 
 	It("fills in the three missing blocks (Numbers: 5,8,10)", func() {
@@ -48,10 +52,12 @@ var _ = Describe("Populating blocks", func() {
 		blockChain.SetLastBlock(big.NewInt(13))
 		blockRepository.SetMissingBlockNumbersReturnArray([]int64{5, 8, 10})
 
-		blocksAdded := history.PopulateMissingBlocks(blockChain, blockRepository, 5)
+		blocksAdded, err := history.PopulateMissingBlocks(blockChain, blockRepository, blockWriter, 5, history.DefaultBackFillConfig)
 
+		Expect(err).NotTo(HaveOccurred())
 		Expect(blocksAdded).To(Equal(3))
-		blockRepository.AssertCreateOrUpdateBlocksCallCountAndBlockNumbersEquals(3, []int64{5, 8, 10})
+		blockWriter.AssertWriteBlocksCallCountEquals(1)
+		blockWriter.AssertWriteBlocksCalledWithBlockNumbers([]int64{5, 8, 10})
 	})
 
 	It("returns the number of blocks created", func() {
@@ -59,8 +65,9 @@ var _ = Describe("Populating blocks", func() {
 		blockChain.SetLastBlock(big.NewInt(6))
 		blockRepository.SetMissingBlockNumbersReturnArray([]int64{4, 5})
 
-		numberOfBlocksCreated := history.PopulateMissingBlocks(blockChain, blockRepository, 3)
+		numberOfBlocksCreated, err := history.PopulateMissingBlocks(blockChain, blockRepository, blockWriter, 3, history.DefaultBackFillConfig)
 
+		Expect(err).NotTo(HaveOccurred())
 		Expect(numberOfBlocksCreated).To(Equal(2))
 	})
 
@@ -81,4 +88,68 @@ var _ = Describe("Populating blocks", func() {
 
 		blockRepository.AssertCreateOrUpdateBlockCallCountEquals(0)
 	})
+
+	It("persists blocks in order when retrieving with multiple workers", func() {
+		blockChain := fakes.NewMockBlockChain()
+		blockChain.SetLastBlock(big.NewInt(10))
+		blockRepository.SetMissingBlockNumbersReturnArray(history.MakeRange(1, 10))
+		config := history.BackFillConfig{Workers: 4, BatchSize: 3}
+
+		blocksAdded, err := history.PopulateMissingBlocks(blockChain, blockRepository, blockWriter, 1, config)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(blocksAdded).To(Equal(10))
+		blockWriter.AssertWriteBlocksCallCountEquals(4)
+		blockWriter.AssertWriteBlocksCalledWithBlockNumbers(history.MakeRange(1, 10))
+	})
+
+	It("skips blocks that cannot be retrieved after retrying", func() {
+		blockChain := fakes.NewMockBlockChain()
+		blockChain.SetLastBlock(big.NewInt(3))
+		blockChain.SetGetBlockByNumberErr(fakes.FakeError)
+		blockRepository.SetMissingBlockNumbersReturnArray([]int64{1, 2, 3})
+		config := history.BackFillConfig{Workers: 2, BatchSize: 2, MaxRetries: 2}
+
+		blocksAdded, err := history.PopulateMissingBlocks(blockChain, blockRepository, blockWriter, 1, config)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(blocksAdded).To(Equal(0))
+		blockWriter.AssertWriteBlocksCalledWithBlockNumbers(nil)
+	})
+
+	It("returns an error if writing a batch fails", func() {
+		blockChain := fakes.NewMockBlockChain()
+		blockChain.SetLastBlock(big.NewInt(3))
+		blockRepository.SetMissingBlockNumbersReturnArray([]int64{1, 2, 3})
+		blockWriter.SetWriteBlocksReturnErr(fakes.FakeError)
+		config := history.BackFillConfig{BatchSize: 2}
+
+		blocksAdded, err := history.PopulateMissingBlocks(blockChain, blockRepository, blockWriter, 1, config)
+
+		Expect(err).To(MatchError(fakes.FakeError))
+		Expect(blocksAdded).To(Equal(0))
+		blockWriter.AssertWriteBlocksCallCountEquals(1)
+	})
+
+	It("refreshes flagged blocks in place", func() {
+		blockChain := fakes.NewMockBlockChain()
+		blockRepository.SetBlockNumbersNeedingRefreshReturnArray([]int64{3, 7})
+
+		refreshed, err := history.RefreshFlaggedBlocks(blockChain, blockRepository, history.DefaultBackFillConfig)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(refreshed).To(Equal(2))
+		blockRepository.AssertRefreshBlockCalledWithBlockNumbers([]int64{3, 7})
+		blockWriter.AssertWriteBlocksCallCountEquals(0)
+	})
+
+	It("returns an error if refreshing a block fails", func() {
+		blockChain := fakes.NewMockBlockChain()
+		blockRepository.SetBlockNumbersNeedingRefreshReturnArray([]int64{3})
+		blockRepository.SetRefreshBlockReturnErr(fakes.FakeError)
+
+		_, err := history.RefreshFlaggedBlocks(blockChain, blockRepository, history.DefaultBackFillConfig)
+
+		Expect(err).To(MatchError(fakes.FakeError))
+	})
 })
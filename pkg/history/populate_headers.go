@@ -24,16 +24,24 @@ import (
 	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/repositories"
 )
 
-func PopulateMissingHeaders(blockchain core.BlockChain, headerRepository datastore.HeaderRepository, startingBlockNumber int64) (int, error) {
+func PopulateMissingHeaders(blockchain core.BlockChain, headerRepository datastore.HeaderRepository, startingBlockNumber int64, config BackFillConfig) (int, error) {
 	lastBlock := blockchain.LastBlock().Int64()
 	blockRange := headerRepository.MissingBlockNumbers(startingBlockNumber, lastBlock, blockchain.Node().ID)
 	log.SetPrefix("")
 	log.Printf("Backfilling %d blocks\n\n", len(blockRange))
-	_, err := RetrieveAndUpdateHeaders(blockchain, headerRepository, blockRange)
-	if err != nil {
-		return 0, err
-	}
-	return len(blockRange), nil
+	return backFill(config, blockRange, func(blockNumber int64) (persistFunc, error) {
+		header, err := blockchain.GetHeaderByNumber(blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		return func() error {
+			_, err := headerRepository.CreateOrUpdateHeader(header)
+			if err == repositories.ErrValidHeaderExists {
+				return nil
+			}
+			return err
+		}, nil
+	}, nil)
 }
 
 func RetrieveAndUpdateHeaders(chain core.BlockChain, headerRepository datastore.HeaderRepository, blockNumbers []int64) (int, error) {
@@ -39,7 +39,7 @@ var _ = Describe("Populating headers", func() {
 		blockChain.SetLastBlock(big.NewInt(2))
 		headerRepository.SetMissingBlockNumbers([]int64{2})
 
-		headersAdded, err := history.PopulateMissingHeaders(blockChain, headerRepository, 1)
+		headersAdded, err := history.PopulateMissingHeaders(blockChain, headerRepository, 1, history.DefaultBackFillConfig)
 
 		Expect(err).NotTo(HaveOccurred())
 		Expect(headersAdded).To(Equal(1))
@@ -50,7 +50,7 @@ var _ = Describe("Populating headers", func() {
 		blockChain.SetLastBlock(big.NewInt(2))
 		headerRepository.SetMissingBlockNumbers([]int64{2})
 
-		_, err := history.PopulateMissingHeaders(blockChain, headerRepository, 1)
+		_, err := history.PopulateMissingHeaders(blockChain, headerRepository, 1, history.DefaultBackFillConfig)
 
 		Expect(err).NotTo(HaveOccurred())
 		headerRepository.AssertCreateOrUpdateHeaderCallCountAndPassedBlockNumbers(1, []int64{2})
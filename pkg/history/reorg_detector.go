@@ -0,0 +1,104 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package history
+
+import (
+	"log"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore"
+)
+
+type ReorgDetector struct {
+	blockchain      core.BlockChain
+	blockRepository datastore.BlockRepository
+	reorgRepository datastore.ReorgRepository
+}
+
+func NewReorgDetector(blockchain core.BlockChain, blockRepository datastore.BlockRepository, reorgRepository datastore.ReorgRepository) ReorgDetector {
+	return ReorgDetector{
+		blockchain:      blockchain,
+		blockRepository: blockRepository,
+		reorgRepository: reorgRepository,
+	}
+}
+
+// DetectReorg checks the stored chain against a canonical block by following parent hashes back
+// from the block until they agree with the stored blocks. Every stored block on the orphaned side
+// of the fork is replaced with its canonical counterpart (dropping its transactions, receipts and
+// logs) and the reorg is recorded. Returns the zero Reorg when the stored chain is consistent.
+func (detector ReorgDetector) DetectReorg(block core.Block) (core.Reorg, error) {
+	var orphanedHashes []string
+	var replacements []core.Block
+	expectedHash := block.Hash
+	canonical := &block
+	for blockNumber := block.Number; blockNumber >= 0; blockNumber-- {
+		storedHash, stored := detector.storedBlockHash(blockNumber)
+		if !stored && blockNumber == block.Number {
+			// nothing to replace at the head, but its parent must still match
+			expectedHash = block.ParentHash
+			canonical = nil
+			continue
+		}
+		if !stored || storedHash == expectedHash {
+			break
+		}
+		if canonical == nil {
+			fetched, err := detector.blockchain.GetBlockByNumber(blockNumber)
+			if err != nil {
+				return core.Reorg{}, err
+			}
+			canonical = &fetched
+		}
+		orphanedHashes = append(orphanedHashes, storedHash)
+		replacements = append(replacements, *canonical)
+		expectedHash = canonical.ParentHash
+		canonical = nil
+	}
+	if len(replacements) == 0 {
+		return core.Reorg{}, nil
+	}
+
+	forkIndex := len(replacements) - 1
+	reorg := core.Reorg{
+		BlockNumber: replacements[forkIndex].Number,
+		Depth:       int64(len(replacements)),
+		OldHash:     orphanedHashes[forkIndex],
+		NewHash:     replacements[forkIndex].Hash,
+	}
+	log.Printf("Reorg of depth %d detected at block %d: %s replaced by %s\n", reorg.Depth, reorg.BlockNumber, reorg.OldHash, reorg.NewHash)
+	for i, j := 0, forkIndex; i < j; i, j = i+1, j-1 {
+		replacements[i], replacements[j] = replacements[j], replacements[i]
+	}
+	reorgId, err := detector.reorgRepository.CreateReorg(reorg, replacements)
+	if err != nil {
+		return core.Reorg{}, err
+	}
+	reorg.Id = reorgId
+	return reorg, nil
+}
+
+func (detector ReorgDetector) storedBlockHash(blockNumber int64) (string, bool) {
+	storedBlock, err := detector.blockRepository.GetBlock(blockNumber)
+	if err != nil {
+		return "", false
+	}
+	return storedBlock.Hash, blockExists(storedBlock.Hash)
+}
+
+func blockExists(hash string) bool {
+	return hash != ""
+}
@@ -0,0 +1,116 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package history_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+	"github.com/vulcanize/vulcanizedb/pkg/history"
+)
+
+var _ = Describe("Reorg detector", func() {
+	var (
+		blockChain      *fakes.MockBlockChain
+		blockRepository *fakes.MockBlockRepository
+		reorgRepository *fakes.MockReorgRepository
+		detector        history.ReorgDetector
+	)
+
+	BeforeEach(func() {
+		blockChain = fakes.NewMockBlockChain()
+		blockRepository = fakes.NewMockBlockRepository()
+		reorgRepository = fakes.NewMockReorgRepository()
+		detector = history.NewReorgDetector(blockChain, blockRepository, reorgRepository)
+		blockRepository.SetGetBlockReturnBlocks([]core.Block{
+			{Number: 1, Hash: "hash1", ParentHash: "hash0"},
+			{Number: 2, Hash: "hash2", ParentHash: "hash1"},
+			{Number: 3, Hash: "old3", ParentHash: "hash2"},
+			{Number: 4, Hash: "old4", ParentHash: "old3"},
+		})
+	})
+
+	It("does nothing when the block extends the stored chain", func() {
+		reorg, err := detector.DetectReorg(core.Block{Number: 5, Hash: "hash5", ParentHash: "old4"})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reorg).To(BeZero())
+		blockRepository.AssertCreateOrUpdateBlockCallCountEquals(0)
+		reorgRepository.AssertCreateReorgNotCalled()
+	})
+
+	It("does nothing when the block matches the stored block", func() {
+		reorg, err := detector.DetectReorg(core.Block{Number: 4, Hash: "old4", ParentHash: "old3"})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reorg).To(BeZero())
+		reorgRepository.AssertCreateReorgNotCalled()
+	})
+
+	It("replaces orphaned blocks back to the common ancestor", func() {
+		blockChain.SetGetBlockByNumberReturnBlocks([]core.Block{
+			{Number: 3, Hash: "new3", ParentHash: "hash2"},
+		})
+		newHead := core.Block{Number: 4, Hash: "new4", ParentHash: "new3"}
+
+		reorg, err := detector.DetectReorg(newHead)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reorg).To(Equal(core.Reorg{Id: 1, BlockNumber: 3, Depth: 2, OldHash: "old3", NewHash: "new3"}))
+		blockRepository.AssertCreateOrUpdateBlockCallCountEquals(0)
+		reorgRepository.AssertCreateReorgReplacedBlockNumbers([]int64{3, 4})
+		reorgRepository.AssertCreateReorgCalledWith([]core.Reorg{{BlockNumber: 3, Depth: 2, OldHash: "old3", NewHash: "new3"}})
+	})
+
+	It("checks the parent of a block that is not yet stored", func() {
+		blockChain.SetGetBlockByNumberReturnBlocks([]core.Block{
+			{Number: 4, Hash: "new4", ParentHash: "hash3"},
+			{Number: 3, Hash: "hash3", ParentHash: "hash2"},
+		})
+		newHead := core.Block{Number: 5, Hash: "hash5", ParentHash: "new4"}
+
+		reorg, err := detector.DetectReorg(newHead)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reorg.BlockNumber).To(Equal(int64(3)))
+		Expect(reorg.Depth).To(Equal(int64(2)))
+		reorgRepository.AssertCreateReorgReplacedBlockNumbers([]int64{3, 4})
+	})
+
+	It("returns an error if a canonical block cannot be retrieved", func() {
+		blockChain.SetGetBlockByNumberErr(fakes.FakeError)
+
+		_, err := detector.DetectReorg(core.Block{Number: 4, Hash: "new4", ParentHash: "new3"})
+
+		Expect(err).To(MatchError(fakes.FakeError))
+		reorgRepository.AssertCreateReorgNotCalled()
+	})
+
+	It("returns an error if the replacements and reorg cannot be persisted", func() {
+		blockChain.SetGetBlockByNumberReturnBlocks([]core.Block{
+			{Number: 3, Hash: "new3", ParentHash: "hash2"},
+		})
+		reorgRepository.SetCreateReorgReturnErr(fakes.FakeError)
+
+		reorg, err := detector.DetectReorg(core.Block{Number: 4, Hash: "new4", ParentHash: "new3"})
+
+		Expect(err).To(MatchError(fakes.FakeError))
+		Expect(reorg).To(BeZero())
+		blockRepository.AssertCreateOrUpdateBlockCallCountEquals(0)
+	})
+})
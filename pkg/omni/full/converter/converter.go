@@ -17,12 +17,6 @@
 package converter
 
 import (
-	"errors"
-	"fmt"
-	"math/big"
-	"strconv"
-
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/vulcanize/vulcanizedb/pkg/core"
@@ -54,47 +48,30 @@ func (c *converter) Update(info *contract.Contract) {
 
 // Convert the given watched event log into a types.Log for the given event
 func (c *converter) Convert(watchedEvent core.WatchedEvent, event types.Event) (*types.Log, error) {
-	contract := bind.NewBoundContract(common.HexToAddress(c.ContractInfo.Address), c.ContractInfo.ParsedAbi, nil, nil, nil)
-	values := make(map[string]interface{})
-
-	for _, field := range event.Fields {
-		var i interface{}
-		values[field.Name] = i
-	}
-
 	log := helpers.ConvertToLog(watchedEvent)
-	err := contract.UnpackLogIntoMap(values, event.Name, log)
+	// Anonymous events are fetched by address alone, so skip logs of any other shape
+	if !helpers.MatchesEvent(c.ContractInfo.ParsedAbi, event, log) {
+		return nil, nil
+	}
+	values, err := helpers.UnpackLog(c.ContractInfo.ParsedAbi, event, log)
 	if err != nil {
+		if event.Anonymous {
+			return nil, nil
+		}
 		return nil, err
 	}
 
 	strValues := make(map[string]string, len(values))
-
-	for fieldName, input := range values {
-		// Postgres cannot handle custom types, resolve to strings
-		switch input.(type) {
-		case *big.Int:
-			b := input.(*big.Int)
-			strValues[fieldName] = b.String()
-		case common.Address:
-			a := input.(common.Address)
-			strValues[fieldName] = a.String()
+	// Postgres cannot handle custom types, resolve to strings in the input format of each field's pg type
+	for _, field := range event.Fields {
+		value := values[field.ColumnName()]
+		strValue, err := field.PgValue(value)
+		if err != nil {
+			return nil, err
+		}
+		strValues[field.ColumnName()] = strValue
+		if a, ok := value.(common.Address); ok {
 			c.ContractInfo.AddTokenHolderAddress(a.String()) // cache address in a list of contract's token holder addresses
-		case common.Hash:
-			h := input.(common.Hash)
-			strValues[fieldName] = h.String()
-		case string:
-			strValues[fieldName] = input.(string)
-		case bool:
-			strValues[fieldName] = strconv.FormatBool(input.(bool))
-		case []byte:
-			b := input.([]byte)
-			strValues[fieldName] = string(b)
-		case byte:
-			b := input.(byte)
-			strValues[fieldName] = string(b)
-		default:
-			return nil, errors.New(fmt.Sprintf("error: unhandled abi type %T", input))
 		}
 	}
 
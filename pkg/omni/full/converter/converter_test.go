@@ -99,6 +99,23 @@ var _ = Describe("Converter", func() {
 			Expect(ok).To(Equal(false))
 		})
 
+		It("Converts anonymous event logs, skipping logs with a different number of topics", func() {
+			con = test_helpers.SetupDaiContract([]string{"LogNote"}, []string{})
+			event, ok := con.Events["LogNote"]
+			Expect(ok).To(Equal(true))
+
+			c := converter.NewConverter(con)
+			log, err := c.Convert(mocks.MockTranferEvent, event)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(log).To(BeNil())
+
+			log, err = c.Convert(mocks.MockLogNoteEvent, event)
+			Expect(err).ToNot(HaveOccurred())
+			guy := common.HexToAddress("0x000000000000000000000000000000000000af21")
+			Expect(log.Values["guy"]).To(Equal(guy.String()))
+			Expect(log.Values["wad"]).To(Equal("1000"))
+		})
+
 		It("Fails with an empty contract", func() {
 			event := con.Events["Transfer"]
 			c := converter.NewConverter(&contract.Contract{})
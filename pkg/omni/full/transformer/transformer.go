@@ -30,6 +30,7 @@ import (
 	"github.com/vulcanize/vulcanizedb/pkg/omni/shared/poller"
 	"github.com/vulcanize/vulcanizedb/pkg/omni/shared/repository"
 	"github.com/vulcanize/vulcanizedb/pkg/omni/shared/types"
+	"log"
 )
 
 // Requires a fully synced vDB and a running eth node (or infura)
@@ -65,6 +66,9 @@ type transformer struct {
 	// before persisting; if empty no filter is applied
 	EventAddrs  map[string][]string
 	MethodAddrs map[string][]string
+
+	// Argument sets to poll methods with, mapped to contract address and method name
+	MethodArgs map[string]map[string]types.MethodArgs
 }
 
 // Transformer takes in config for blockchain, database, and network id
@@ -83,6 +87,7 @@ func NewTransformer(network string, BC core.BlockChain, DB *postgres.DB) *transf
 		ContractRanges:         map[string][2]int64{},
 		EventAddrs:             map[string][]string{},
 		MethodAddrs:            map[string][]string{},
+		MethodArgs:             map[string]map[string]types.MethodArgs{},
 	}
 }
 
@@ -97,6 +102,10 @@ func (t *transformer) Init() error {
 		if err != nil {
 			return err
 		}
+		err = t.Parser.CheckWanted(subset, t.WantedMethods[contractAddr])
+		if err != nil {
+			return err
+		}
 
 		// Get first block for contract and most recent block for the chain
 		firstBlock, err := t.BlockRetriever.RetrieveFirstBlock(contractAddr)
@@ -146,8 +155,12 @@ func (t *transformer) Init() error {
 			Methods:        t.GetSelectMethods(t.WantedMethods[contractAddr]),
 			EventAddrs:     EventAddrs,
 			MethodAddrs:    MethodAddrs,
+			MethodArgs:     t.MethodArgs[contractAddr],
 			TknHolderAddrs: map[string]bool{},
 		}
+		for method, reason := range poller.UnpolledMethods(*info, t.WantedMethods[contractAddr]) {
+			log.Printf("Method %s of contract %s will not be polled: %s\n", method, contractAddr, reason)
+		}
 
 		// Use info to create filters
 		err = info.GenerateFilters()
@@ -241,6 +254,11 @@ func (t *transformer) SetMethodAddrs(contractAddr string, filterSet []string) {
 	t.MethodAddrs[contractAddr] = filterSet
 }
 
+// Used to set the argument sets to poll a contract's methods with
+func (t *transformer) SetMethodArgs(contractAddr string, methodArgs map[string]types.MethodArgs) {
+	t.MethodArgs[contractAddr] = methodArgs
+}
+
 // Used to set the block range to watch for a given address
 func (t *transformer) SetRange(contractAddr string, rng [2]int64) {
 	t.ContractRanges[contractAddr] = rng
@@ -19,6 +19,7 @@ package converter_test
 import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -29,6 +30,8 @@ import (
 	"github.com/vulcanize/vulcanizedb/pkg/omni/shared/helpers/test_helpers/mocks"
 )
 
+var namedAbi = `[{"anonymous":false,"inputs":[{"indexed":true,"name":"name","type":"string"},{"indexed":false,"name":"value","type":"uint256"}],"name":"Named","type":"event"}]`
+
 var _ = Describe("Converter", func() {
 	var con *contract.Contract
 	var wantedEvents = []string{"Transfer", "Mint"}
@@ -102,6 +105,42 @@ var _ = Describe("Converter", func() {
 			Expect(ok).To(Equal(false))
 		})
 
+		It("Converts anonymous event logs, skipping logs with a different number of topics", func() {
+			con = test_helpers.SetupDaiContract([]string{"LogNote"}, []string{})
+			event, ok := con.Events["LogNote"]
+			Expect(ok).To(Equal(true))
+
+			c := converter.NewConverter(con)
+			logs, err := c.Convert([]types.Log{mocks.MockTransferLog1, mocks.MockLogNoteLog}, event, 232)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(logs)).To(Equal(1))
+
+			guy := common.HexToAddress("0x000000000000000000000000000000000000af21")
+			Expect(logs[0].Values["guy"]).To(Equal(guy.String()))
+			Expect(logs[0].Values["wad"]).To(Equal("1000"))
+			Expect(logs[0].LogIndex).To(Equal(uint(11)))
+		})
+
+		It("Records indexed dynamic values as their hash", func() {
+			p := mocks.NewParser(namedAbi)
+			Expect(p.Parse()).To(Succeed())
+			event := p.GetEvents([]string{"Named"})["Named"]
+			Expect(event.Fields[0].ColumnName()).To(Equal("name_hash"))
+			Expect(event.Fields[0].PgType).To(Equal("CHARACTER VARYING(66)"))
+
+			nameHash := crypto.Keccak256Hash([]byte("vulcanize"))
+			log := types.Log{
+				Topics: []common.Hash{common.HexToHash(helpers.GenerateSignature(event.Sig())), nameHash},
+				Data:   common.LeftPadBytes([]byte{42}, 32),
+			}
+			c := converter.NewConverter(&contract.Contract{ParsedAbi: p.ParsedAbi(), EventAddrs: map[string]bool{}})
+			logs, err := c.Convert([]types.Log{log}, event, 232)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(logs)).To(Equal(1))
+			Expect(logs[0].Values).To(Equal(map[string]string{"name_hash": nameHash.Hex(), "value": "42"}))
+		})
+
 		It("Fails with an empty contract", func() {
 			event := con.Events["Transfer"]
 			c := converter.NewConverter(&contract.Contract{})
@@ -30,6 +30,7 @@ type HeaderRepository interface {
 	AddCheckColumn(eventID string) error
 	MarkHeaderChecked(headerID int64, eventID string) error
 	MissingHeaders(startingBlockNumber int64, endingBlockNumber int64, eventID string) ([]core.Header, error)
+	MissingFinalHeaders(startingBlockNumber int64, endingBlockNumber int64, eventID string) ([]core.Header, error)
 	CheckCache(key string) (interface{}, bool)
 }
 
@@ -76,16 +77,31 @@ func (r *headerRepository) MarkHeaderChecked(headerID int64, eventID string) err
 }
 
 func (r *headerRepository) MissingHeaders(startingBlockNumber int64, endingBlockNumber int64, eventID string) ([]core.Header, error) {
+	return r.missingHeaders(startingBlockNumber, endingBlockNumber, eventID, false)
+}
+
+// Returns unchecked headers that have been marked final, leaving recent headers
+// that could still be reorganized out of the chain for a later pass
+func (r *headerRepository) MissingFinalHeaders(startingBlockNumber int64, endingBlockNumber int64, eventID string) ([]core.Header, error) {
+	return r.missingHeaders(startingBlockNumber, endingBlockNumber, eventID, true)
+}
+
+func (r *headerRepository) missingHeaders(startingBlockNumber int64, endingBlockNumber int64, eventID string, finalOnly bool) ([]core.Header, error) {
 	var result []core.Header
 	var query string
 	var err error
 
+	finalFilter := ""
+	if finalOnly {
+		finalFilter = " AND headers.is_final = TRUE"
+	}
+
 	if endingBlockNumber == -1 {
 		query = `SELECT headers.id, headers.block_number, headers.hash FROM headers
 				LEFT JOIN checked_headers on headers.id = header_id
 				WHERE (header_id ISNULL OR ` + eventID + ` IS FALSE)
 				AND headers.block_number >= $1
-				AND headers.eth_node_fingerprint = $2`
+				AND headers.eth_node_fingerprint = $2` + finalFilter
 		err = r.db.Select(&result, query, startingBlockNumber, r.db.Node.ID)
 	} else {
 		query = `SELECT headers.id, headers.block_number, headers.hash FROM headers
@@ -93,7 +109,7 @@ func (r *headerRepository) MissingHeaders(startingBlockNumber int64, endingBlock
 				WHERE (header_id ISNULL OR ` + eventID + ` IS FALSE)
 				AND headers.block_number >= $1
 				AND headers.block_number <= $2
-				AND headers.eth_node_fingerprint = $3`
+				AND headers.eth_node_fingerprint = $3` + finalFilter
 		err = r.db.Select(&result, query, startingBlockNumber, endingBlockNumber, r.db.Node.ID)
 	}
 
@@ -97,6 +97,25 @@ var _ = Describe("Repository", func() {
 		})
 	})
 
+	Describe("MissingFinalHeaders", func() {
+		It("Returns only unchecked headers that have been marked final", func() {
+			headerRepository.CreateOrUpdateHeader(mocks.MockHeader1)
+			headerRepository.CreateOrUpdateHeader(mocks.MockHeader2)
+			headerRepository.CreateOrUpdateHeader(mocks.MockHeader3)
+			err := r.AddCheckColumn(eventID)
+			Expect(err).ToNot(HaveOccurred())
+			err = headerRepository.SetHeadersStatus(mocks.MockHeader3.BlockNumber, 0)
+			Expect(err).ToNot(HaveOccurred())
+
+			missingHeaders, err := r.MissingFinalHeaders(6194630, 6194635, eventID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(missingHeaders)).To(Equal(2))
+			for _, header := range missingHeaders {
+				Expect(header.BlockNumber).To(BeNumerically("<", mocks.MockHeader3.BlockNumber))
+			}
+		})
+	})
+
 	Describe("MarkHeaderChecked", func() {
 		It("Marks the header checked for the given eventID", func() {
 			headerRepository.CreateOrUpdateHeader(mocks.MockHeader1)
@@ -19,6 +19,7 @@ package transformer
 import (
 	"errors"
 	"github.com/vulcanize/vulcanizedb/pkg/omni/shared/helpers"
+	"log"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -54,6 +55,9 @@ type transformer struct {
 	// Ethereum network name; default "" is mainnet
 	Network string
 
+	// Only process headers that have been marked final
+	FinalOnly bool
+
 	// Store contract info as mapping to contract address
 	Contracts map[string]*contract.Contract
 
@@ -69,6 +73,9 @@ type transformer struct {
 	// before persisting; if empty no filter is applied
 	EventAddrs  map[string][]string
 	MethodAddrs map[string][]string
+
+	// Argument sets to poll methods with, mapped to contract address and method name
+	MethodArgs map[string]map[string]types.MethodArgs
 }
 
 // Transformer takes in config for blockchain, database, and network id
@@ -88,6 +95,7 @@ func NewTransformer(network string, bc core.BlockChain, db *postgres.DB) *transf
 		ContractRanges:   map[string][2]int64{},
 		EventAddrs:       map[string][]string{},
 		MethodAddrs:      map[string][]string{},
+		MethodArgs:       map[string]map[string]types.MethodArgs{},
 	}
 }
 
@@ -103,6 +111,10 @@ func (tr *transformer) Init() error {
 		if err != nil {
 			return err
 		}
+		err = tr.Parser.CheckWanted(subset, tr.WantedMethods[contractAddr])
+		if err != nil {
+			return err
+		}
 
 		// Get first block and most recent block number in the header repo
 		firstBlock, err := tr.BlockRetriever.RetrieveFirstBlock()
@@ -149,8 +161,12 @@ func (tr *transformer) Init() error {
 			Methods:        tr.GetSelectMethods(tr.WantedMethods[contractAddr]),
 			EventAddrs:     EventAddrs,
 			MethodAddrs:    MethodAddrs,
+			MethodArgs:     tr.MethodArgs[contractAddr],
 			TknHolderAddrs: map[string]bool{},
 		}
+		for method, reason := range poller.UnpolledMethods(*info, tr.WantedMethods[contractAddr]) {
+			log.Printf("Method %s of contract %s will not be polled: %s\n", method, contractAddr, reason)
+		}
 
 		// Store contract info for execution
 		tr.Contracts[contractAddr] = info
@@ -170,8 +186,12 @@ func (tr *transformer) Execute() error {
 
 		// Iterate through events
 		for _, event := range con.Events {
-			// Filter using the event signature
-			topics := [][]common.Hash{{common.HexToHash(helpers.GenerateSignature(event.Sig()))}}
+			// Filter using the event signature; anonymous events have none,
+			// so all of the contract's logs are fetched and matched by the converter
+			var topics [][]common.Hash
+			if !event.Anonymous {
+				topics = [][]common.Hash{{common.HexToHash(helpers.GenerateSignature(event.Sig()))}}
+			}
 
 			// Generate eventID and use it to create a checked_header column if one does not already exist
 			eventId := strings.ToLower(event.Name + "_" + con.Address)
@@ -180,7 +200,7 @@ func (tr *transformer) Execute() error {
 			}
 
 			// Find unchecked headers for this event
-			missingHeaders, err := tr.MissingHeaders(con.StartingBlock, con.LastBlock, eventId)
+			missingHeaders, err := tr.missingHeaders(con, eventId)
 			if err != nil {
 				return err
 			}
@@ -229,6 +249,14 @@ func (tr *transformer) Execute() error {
 	return nil
 }
 
+// Returns headers not yet checked for the event, only those past the finality depth when FinalOnly is set
+func (tr *transformer) missingHeaders(con *contract.Contract, eventId string) ([]core.Header, error) {
+	if tr.FinalOnly {
+		return tr.MissingFinalHeaders(con.StartingBlock, con.LastBlock, eventId)
+	}
+	return tr.MissingHeaders(con.StartingBlock, con.LastBlock, eventId)
+}
+
 // Used to set which contract addresses and which of their events to watch
 func (tr *transformer) SetEvents(contractAddr string, filterSet []string) {
 	tr.WatchedEvents[contractAddr] = filterSet
@@ -249,6 +277,11 @@ func (tr *transformer) SetMethodAddrs(contractAddr string, filterSet []string) {
 	tr.MethodAddrs[contractAddr] = filterSet
 }
 
+// Used to set the argument sets to poll a contract's methods with
+func (tr *transformer) SetMethodArgs(contractAddr string, methodArgs map[string]types.MethodArgs) {
+	tr.MethodArgs[contractAddr] = methodArgs
+}
+
 // Used to set the block range to watch for a given address
 func (tr *transformer) SetRange(contractAddr string, rng [2]int64) {
 	tr.ContractRanges[contractAddr] = rng
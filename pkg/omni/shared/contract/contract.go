@@ -41,6 +41,7 @@ type Contract struct {
 	Filters        map[string]filters.LogFilter // Map of event filters to their names
 	EventAddrs     map[string]bool              // User-input list of account addresses to watch events for
 	MethodAddrs    map[string]bool              // User-input list of account addresses to poll methods for
+	MethodArgs     map[string]types.MethodArgs  // User-input argument sets to poll methods with, mapped to method names
 	TknHolderAddrs map[string]bool              // List of all contract-associated addresses, populated as events are transformed
 }
 
@@ -49,12 +50,17 @@ func (c *Contract) GenerateFilters() error {
 	c.Filters = map[string]filters.LogFilter{}
 
 	for name, event := range c.Events {
+		// Anonymous events have no signature topic, so their filter matches every log of the contract
+		topics := core.Topics{}
+		if !event.Anonymous {
+			topics[0] = helpers.GenerateSignature(event.Sig()) // move generate signatrue to pkg
+		}
 		c.Filters[name] = filters.LogFilter{
 			Name:      name,
 			FromBlock: c.StartingBlock,
 			ToBlock:   -1,
 			Address:   c.Address,
-			Topics:    core.Topics{helpers.GenerateSignature(event.Sig())}, // move generate signatrue to pkg
+			Topics:    topics,
 		}
 	}
 	// If no filters were generated, throw an error (no point in continuing with this contract)
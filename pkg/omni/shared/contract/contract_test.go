@@ -20,6 +20,8 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"github.com/vulcanize/vulcanizedb/pkg/core"
+	"github.com/vulcanize/vulcanizedb/pkg/omni/shared/constants"
 	"github.com/vulcanize/vulcanizedb/pkg/omni/shared/contract"
 	"github.com/vulcanize/vulcanizedb/pkg/omni/shared/helpers/test_helpers"
 	"github.com/vulcanize/vulcanizedb/pkg/omni/shared/helpers/test_helpers/mocks"
@@ -50,6 +52,17 @@ var _ = Describe("Contract", func() {
 
 		})
 
+		It("Generates filters without a signature topic for anonymous events", func() {
+			info = test_helpers.SetupDaiContract([]string{"LogNote"}, nil)
+			err = info.GenerateFilters()
+			Expect(err).ToNot(HaveOccurred())
+
+			val, ok := info.Filters["LogNote"]
+			Expect(ok).To(Equal(true))
+			Expect(val.Address).To(Equal(constants.DaiContractAddress))
+			Expect(val.Topics).To(Equal(core.Topics{}))
+		})
+
 		It("Fails with an empty contract", func() {
 			info = &contract.Contract{}
 			err = info.GenerateFilters()
@@ -0,0 +1,121 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package helpers
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/vulcanize/vulcanizedb/pkg/omni/shared/types"
+)
+
+var tt256 = new(big.Int).Lsh(big.NewInt(1), 256)
+
+// MatchesEvent returns true if the log could have been emitted as the given event of the contract.
+// Anonymous events have no signature topic, so they are matched on topic count and on not
+// carrying the signature of one of the contract's other events.
+func MatchesEvent(contractAbi abi.ABI, event types.Event, log gethTypes.Log) bool {
+	if len(log.Topics) != event.TopicCount() {
+		return false
+	}
+	if !event.Anonymous {
+		return log.Topics[0] == common.HexToHash(GenerateSignature(event.Sig()))
+	}
+	if len(log.Topics) > 0 {
+		for _, e := range contractAbi.Events {
+			if !e.Anonymous && e.Id() == log.Topics[0] {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// UnpackLog decodes the log's data and topics into a map of the event's field column names to values.
+// Indexed dynamic fields are returned as the common.Hash found in their topic, and tuple arrays
+// and dynamic tuples as values that marshal to JSON.
+func UnpackLog(contractAbi abi.ABI, event types.Event, log gethTypes.Log) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(event.Fields))
+	if len(log.Data) > 0 {
+		err := contractAbi.UnpackIntoMap(values, event.Name, log.Data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, field := range event.Fields {
+		if field.Tuple == nil {
+			continue
+		}
+		value, err := field.Tuple.DecodeJSON(values[field.Name], log.Data)
+		if err != nil {
+			return nil, err
+		}
+		values[field.Name] = value
+	}
+
+	topics := log.Topics
+	if !event.Anonymous {
+		if len(topics) == 0 {
+			return nil, errors.New("log has no event signature topic")
+		}
+		topics = topics[1:]
+	}
+	for _, field := range event.Fields {
+		if !field.Indexed {
+			continue
+		}
+		if len(topics) == 0 {
+			return nil, errors.New("log has fewer topics than indexed event fields")
+		}
+		values[field.ColumnName()] = topicValue(field, topics[0])
+		topics = topics[1:]
+	}
+	if len(topics) > 0 {
+		return nil, errors.New("log has more topics than indexed event fields")
+	}
+
+	return values, nil
+}
+
+func topicValue(field types.Field, topic common.Hash) interface{} {
+	if field.Hashed() {
+		return topic
+	}
+	switch field.Type.T {
+	case abi.BoolTy:
+		return topic[common.HashLength-1] == 1
+	case abi.IntTy:
+		num := new(big.Int).SetBytes(topic[:])
+		if topic[0]&0x80 != 0 {
+			num.Sub(num, tt256)
+		}
+		return num
+	case abi.UintTy:
+		return new(big.Int).SetBytes(topic[:])
+	case abi.AddressTy:
+		return common.BytesToAddress(topic[common.HashLength-common.AddressLength:])
+	case abi.FixedBytesTy:
+		return topic[:field.Type.Size]
+	default:
+		return topic
+	}
+}
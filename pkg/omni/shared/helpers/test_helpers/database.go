@@ -20,6 +20,7 @@ import (
 	"math/rand"
 
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 	. "github.com/onsi/gomega"
 
@@ -74,8 +75,8 @@ func SetupBC() core.BlockChain {
 	rpcClient := client.NewRpcClient(rawRpcClient, infuraIPC)
 	ethClient := ethclient.NewClient(rawRpcClient)
 	blockChainClient := client.NewEthClient(ethClient)
-	node := node.MakeNode(rpcClient)
-	transactionConverter := rpc2.NewRpcTransactionConverter(ethClient)
+	node := node.MakeNodeOfType(rpcClient, core.INFURA)
+	transactionConverter := rpc2.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
 	blockChain := geth.NewBlockChain(blockChainClient, rpcClient, node, transactionConverter)
 
 	return blockChain
@@ -88,8 +89,8 @@ func SetupDBandBC() (*postgres.DB, core.BlockChain) {
 	rpcClient := client.NewRpcClient(rawRpcClient, infuraIPC)
 	ethClient := ethclient.NewClient(rawRpcClient)
 	blockChainClient := client.NewEthClient(ethClient)
-	node := node.MakeNode(rpcClient)
-	transactionConverter := rpc2.NewRpcTransactionConverter(ethClient)
+	node := node.MakeNodeOfType(rpcClient, core.INFURA)
+	transactionConverter := rpc2.NewRpcTransactionConverter(rpcClient, params.MainnetChainConfig)
 	blockChain := geth.NewBlockChain(blockChainClient, rpcClient, node, transactionConverter)
 
 	db, err := postgres.NewDB(config.Database{
@@ -150,6 +151,26 @@ func SetupTusdContract(wantedEvents, wantedMethods []string) *contract.Contract
 	}
 }
 
+func SetupDaiContract(wantedEvents, wantedMethods []string) *contract.Contract {
+	p := mocks.NewParser(constants.DaiAbiString)
+	err := p.Parse()
+	Expect(err).ToNot(HaveOccurred())
+
+	return &contract.Contract{
+		Name:           "Dai",
+		Address:        constants.DaiContractAddress,
+		Abi:            p.Abi(),
+		ParsedAbi:      p.ParsedAbi(),
+		StartingBlock:  4752008,
+		LastBlock:      6507323,
+		Events:         p.GetEvents(wantedEvents),
+		Methods:        p.GetMethods(wantedMethods),
+		EventAddrs:     map[string]bool{},
+		MethodAddrs:    map[string]bool{},
+		TknHolderAddrs: map[string]bool{},
+	}
+}
+
 func TearDown(db *postgres.DB) {
 	tx, err := db.Begin()
 	Expect(err).NotTo(HaveOccurred())
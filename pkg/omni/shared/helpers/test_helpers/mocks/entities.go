@@ -171,3 +171,33 @@ var MockMintLog = types.Log{
 	},
 	Data: hexutil.MustDecode("0x000000000000000000000000c02aaa39b223fe8d0a0e5c4f27ead9083c756cc200000000000000000000000089d24a6b4ccb1b6faa2625fe562bdd9a23260359000000000000000000000000000000000000000000000000392d2e2bda9c00000000000000000000000000000000000000000000000000927f41fa0a4a418000000000000000000000000000000000000000000000000000000000005adcfebe"),
 }
+
+// Anonymous Dai LogNote(bytes4 indexed sig, address indexed guy, bytes32 indexed foo, bytes32 indexed bar, uint256 wad, bytes fax)
+var MockLogNoteLog = types.Log{
+	Index:       11,
+	Address:     common.HexToAddress(constants.DaiContractAddress),
+	BlockNumber: 5488078,
+	TxIndex:     51,
+	TxHash:      common.HexToHash("0x135391a0962a63944e5908e6fedfff90fb4be3e3290a21017861099bad6546af"),
+	Topics: []common.Hash{
+		common.HexToHash("0xa9059cbb00000000000000000000000000000000000000000000000000000000"),
+		common.HexToHash("0x000000000000000000000000000000000000000000000000000000000000af21"),
+		common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000001"),
+		common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000002"),
+	},
+	Data: hexutil.MustDecode("0x00000000000000000000000000000000000000000000000000000000000003e800000000000000000000000000000000000000000000000000000000000000400000000000000000000000000000000000000000000000000000000000000004a9059cbb00000000000000000000000000000000000000000000000000000000"),
+}
+
+var MockLogNoteEvent = core.WatchedEvent{
+	LogID:       2,
+	Name:        "LogNote",
+	BlockNumber: 5488078,
+	Address:     constants.DaiContractAddress,
+	TxHash:      "0x135391a0962a63944e5908e6fedfff90fb4be3e3290a21017861099bad6546af",
+	Index:       11,
+	Topic0:      "0xa9059cbb00000000000000000000000000000000000000000000000000000000",
+	Topic1:      "0x000000000000000000000000000000000000000000000000000000000000af21",
+	Topic2:      "0x0000000000000000000000000000000000000000000000000000000000000001",
+	Topic3:      "0x0000000000000000000000000000000000000000000000000000000000000002",
+	Data:        "0x00000000000000000000000000000000000000000000000000000000000003e800000000000000000000000000000000000000000000000000000000000000400000000000000000000000000000000000000000000000000000000000000004a9059cbb00000000000000000000000000000000000000000000000000000000",
+}
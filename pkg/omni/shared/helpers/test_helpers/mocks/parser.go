@@ -58,27 +58,26 @@ func (p *parser) Parse() error {
 // Returns wanted methods, if they meet the criteria, as map of types.Methods
 // Only returns specified methods
 func (p *parser) GetMethods(wanted []string) map[string]types.Method {
-	addrMethods := map[string]types.Method{}
+	selectMethods := map[string]types.Method{}
 
 	for _, m := range p.parsedAbi.Methods {
-		// Only return methods that have less than 3 inputs, 1 output, and wanted
-		if len(m.Inputs) < 3 && len(m.Outputs) == 1 && stringInSlice(wanted, m.Name) {
-			addrsOnly := true
-			for _, input := range m.Inputs {
-				if input.Type.T != abi.AddressTy {
-					addrsOnly = false
-				}
-			}
-
-			// Only return methods if inputs are all of type address and output is of the accepted types
-			if addrsOnly && wantType(m.Outputs[0]) {
-				method := types.NewMethod(m)
-				addrMethods[method.Name] = method
-			}
+		// Only return methods that are constant, have at least 1 output, and are wanted
+		if !m.Const || len(m.Outputs) == 0 || !stringInSlice(wanted, m.Name) {
+			continue
+		}
+
+		// Only return methods if all inputs can be given as arguments
+		method := types.NewMethod(m)
+		pollable := true
+		for _, arg := range method.Args {
+			pollable = pollable && arg.Arg()
+		}
+		if pollable {
+			selectMethods[method.Name] = method
 		}
 	}
 
-	return addrMethods
+	return selectMethods
 }
 
 // Returns wanted events as map of types.Events
@@ -96,17 +95,6 @@ func (p *parser) GetEvents(wanted []string) map[string]types.Event {
 	return events
 }
 
-func wantType(arg abi.Argument) bool {
-	wanted := []byte{abi.UintTy, abi.IntTy, abi.BoolTy, abi.StringTy, abi.AddressTy, abi.HashTy}
-	for _, ty := range wanted {
-		if arg.Type.T == ty {
-			return true
-		}
-	}
-
-	return false
-}
-
 func stringInSlice(list []string, s string) bool {
 	for _, b := range list {
 		if b == s {
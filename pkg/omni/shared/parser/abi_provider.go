@@ -0,0 +1,191 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/vulcanize/vulcanizedb/pkg/datastore"
+	"github.com/vulcanize/vulcanizedb/pkg/geth"
+	"github.com/vulcanize/vulcanizedb/pkg/omni/shared/constants"
+)
+
+var ErrAbiNotFound = errors.New("ABI not found")
+
+// AbiProvider looks up the ABI of a contract by address
+type AbiProvider interface {
+	GetAbi(contractAddr string) (string, error)
+}
+
+type abiProviders []AbiProvider
+
+// NewAbiProviders returns a provider asking each of providers in turn until one has the ABI
+func NewAbiProviders(providers ...AbiProvider) AbiProvider {
+	return abiProviders(providers)
+}
+
+func (providers abiProviders) GetAbi(contractAddr string) (string, error) {
+	var errs []string
+	for _, provider := range providers {
+		abi, err := provider.GetAbi(contractAddr)
+		if err == nil {
+			return abi, nil
+		}
+		errs = append(errs, err.Error())
+	}
+	return "", fmt.Errorf("%v for %s: %s", ErrAbiNotFound, contractAddr, strings.Join(errs, "; "))
+}
+
+type contractAbiProviders struct {
+	providers map[string]AbiProvider
+	fallback  AbiProvider
+}
+
+// NewContractAbiProviders returns a provider using the provider configured for a contract
+// address, or fallback for contracts without one
+func NewContractAbiProviders(providers map[string]AbiProvider, fallback AbiProvider) AbiProvider {
+	byAddress := make(map[string]AbiProvider, len(providers))
+	for contractAddr, provider := range providers {
+		byAddress[strings.ToLower(contractAddr)] = provider
+	}
+	return contractAbiProviders{providers: byAddress, fallback: fallback}
+}
+
+func (providers contractAbiProviders) GetAbi(contractAddr string) (string, error) {
+	if provider, ok := providers.providers[strings.ToLower(contractAddr)]; ok {
+		return provider.GetAbi(contractAddr)
+	}
+	return providers.fallback.GetAbi(contractAddr)
+}
+
+type staticAbiProvider struct {
+	abi string
+}
+
+// NewStaticAbiProvider returns a provider with the given ABI for every contract
+func NewStaticAbiProvider(abi string) AbiProvider {
+	return staticAbiProvider{abi: abi}
+}
+
+func (provider staticAbiProvider) GetAbi(contractAddr string) (string, error) {
+	return provider.abi, nil
+}
+
+type fileAbiProvider struct {
+	path string
+}
+
+// NewFileAbiProvider returns a provider with the ABI in the file at path for every contract
+func NewFileAbiProvider(path string) AbiProvider {
+	return fileAbiProvider{path: path}
+}
+
+func (provider fileAbiProvider) GetAbi(contractAddr string) (string, error) {
+	return geth.ReadAbiFile(provider.path)
+}
+
+type directoryAbiProvider struct {
+	dir string
+}
+
+// NewDirectoryAbiProvider returns a provider reading ABIs from files in dir named by
+// contract address, e.g. 0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359.json
+func NewDirectoryAbiProvider(dir string) AbiProvider {
+	return directoryAbiProvider{dir: dir}
+}
+
+func (provider directoryAbiProvider) GetAbi(contractAddr string) (string, error) {
+	for _, name := range []string{strings.ToLower(contractAddr), common.HexToAddress(contractAddr).Hex()} {
+		path := filepath.Join(provider.dir, name+".json")
+		if _, err := os.Stat(path); err == nil {
+			return geth.ReadAbiFile(path)
+		}
+	}
+	return "", fmt.Errorf("no ABI file for %s in %s", contractAddr, provider.dir)
+}
+
+type knownAbiProvider struct{}
+
+// NewKnownAbiProvider returns a provider with the ABIs of the contracts in constants.Abis
+func NewKnownAbiProvider() AbiProvider {
+	return knownAbiProvider{}
+}
+
+func (knownAbiProvider) GetAbi(contractAddr string) (string, error) {
+	if abi, ok := constants.Abis[common.HexToAddress(contractAddr)]; ok {
+		return abi, nil
+	}
+	return "", errors.New("ABI not present in lookup table")
+}
+
+type etherscanAbiProvider struct {
+	client *geth.EtherScanAPI
+}
+
+// NewEtherscanAbiProvider returns a provider fetching verified contract ABIs from
+// Etherscan for the given network
+func NewEtherscanAbiProvider(network string) AbiProvider {
+	return etherscanAbiProvider{client: geth.NewEtherScanClient(geth.GenURL(network))}
+}
+
+func (provider etherscanAbiProvider) GetAbi(contractAddr string) (string, error) {
+	abi, err := provider.client.GetAbi(contractAddr)
+	if err != nil {
+		return "", err
+	}
+	// Etherscan responds with a message instead of an ABI for unverified contracts
+	_, _, err = parseAbi(abi)
+	if err != nil {
+		return "", fmt.Errorf("etherscan: %s", abi)
+	}
+	return abi, nil
+}
+
+type cachingAbiProvider struct {
+	cache    datastore.AbiRepository
+	provider AbiProvider
+	source   string
+}
+
+// NewCachingAbiProvider returns a provider storing the ABIs found by provider in cache,
+// recorded as coming from source, and looking them up there first
+func NewCachingAbiProvider(cache datastore.AbiRepository, provider AbiProvider, source string) AbiProvider {
+	return cachingAbiProvider{cache: cache, provider: provider, source: source}
+}
+
+func (provider cachingAbiProvider) GetAbi(contractAddr string) (string, error) {
+	abi, err := provider.cache.GetAbi(contractAddr)
+	if err == nil {
+		return abi, nil
+	}
+	abi, err = provider.provider.GetAbi(contractAddr)
+	if err != nil {
+		return "", err
+	}
+	err = provider.cache.CreateAbi(contractAddr, abi, provider.source)
+	if err != nil {
+		log.Printf("Error caching ABI for %s: %v\n", contractAddr, err)
+	}
+	return abi, nil
+}
@@ -0,0 +1,161 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package parser_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/fakes"
+	"github.com/vulcanize/vulcanizedb/pkg/omni/shared/constants"
+	"github.com/vulcanize/vulcanizedb/pkg/omni/shared/parser"
+)
+
+type fakeAbiProvider struct {
+	abi   string
+	err   error
+	calls int
+}
+
+func (provider *fakeAbiProvider) GetAbi(contractAddr string) (string, error) {
+	provider.calls++
+	return provider.abi, provider.err
+}
+
+var _ = Describe("ABI providers", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "abis")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("reads the ABI from a file for any contract", func() {
+		path := filepath.Join(dir, "contract.json")
+		Expect(ioutil.WriteFile(path, []byte(constants.DaiAbiString), 0644)).To(Succeed())
+
+		abi, err := parser.NewFileAbiProvider(path).GetAbi(constants.TusdContractAddress)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(abi).To(Equal(constants.DaiAbiString))
+	})
+
+	It("reads ABIs from a directory of files named by contract address", func() {
+		path := filepath.Join(dir, "0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359.json")
+		Expect(ioutil.WriteFile(path, []byte(constants.DaiAbiString), 0644)).To(Succeed())
+		provider := parser.NewDirectoryAbiProvider(dir)
+
+		abi, err := provider.GetAbi("0x89D24A6b4CcB1B6fAA2625fE562bDD9a23260359")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(abi).To(Equal(constants.DaiAbiString))
+		_, err = provider.GetAbi(constants.TusdContractAddress)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("looks up known contracts", func() {
+		abi, err := parser.NewKnownAbiProvider().GetAbi(constants.TusdContractAddress)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(abi).To(Equal(constants.TusdAbiString))
+	})
+
+	It("asks each provider in turn", func() {
+		missing := &fakeAbiProvider{err: errors.New("missing")}
+		found := &fakeAbiProvider{abi: constants.DaiAbiString}
+		unused := &fakeAbiProvider{abi: constants.TusdAbiString}
+
+		abi, err := parser.NewAbiProviders(missing, found, unused).GetAbi(constants.DaiContractAddress)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(abi).To(Equal(constants.DaiAbiString))
+		Expect(missing.calls).To(Equal(1))
+		Expect(unused.calls).To(BeZero())
+	})
+
+	It("returns an error when no provider has the ABI", func() {
+		missing := &fakeAbiProvider{err: errors.New("missing")}
+
+		_, err := parser.NewAbiProviders(missing).GetAbi(constants.DaiContractAddress)
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(parser.ErrAbiNotFound.Error()))
+	})
+
+	It("uses the provider configured for a contract address", func() {
+		fallback := &fakeAbiProvider{abi: constants.TusdAbiString}
+		provider := parser.NewContractAbiProviders(map[string]parser.AbiProvider{
+			"0x89D24A6b4CcB1B6fAA2625fE562bDD9a23260359": parser.NewStaticAbiProvider(constants.DaiAbiString),
+		}, fallback)
+
+		abi, err := provider.GetAbi("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(abi).To(Equal(constants.DaiAbiString))
+		Expect(fallback.calls).To(BeZero())
+		abi, err = provider.GetAbi(constants.TusdContractAddress)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(abi).To(Equal(constants.TusdAbiString))
+		Expect(fallback.calls).To(Equal(1))
+	})
+
+	It("caches the ABIs it fetches", func() {
+		cache := fakes.NewMockAbiRepository()
+		fetcher := &fakeAbiProvider{abi: constants.DaiAbiString}
+		provider := parser.NewCachingAbiProvider(cache, fetcher, "etherscan")
+
+		abi, err := provider.GetAbi(constants.DaiContractAddress)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(abi).To(Equal(constants.DaiAbiString))
+		abi, err = provider.GetAbi(constants.DaiContractAddress)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(abi).To(Equal(constants.DaiAbiString))
+		Expect(fetcher.calls).To(Equal(1))
+		cache.AssertCreateAbiCalledWith([]string{constants.DaiContractAddress}, []string{"etherscan"})
+	})
+
+	It("does not cache when the ABI cannot be fetched", func() {
+		cache := fakes.NewMockAbiRepository()
+		fetcher := &fakeAbiProvider{err: errors.New("offline")}
+
+		_, err := parser.NewCachingAbiProvider(cache, fetcher, "etherscan").GetAbi(constants.DaiContractAddress)
+
+		Expect(err).To(HaveOccurred())
+		cache.AssertCreateAbiNotCalled()
+	})
+
+	It("parses the ABI from its provider", func() {
+		p := parser.NewParserWithProvider(&fakeAbiProvider{abi: constants.DaiAbiString})
+
+		err := p.Parse(constants.DaiContractAddress)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.Abi()).To(Equal(constants.DaiAbiString))
+		Expect(p.GetEvents([]string{"Transfer"})).To(HaveKey("Transfer"))
+	})
+})
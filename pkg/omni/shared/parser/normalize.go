@@ -0,0 +1,284 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/vulcanize/vulcanizedb/pkg/omni/shared/types"
+)
+
+// The abi package this tree uses cannot parse fixed point or tuple types, so ABIs are normalized
+// before parsing. Fixed point types are replaced by the integer types they are encoded as, with
+// their decimal places kept aside. Static tuples in event data and method outputs are encoded
+// like their components, so are flattened into one argument per component, while indexed tuples
+// are hashed like indexed bytes. Tuple arrays and dynamic tuples in event data are replaced by
+// placeholders the abi package can skip over and decoded into JSON from the log data. Methods
+// with tuple or fixed point inputs, or tuple arrays or dynamic tuples in their outputs, are dropped.
+
+var fixedPointType = regexp.MustCompile(`^(u?)fixed(([0-9]+)x([0-9]+))?$`)
+
+type normalizedAbi struct {
+	abi             string
+	eventSignatures map[string]string                     // Signatures of events whose inputs were rewritten
+	eventDecimals   map[string][]int                      // Decimal places of each normalized event input, by event name
+	eventTuples     map[string]map[string]types.Component // Tuples decoded from event data, by event and input name
+	outputDecimals  map[string][]int                      // Decimal places of each normalized method output, by method name
+	dropped         []droppedEntry                        // Entries that could not be normalized
+}
+
+type droppedEntry struct {
+	kind   string
+	name   string
+	reason string
+}
+
+func (entry droppedEntry) String() string {
+	return fmt.Sprintf("%s %s: %s", entry.kind, entry.name, entry.reason)
+}
+
+// normalizedArguments holds arguments rewritten to types the abi package can parse
+type normalizedArguments struct {
+	args      []types.Component
+	decimals  []int                      // Decimal places of each argument
+	tuples    map[string]types.Component // Tuple arrays and dynamic tuples replaced by placeholders, by argument name
+	rewritten bool
+}
+
+func normalizeAbi(abiStr string) (normalizedAbi, error) {
+	normalized := normalizedAbi{
+		abi:             abiStr,
+		eventSignatures: map[string]string{},
+		eventDecimals:   map[string][]int{},
+		eventTuples:     map[string]map[string]types.Component{},
+		outputDecimals:  map[string][]int{},
+	}
+	var entries []map[string]json.RawMessage
+	err := json.Unmarshal([]byte(abiStr), &entries)
+	if err != nil {
+		return normalizedAbi{}, err
+	}
+
+	changed := false
+	kept := make([]map[string]json.RawMessage, 0, len(entries))
+	for _, entry := range entries {
+		var entryType, name string
+		var inputs, outputs []types.Component
+		if err = unmarshalFields(entry, &entryType, &name, &inputs, &outputs); err != nil {
+			return normalizedAbi{}, err
+		}
+
+		if entryType == "event" {
+			normalizedInputs := normalizeArguments(inputs)
+			normalized.eventDecimals[name] = normalizedInputs.decimals
+			if len(normalizedInputs.tuples) > 0 {
+				normalized.eventTuples[name] = normalizedInputs.tuples
+			}
+			if normalizedInputs.rewritten {
+				normalized.eventSignatures[name] = signature(name, inputs)
+				entry["inputs"], _ = json.Marshal(normalizedInputs.args)
+				changed = true
+			}
+			kept = append(kept, entry)
+			continue
+		}
+
+		// Methods are called by a selector derived from their input types, so these cannot be rewritten
+		if unsupported := unsupportedInput(inputs); unsupported != "" {
+			normalized.dropped = append(normalized.dropped, droppedEntry{entryTypeOrFunction(entryType), name, unsupported + " input"})
+			changed = true
+			continue
+		}
+		// Method results are unpacked by the abi package alone, so placeholders cannot be decoded
+		normalizedOutputs := normalizeArguments(outputs)
+		if len(normalizedOutputs.tuples) > 0 {
+			normalized.dropped = append(normalized.dropped, droppedEntry{entryTypeOrFunction(entryType), name, "tuple array or dynamic tuple output"})
+			changed = true
+			continue
+		}
+		normalized.outputDecimals[name] = normalizedOutputs.decimals
+		if normalizedOutputs.rewritten {
+			entry["outputs"], _ = json.Marshal(normalizedOutputs.args)
+			changed = true
+		}
+		kept = append(kept, entry)
+	}
+
+	if changed {
+		b, err := json.Marshal(kept)
+		if err != nil {
+			return normalizedAbi{}, err
+		}
+		normalized.abi = string(b)
+	}
+
+	return normalized, nil
+}
+
+func unmarshalFields(entry map[string]json.RawMessage, entryType, name *string, inputs, outputs *[]types.Component) error {
+	fields := []struct {
+		key   string
+		value interface{}
+	}{{"type", entryType}, {"name", name}, {"inputs", inputs}, {"outputs", outputs}}
+	for _, field := range fields {
+		if raw, ok := entry[field.key]; ok {
+			if err := json.Unmarshal(raw, field.value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Entries without a type are functions
+func entryTypeOrFunction(entryType string) string {
+	if entryType == "" {
+		return "function"
+	}
+
+	return entryType
+}
+
+// normalizeArguments rewrites fixed point and tuple arguments to types the abi package can parse
+func normalizeArguments(args []types.Component) normalizedArguments {
+	normalized := normalizedArguments{
+		args:     make([]types.Component, 0, len(args)),
+		decimals: make([]int, 0, len(args)),
+		tuples:   map[string]types.Component{},
+	}
+	for _, arg := range args {
+		base, suffix := splitArrayType(arg.Type)
+		if base != "tuple" {
+			argType, places := integerType(arg.Type)
+			normalized.rewritten = normalized.rewritten || argType != arg.Type
+			normalized.args = append(normalized.args, types.Component{Name: arg.Name, Type: argType, Indexed: arg.Indexed})
+			normalized.decimals = append(normalized.decimals, places)
+			continue
+		}
+
+		normalized.rewritten = true
+		switch {
+		case arg.Indexed:
+			normalized.args = append(normalized.args, types.Component{Name: arg.Name, Type: "bytes", Indexed: true})
+			normalized.decimals = append(normalized.decimals, 0)
+		case suffix != "" || arg.Dynamic():
+			// Dynamic values take up the word holding their offset, and static ones their words in place
+			placeholder := "uint256"
+			if !arg.Dynamic() {
+				placeholder = fmt.Sprintf("bytes32[%d]", arg.Words())
+			}
+			normalized.args = append(normalized.args, types.Component{Name: arg.Name, Type: placeholder})
+			normalized.decimals = append(normalized.decimals, 0)
+			normalized.tuples[arg.Name] = arg
+		default:
+			components := make([]types.Component, len(arg.Components))
+			for i, component := range arg.Components {
+				components[i] = component
+				components[i].Name = componentName(arg.Name, component.Name, i)
+			}
+			flattened := normalizeArguments(components)
+			normalized.args = append(normalized.args, flattened.args...)
+			normalized.decimals = append(normalized.decimals, flattened.decimals...)
+			for name, tuple := range flattened.tuples {
+				normalized.tuples[name] = tuple
+			}
+		}
+	}
+
+	return normalized
+}
+
+func componentName(tupleName, name string, index int) string {
+	if name == "" {
+		name = strconv.Itoa(index)
+	}
+	if tupleName == "" {
+		return name
+	}
+
+	return tupleName + "_" + name
+}
+
+// integerType returns the integer type a fixed point type is encoded as, and its decimal places
+func integerType(argType string) (string, int) {
+	base, suffix := splitArrayType(argType)
+	match := fixedPointType.FindStringSubmatch(base)
+	if match == nil {
+		return argType, 0
+	}
+	bits, places := "128", 18
+	if match[2] != "" {
+		bits = match[3]
+		places, _ = strconv.Atoi(match[4])
+	}
+
+	return match[1] + "int" + bits + suffix, places
+}
+
+// Returns the kind of the first input that cannot be rewritten without changing the method's selector
+func unsupportedInput(args []types.Component) string {
+	for _, arg := range args {
+		base, _ := splitArrayType(arg.Type)
+		if base == "tuple" {
+			return "tuple"
+		}
+		if fixedPointType.MatchString(base) {
+			return "fixed point"
+		}
+	}
+
+	return ""
+}
+
+func splitArrayType(argType string) (string, string) {
+	if i := strings.Index(argType, "["); i >= 0 {
+		return argType[:i], argType[i:]
+	}
+
+	return argType, ""
+}
+
+// signature returns the canonical signature of an event with the given arguments
+func signature(name string, args []types.Component) string {
+	argTypes := make([]string, len(args))
+	for i, arg := range args {
+		argTypes[i] = canonicalType(arg)
+	}
+
+	return fmt.Sprintf("%v(%v)", name, strings.Join(argTypes, ","))
+}
+
+func canonicalType(arg types.Component) string {
+	base, suffix := splitArrayType(arg.Type)
+	switch base {
+	case "tuple":
+		components := make([]string, len(arg.Components))
+		for i, component := range arg.Components {
+			components[i] = canonicalType(component)
+		}
+		return "(" + strings.Join(components, ",") + ")" + suffix
+	case "fixed", "ufixed":
+		return base + "128x18" + suffix
+	default:
+		return arg.Type
+	}
+}
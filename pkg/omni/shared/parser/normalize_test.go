@@ -0,0 +1,152 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package parser_test
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/geth"
+	"github.com/vulcanize/vulcanizedb/pkg/omni/shared/helpers"
+	"github.com/vulcanize/vulcanizedb/pkg/omni/shared/parser"
+	"github.com/vulcanize/vulcanizedb/pkg/omni/shared/types"
+)
+
+var abiV2 = `[
+	{"anonymous":false,"type":"event","name":"Order","inputs":[
+		{"indexed":true,"name":"maker","type":"address"},
+		{"indexed":true,"name":"key","type":"tuple","components":[{"name":"id","type":"uint256"}]},
+		{"indexed":false,"name":"order","type":"tuple","components":[
+			{"name":"price","type":"ufixed128x6"},
+			{"name":"sides","type":"tuple","components":[{"name":"buy","type":"bool"},{"name":"","type":"bytes32"}]}]},
+		{"indexed":false,"name":"rate","type":"fixed"}]},
+	{"anonymous":false,"type":"event","name":"Batch","inputs":[
+		{"indexed":false,"name":"orders","type":"tuple[]","components":[{"name":"id","type":"uint256"}]}]},
+	{"anonymous":false,"type":"event","name":"Named","inputs":[
+		{"indexed":false,"name":"entry","type":"tuple","components":[{"name":"owner","type":"address"},{"name":"label","type":"string"}]},
+		{"indexed":false,"name":"count","type":"uint8"}]},
+	{"anonymous":false,"type":"event","name":"Pairs","inputs":[
+		{"indexed":false,"name":"pairs","type":"tuple[2]","components":[{"name":"a","type":"uint8"},{"name":"","type":"int8"}]},
+		{"indexed":false,"name":"last","type":"bool"}]},
+	{"constant":true,"type":"function","name":"orders","inputs":[],"outputs":[{"name":"","type":"tuple[]","components":[{"name":"id","type":"uint256"}]}],"payable":false,"stateMutability":"view"},
+	{"constant":true,"type":"function","name":"price","inputs":[],"outputs":[{"name":"","type":"ufixed64x2"}],"payable":false,"stateMutability":"view"},
+	{"constant":true,"type":"function","name":"orderOf","inputs":[{"name":"key","type":"tuple","components":[{"name":"id","type":"uint256"}]}],"outputs":[{"name":"","type":"uint256"}],"payable":false,"stateMutability":"view"},
+	{"constant":true,"type":"function","name":"balanceOf","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}],"payable":false,"stateMutability":"view"}
+]`
+
+var _ = Describe("ABI normalization", func() {
+	var p parser.Parser
+
+	BeforeEach(func() {
+		p = parser.NewParserWithProvider(parser.NewStaticAbiProvider(abiV2))
+		Expect(p.Parse("0x1234")).To(Succeed())
+	})
+
+	It("flattens static tuples and hashes indexed tuples in events", func() {
+		event, ok := p.GetEvents([]string{})["Order"]
+		Expect(ok).To(BeTrue())
+
+		var columns, pgTypes []string
+		for _, field := range event.Fields {
+			columns = append(columns, field.ColumnName())
+			pgTypes = append(pgTypes, field.PgType)
+		}
+		Expect(columns).To(Equal([]string{"maker", "key_hash", "order_price", "order_sides_buy", "order_sides_1", "rate"}))
+		Expect(pgTypes).To(Equal([]string{"CHARACTER VARYING(66)", "CHARACTER VARYING(66)", "NUMERIC", "BOOLEAN", "BYTEA", "NUMERIC"}))
+		Expect(event.Fields[2].Decimals).To(Equal(6))
+		Expect(event.Fields[5].Decimals).To(Equal(18))
+	})
+
+	It("keeps the original event signature", func() {
+		event := p.GetEvents([]string{"Order"})["Order"]
+
+		Expect(event.Sig()).To(Equal("Order(address,(uint256),(ufixed128x6,(bool,bytes32)),fixed128x18)"))
+		Expect(helpers.GenerateSignature(event.Sig())).NotTo(Equal(p.ParsedAbi().Events["Order"].Id().Hex()))
+	})
+
+	It("reads fixed point method outputs as integers with decimal places", func() {
+		method, ok := p.GetMethods([]string{})["price"]
+		Expect(ok).To(BeTrue())
+
+		Expect(method.Return[0].Type.String()).To(Equal("uint64"))
+		Expect(method.Return[0].Decimals).To(Equal(2))
+	})
+
+	It("decodes tuple arrays and dynamic tuples in event data into JSON", func() {
+		events := p.GetEvents([]string{})
+		Expect(events["Batch"].Fields[0].PgType).To(Equal("JSONB"))
+		Expect(events["Named"].Sig()).To(Equal("Named((address,string),uint8)"))
+
+		Expect(unpack(p.ParsedAbi(), events["Batch"], word(0x20), word(2), word(1), word(2))).To(Equal([]string{`[{"id":1},{"id":2}]`}))
+		Expect(unpack(p.ParsedAbi(), events["Named"], word(0x40), word(5), word(1), word(0x40), word(2), []byte("hi"))).
+			To(Equal([]string{`{"label":"hi","owner":"0x0000000000000000000000000000000000000001"}`, "5"}))
+		Expect(unpack(p.ParsedAbi(), events["Pairs"], word(1), word(2), word(3), negativeWord(4), word(1))).
+			To(Equal([]string{`[{"1":2,"a":1},{"1":-4,"a":3}]`, "true"}))
+	})
+
+	It("drops methods that cannot be normalized", func() {
+		methods := p.GetMethods([]string{})
+		_, ok := methods["orderOf"]
+		Expect(ok).To(BeFalse())
+		_, ok = methods["orders"]
+		Expect(ok).To(BeFalse())
+		_, ok = methods["balanceOf"]
+		Expect(ok).To(BeTrue())
+	})
+
+	It("fails for wanted methods that were dropped", func() {
+		Expect(p.CheckWanted([]string{"Batch"}, []string{"balanceOf"})).To(Succeed())
+		Expect(p.CheckWanted(nil, []string{"orders"})).To(MatchError(ContainSubstring("orders")))
+		Expect(p.CheckWanted(nil, []string{"orderOf"})).To(MatchError(ContainSubstring("tuple input")))
+	})
+
+	It("returns an ABI the abi package can parse", func() {
+		_, err := geth.ParseAbi(p.Abi())
+
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
+
+func word(n int64) []byte {
+	return common.LeftPadBytes(big.NewInt(n).Bytes(), 32)
+}
+
+func negativeWord(n int64) []byte {
+	return new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(n)).Bytes()
+}
+
+// unpack decodes a log of the event with the given data and formats its values as postgres input
+func unpack(contractAbi abi.ABI, event types.Event, data ...[]byte) []string {
+	var logData []byte
+	for _, d := range data {
+		logData = append(logData, common.RightPadBytes(d, (len(d)+31)/32*32)...)
+	}
+	log := gethTypes.Log{Topics: []common.Hash{common.HexToHash(helpers.GenerateSignature(event.Sig()))}, Data: logData}
+	values, err := helpers.UnpackLog(contractAbi, event, log)
+	Expect(err).NotTo(HaveOccurred())
+	formatted := make([]string, len(event.Fields))
+	for i, field := range event.Fields {
+		formatted[i], err = field.PgValue(values[field.ColumnName()])
+		Expect(err).NotTo(HaveOccurred())
+	}
+	return formatted
+}
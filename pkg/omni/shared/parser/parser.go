@@ -17,20 +17,21 @@
 package parser
 
 import (
-	"errors"
+	"fmt"
+	"log"
+	"sort"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
-	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/vulcanize/vulcanizedb/pkg/geth"
-	"github.com/vulcanize/vulcanizedb/pkg/omni/shared/constants"
 	"github.com/vulcanize/vulcanizedb/pkg/omni/shared/types"
 )
 
 // Parser is used to fetch and parse contract ABIs
-// It is dependent on etherscan's api
+// ABIs are looked up with an AbiProvider
 type Parser interface {
 	Parse(contractAddr string) error
+	CheckWanted(events, methods []string) error
 	Abi() string
 	ParsedAbi() abi.ABI
 	GetMethods(wanted []string) map[string]types.Method
@@ -39,16 +40,20 @@ type Parser interface {
 }
 
 type parser struct {
-	client    *geth.EtherScanAPI
-	abi       string
-	parsedAbi abi.ABI
+	provider   AbiProvider
+	abi        string
+	parsedAbi  abi.ABI
+	normalized normalizedAbi
 }
 
+// NewParser looks up ABIs in constants.Abis, then on etherscan for the given network
 func NewParser(network string) *parser {
-	url := geth.GenURL(network)
+	return NewParserWithProvider(NewAbiProviders(NewKnownAbiProvider(), NewEtherscanAbiProvider(network)))
+}
 
+func NewParserWithProvider(provider AbiProvider) *parser {
 	return &parser{
-		client: geth.NewEtherScanClient(url),
+		provider: provider,
 	}
 }
 
@@ -63,59 +68,122 @@ func (p *parser) ParsedAbi() abi.ABI {
 // Retrieves and parses the abi string
 // for the given contract address
 func (p *parser) Parse(contractAddr string) error {
-	knownAbi, err := p.lookUp(contractAddr)
-	if err == nil {
-		p.abi = knownAbi
-		p.parsedAbi, err = geth.ParseAbi(knownAbi)
+	abiStr, err := p.provider.GetAbi(contractAddr)
+	if err != nil {
 		return err
 	}
-
-	abiStr, err := p.client.GetAbi(contractAddr)
+	p.normalized, p.parsedAbi, err = parseAbi(abiStr)
 	if err != nil {
 		return err
 	}
-	p.abi = abiStr
-	p.parsedAbi, err = geth.ParseAbi(abiStr)
+	for _, dropped := range p.normalized.dropped {
+		log.Printf("Ignoring %s in ABI for %s\n", dropped, contractAddr)
+	}
+	p.abi = p.normalized.abi
+
+	return nil
+}
+
+// CheckWanted returns an error if an event or method wanted by name was dropped from the ABI
+// because it cannot be decoded, or if two wanted anonymous events cannot be told apart
+func (p *parser) CheckWanted(events, methods []string) error {
+	for _, dropped := range p.normalized.dropped {
+		wanted := methods
+		if dropped.kind == "event" {
+			wanted = events
+		}
+		if stringInSlice(wanted, dropped.name) {
+			return fmt.Errorf("cannot watch %s", dropped)
+		}
+	}
+
+	return checkAnonymousEvents(p.GetEvents(events))
+}
+
+// checkAnonymousEvents rejects anonymous events with the same number of topics, since
+// their logs are matched on the topic count alone and would be stored under both
+func checkAnonymousEvents(events map[string]types.Event) error {
+	var names []string
+	for name, event := range events {
+		if event.Anonymous {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	byTopicCount := map[int]string{}
+	for _, name := range names {
+		count := events[name].TopicCount()
+		if other, ok := byTopicCount[count]; ok {
+			return fmt.Errorf("cannot watch both anonymous events %s and %s, their logs have the same number of topics", other, name)
+		}
+		byTopicCount[count] = name
+	}
+
+	return nil
+}
+
+// parseAbi normalizes the abi string into one the abi package can parse before parsing it
+func parseAbi(abiStr string) (normalizedAbi, abi.ABI, error) {
+	normalized, err := normalizeAbi(abiStr)
+	if err != nil {
+		return normalizedAbi{}, abi.ABI{}, geth.ErrInvalidAbiFile
+	}
+	parsedAbi, err := geth.ParseAbi(normalized.abi)
+
+	return normalized, parsedAbi, err
+}
+
+func (p *parser) newEvent(e abi.Event) types.Event {
+	event := types.NewEvent(e)
+	event.Signature = p.normalized.eventSignatures[e.Name]
+	for i, places := range p.normalized.eventDecimals[e.Name] {
+		event.Fields[i].Decimals = places
+	}
+	for i, field := range event.Fields {
+		if tuple, ok := p.normalized.eventTuples[e.Name][field.Name]; ok {
+			event.Fields[i].Tuple = &tuple
+			event.Fields[i].PgType = "JSONB"
+		}
+	}
 
-	return err
+	return event
 }
 
-func (p *parser) lookUp(contractAddr string) (string, error) {
-	if v, ok := constants.Abis[common.HexToAddress(contractAddr)]; ok {
-		return v, nil
+func (p *parser) newMethod(m abi.Method) types.Method {
+	method := types.NewMethod(m)
+	for i, places := range p.normalized.outputDecimals[m.Name] {
+		method.Return[i].Decimals = places
 	}
 
-	return "", errors.New("ABI not present in lookup tabe")
+	return method
 }
 
 // Returns wanted methods, if they meet the criteria, as map of types.Methods
+// Methods must be constant, return at least one value and take only arguments that can be configured
 // Empty wanted array => all methods that fit are returned
 // Nil wanted array => no events are returned
 func (p *parser) GetSelectMethods(wanted []string) map[string]types.Method {
-	addrMethods := map[string]types.Method{}
+	selectMethods := map[string]types.Method{}
 	if wanted == nil {
 		return nil
 	}
 
 	for _, m := range p.parsedAbi.Methods {
-		// Only return methods that have less than 3 inputs, 1 output
-		if len(m.Inputs) < 3 && len(m.Outputs) == 1 && (len(wanted) == 0 || stringInSlice(wanted, m.Name)) {
-			addrsOnly := true
-			for _, input := range m.Inputs {
-				if input.Type.T != abi.AddressTy {
-					addrsOnly = false
-				}
-			}
-
-			// Only return methods if inputs are all of type address and output is of the accepted types
-			if addrsOnly && wantType(m.Outputs[0]) {
-				method := types.NewMethod(m)
-				addrMethods[method.Name] = method
-			}
+		if !m.Const || len(m.Outputs) == 0 || (len(wanted) > 0 && !stringInSlice(wanted, m.Name)) {
+			continue
+		}
+
+		method := p.newMethod(m)
+		pollable := true
+		for _, arg := range method.Args {
+			pollable = pollable && arg.Arg()
+		}
+		if pollable {
+			selectMethods[method.Name] = method
 		}
 	}
 
-	return addrMethods
+	return selectMethods
 }
 
 // Returns wanted methods as map of types.Methods
@@ -130,7 +198,7 @@ func (p *parser) GetMethods(wanted []string) map[string]types.Method {
 	length := len(wanted)
 	for _, m := range p.parsedAbi.Methods {
 		if length == 0 || stringInSlice(wanted, m.Name) {
-			methods[m.Name] = types.NewMethod(m)
+			methods[m.Name] = p.newMethod(m)
 		}
 	}
 
@@ -149,24 +217,13 @@ func (p *parser) GetEvents(wanted []string) map[string]types.Event {
 	length := len(wanted)
 	for _, e := range p.parsedAbi.Events {
 		if length == 0 || stringInSlice(wanted, e.Name) {
-			events[e.Name] = types.NewEvent(e)
+			events[e.Name] = p.newEvent(e)
 		}
 	}
 
 	return events
 }
 
-func wantType(arg abi.Argument) bool {
-	wanted := []byte{abi.UintTy, abi.IntTy, abi.BoolTy, abi.StringTy, abi.AddressTy, abi.HashTy, abi.BytesTy, abi.FixedBytesTy, abi.FixedPointTy}
-	for _, ty := range wanted {
-		if arg.Type.T == ty {
-			return true
-		}
-	}
-
-	return false
-}
-
 func stringInSlice(list []string, s string) bool {
 	for _, b := range list {
 		if b == s {
@@ -64,6 +64,28 @@ var _ = Describe("Parser", func() {
 		})
 	})
 
+	Describe("CheckWanted", func() {
+		anonymousAbi := `[
+	{"anonymous":true,"type":"event","name":"Deposit","inputs":[{"indexed":true,"name":"who","type":"address"},{"indexed":false,"name":"amount","type":"uint256"}]},
+	{"anonymous":true,"type":"event","name":"Withdrawal","inputs":[{"indexed":true,"name":"who","type":"address"},{"indexed":false,"name":"amount","type":"uint256"}]},
+	{"anonymous":true,"type":"event","name":"Sweep","inputs":[{"indexed":false,"name":"amount","type":"uint256"}]}
+]`
+
+		BeforeEach(func() {
+			p = parser.NewParserWithProvider(parser.NewStaticAbiProvider(anonymousAbi))
+			Expect(p.Parse("0x1234")).To(Succeed())
+		})
+
+		It("rejects anonymous events that cannot be told apart", func() {
+			Expect(p.CheckWanted([]string{}, nil)).To(MatchError(ContainSubstring("Deposit and Withdrawal")))
+			Expect(p.CheckWanted([]string{"Deposit", "Withdrawal"}, nil)).NotTo(Succeed())
+		})
+
+		It("accepts anonymous events with different topic counts", func() {
+			Expect(p.CheckWanted([]string{"Deposit", "Sweep"}, nil)).To(Succeed())
+		})
+	})
+
 	Describe("Parse", func() {
 		It("Fetches and parses abi from etherscan using contract address", func() {
 			contractAddr := "0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359" // dai contract address
@@ -112,7 +134,7 @@ var _ = Describe("Parser", func() {
 			Expect(abiTy).To(Equal(abi.UintTy))
 
 			pgTy = e.Fields[2].PgType
-			Expect(pgTy).To(Equal("DECIMAL"))
+			Expect(pgTy).To(Equal("NUMERIC"))
 
 			_, ok = events["Approval"]
 			Expect(ok).To(Equal(false))
@@ -140,7 +162,7 @@ var _ = Describe("Parser", func() {
 			Expect(abiTy).To(Equal(abi.UintTy))
 
 			pgTy = m.Return[0].PgType
-			Expect(pgTy).To(Equal("DECIMAL"))
+			Expect(pgTy).To(Equal("NUMERIC"))
 
 			_, ok = selectMethods["totalSupply"]
 			Expect(ok).To(Equal(false))
@@ -173,8 +195,8 @@ var _ = Describe("Parser", func() {
 		})
 	})
 
-	Describe("GetAddrMethods", func() {
-		It("Parses and returns only methods whose inputs, if any, are all addresses", func() {
+	Describe("GetSelectMethods", func() {
+		It("Parses and returns only methods whose inputs can be given as arguments", func() {
 			contractAddr := "0xDdE2D979e8d39BB8416eAfcFC1758f3CaB2C9C72"
 			err = p.Parse(contractAddr)
 			Expect(err).ToNot(HaveOccurred())
@@ -199,12 +221,12 @@ var _ = Describe("Parser", func() {
 			Expect(ok).To(Equal(true))
 
 			_, ok = selectMethods["supportsInterface"]
-			Expect(ok).To(Equal(false))
+			Expect(ok).To(Equal(true))
 			_, ok = methods["supportsInterface"]
 			Expect(ok).To(Equal(true))
 
 			_, ok = selectMethods["getApproved"]
-			Expect(ok).To(Equal(false))
+			Expect(ok).To(Equal(true))
 			_, ok = methods["getApproved"]
 			Expect(ok).To(Equal(true))
 
@@ -213,5 +235,23 @@ var _ = Describe("Parser", func() {
 			_, ok = methods["name"]
 			Expect(ok).To(Equal(false))
 		})
+
+		It("Returns constant methods with several arguments and return values", func() {
+			p = parser.NewParserWithProvider(parser.NewStaticAbiProvider(`[
+				{"constant":true,"inputs":[],"name":"getReserves","outputs":[{"name":"_reserve0","type":"uint112"},{"name":"_reserve1","type":"uint112"},{"name":"_blockTimestampLast","type":"uint32"}],"type":"function"},
+				{"constant":true,"inputs":[{"name":"owner","type":"address"},{"name":"id","type":"uint256"},{"name":"key","type":"bytes32"}],"name":"lookup","outputs":[{"name":"","type":"bool"}],"type":"function"},
+				{"constant":true,"inputs":[{"name":"ids","type":"uint256[]"}],"name":"totals","outputs":[{"name":"","type":"uint256"}],"type":"function"},
+				{"constant":false,"inputs":[{"name":"to","type":"address"}],"name":"mint","outputs":[{"name":"","type":"bool"}],"type":"function"},
+				{"constant":true,"inputs":[],"name":"ping","outputs":[],"type":"function"}
+			]`))
+			err = p.Parse("0x0000000000000000000000000000000000000001")
+			Expect(err).ToNot(HaveOccurred())
+
+			selectMethods := p.GetSelectMethods([]string{})
+
+			Expect(selectMethods).To(HaveLen(2))
+			Expect(selectMethods["getReserves"].Return).To(HaveLen(3))
+			Expect(selectMethods["lookup"].Args).To(HaveLen(3))
+		})
 	})
 })
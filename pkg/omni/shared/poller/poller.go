@@ -19,10 +19,10 @@ package poller
 import (
 	"errors"
 	"fmt"
-	"math/big"
-	"strconv"
+	"reflect"
+	"strings"
 
-	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 
 	"github.com/vulcanize/vulcanizedb/pkg/core"
 	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
@@ -50,102 +50,170 @@ func NewPoller(blockChain core.BlockChain, db *postgres.DB, mode types.Mode) *po
 	}
 }
 
-// Used to call contract's methods found in abi using list of contract-related addresses
+// Used to call contract's methods found in abi with their configured or event derived argument sets,
+// falling back to the list of contract-related addresses for methods taking only addresses
 func (p *poller) PollContract(con contract.Contract) error {
 	p.contract = con
 	// Iterate over each of the contracts methods
 	for _, m := range con.Methods {
-		switch len(m.Args) {
-		case 0:
-			if err := p.pollNoArg(m); err != nil {
-				return err
-			}
-		case 1:
-			if err := p.pollSingleArg(m); err != nil {
-				return err
-			}
-		case 2:
-			if err := p.pollDoubleArg(m); err != nil {
-				return err
-			}
-		default:
-			return errors.New("poller error: too many arguments to handle")
-
+		argSets, err := p.argSets(m)
+		if err != nil {
+			return err
+		}
+		if err := p.pollMethod(m, argSets); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
-// Poll methods that take no arguments
-func (p *poller) pollNoArg(m types.Method) error {
-	result := types.Result{
-		Method: m,
-		Inputs: nil,
-		PgType: m.Return[0].PgType,
+// Collects the argument sets to poll a method with, without duplicates
+func (p *poller) argSets(m types.Method) ([][]string, error) {
+	if len(m.Args) == 0 {
+		return [][]string{{}}, nil
 	}
 
-	for i := p.contract.StartingBlock; i <= p.contract.LastBlock; i++ {
-		var out interface{}
-		err := p.bc.FetchContractData(p.contract.Abi, p.contract.Address, m.Name, nil, &out, i)
+	methodArgs, configured := p.contract.MethodArgs[m.Name]
+	if !configured {
+		return p.tokenHolderArgs(m), nil
+	}
+
+	sets := methodArgs.Sets
+	if len(methodArgs.EventFields) > 0 {
+		eventName, columns, err := p.eventColumns(m, methodArgs.EventFields)
 		if err != nil {
-			return errors.New(fmt.Sprintf("poller error calling 0 argument method\r\nblock: %d, method: %s, contract: %s\r\nerr: %v", i, m.Name, p.contract.Address, err))
+			return nil, err
 		}
-
-		strOut, err := stringify(out)
+		eventSets, err := p.EventArgs(p.contract.Address, eventName, columns)
 		if err != nil {
-			return err
+			return nil, errors.New(fmt.Sprintf("poller error retrieving %s arguments from %s logs\r\ncontract: %s\r\nerr: %v", m.Name, eventName, p.contract.Address, err))
 		}
+		sets = append(sets, eventSets...)
+	}
 
-		result.Output = strOut
-		result.Block = i
-
-		// Persist result immediately
-		err = p.PersistResult(result, p.contract.Address, p.contract.Name)
-		if err != nil {
-			return errors.New(fmt.Sprintf("poller error persisting 0 argument method result\r\nblock: %d, method: %s, contract: %s\r\nerr: %v", i, m.Name, p.contract.Address, err))
+	seen := map[string]bool{}
+	unique := make([][]string, 0, len(sets))
+	for _, set := range sets {
+		if len(set) != len(m.Args) {
+			return nil, errors.New(fmt.Sprintf("poller error: method %s takes %d arguments, given %d", m.Name, len(m.Args), len(set)))
+		}
+		key := strings.Join(set, ",")
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, set)
 		}
 	}
 
-	return nil
+	return unique, nil
 }
 
-// Use token holder address to poll methods that take 1 address argument (e.g. balanceOf)
-func (p *poller) pollSingleArg(m types.Method) error {
-	result := types.Result{
-		Method: m,
-		Inputs: make([]interface{}, 1),
-		PgType: m.Return[0].PgType,
+// Methods taking a single address (e.g. balanceOf) are polled with each token holder; other methods,
+// including those taking several addresses (e.g. allowance), are only polled with configured arguments
+func (p *poller) tokenHolderArgs(m types.Method) [][]string {
+	if !tokenHolderMethod(m) {
+		return nil
 	}
 
+	sets := make([][]string, 0, len(p.contract.TknHolderAddrs))
 	for addr := range p.contract.TknHolderAddrs {
-		for i := p.contract.StartingBlock; i <= p.contract.LastBlock; i++ {
-			hashArgs := []common.Address{common.HexToAddress(addr)}
-			in := make([]interface{}, len(hashArgs))
-			strIn := make([]interface{}, len(hashArgs))
-			for i, s := range hashArgs {
-				in[i] = s
-				strIn[i] = s.String()
+		sets = append(sets, []string{addr})
+	}
+
+	return sets
+}
+
+func tokenHolderMethod(m types.Method) bool {
+	return len(m.Args) == 1 && m.Args[0].Type.T == abi.AddressTy
+}
+
+// UnpolledMethods explains why each of the wanted methods will never be polled for the contract,
+// mapped to method names, so that it can be reported at startup rather than silently producing no rows
+func UnpolledMethods(con contract.Contract, wanted []string) map[string]string {
+	unpolled := map[string]string{}
+	for _, name := range wanted {
+		m, selected := con.Methods[name]
+		_, configured := con.MethodArgs[name]
+		switch {
+		case !selected:
+			unpolled[name] = "it is not a constant method with return values and configurable arguments in the abi"
+		case len(m.Args) > 0 && !configured && !tokenHolderMethod(m):
+			unpolled[name] = "it takes arguments other than a single address and has no methodArgs configured"
+		}
+	}
+	return unpolled
+}
+
+// Resolves Event.field names to the columns of a watched event's table
+func (p *poller) eventColumns(m types.Method, eventFields []string) (string, []string, error) {
+	var eventName string
+	columns := make([]string, len(eventFields))
+	for i, eventField := range eventFields {
+		parts := strings.SplitN(eventField, ".", 2)
+		event, ok := p.contract.Events[parts[0]]
+		if !ok || len(parts) != 2 {
+			return "", nil, errors.New(fmt.Sprintf("poller error: %s arguments from %s, which is not a watched event", m.Name, eventField))
+		}
+		eventName = event.Name
+		found := false
+		for _, field := range event.Fields {
+			if field.Name == parts[1] && !field.Hashed() {
+				columns[i] = strings.ToLower(field.ColumnName())
+				found = true
 			}
+		}
+		if !found {
+			return "", nil, errors.New(fmt.Sprintf("poller error: %s arguments from %s, which is not a logged value", m.Name, eventField))
+		}
+	}
 
-			var out interface{}
-			err := p.bc.FetchContractData(p.contract.Abi, p.contract.Address, m.Name, in, &out, i)
+	return eventName, columns, nil
+}
+
+// Poll a method at each block with each argument set
+func (p *poller) pollMethod(m types.Method, argSets [][]string) error {
+	for _, set := range argSets {
+		in := make([]interface{}, len(set))
+		strIn := make([]interface{}, len(set))
+		for i, s := range set {
+			arg, err := m.Args[i].ArgValue(s)
 			if err != nil {
-				return errors.New(fmt.Sprintf("poller error calling 1 argument method\r\nblock: %d, method: %s, contract: %s\r\nerr: %v", i, m.Name, p.contract.Address, err))
+				return errors.New(fmt.Sprintf("poller error parsing %s arguments\r\ncontract: %s\r\nerr: %v", m.Name, p.contract.Address, err))
 			}
-
-			strOut, err := stringify(out)
+			in[i] = arg
+			strIn[i], err = m.Args[i].PgValue(arg)
 			if err != nil {
 				return err
 			}
+		}
+		if len(in) == 0 {
+			in = nil
+		}
+
+		result := types.Result{
+			Method: m,
+			Inputs: strIn,
+		}
+
+		for i := p.contract.StartingBlock; i <= p.contract.LastBlock; i++ {
+			outputs, err := p.call(m, in, i)
+			if err != nil {
+				return errors.New(fmt.Sprintf("poller error calling %d argument method\r\nblock: %d, method: %s, contract: %s\r\nerr: %v", len(m.Args), i, m.Name, p.contract.Address, err))
+			}
 
-			result.Output = strOut
+			result.Outputs = make([]interface{}, len(outputs))
+			for j, out := range outputs {
+				result.Outputs[j], err = m.Return[j].PgValue(out)
+				if err != nil {
+					return err
+				}
+			}
 			result.Block = i
-			result.Inputs = strIn
 
+			// Persist result immediately
 			err = p.PersistResult(result, p.contract.Address, p.contract.Name)
 			if err != nil {
-				return errors.New(fmt.Sprintf("poller error persisting 1 argument method result\r\nblock: %d, method: %s, contract: %s\r\nerr: %v", i, m.Name, p.contract.Address, err))
+				return errors.New(fmt.Sprintf("poller error persisting %d argument method result\r\nblock: %d, method: %s, contract: %s\r\nerr: %v", len(m.Args), i, m.Name, p.contract.Address, err))
 			}
 		}
 	}
@@ -153,77 +221,31 @@ func (p *poller) pollSingleArg(m types.Method) error {
 	return nil
 }
 
-// Use token holder address to poll methods that take 2 address arguments (e.g. allowance)
-func (p *poller) pollDoubleArg(m types.Method) error {
-	// For a large block range and address list this will take a really, really long time- maybe we should only do 1 arg methods
-	result := types.Result{
-		Method: m,
-		Inputs: make([]interface{}, 2),
-		PgType: m.Return[0].PgType,
+// Calls the method, returning its decoded return values
+func (p *poller) call(m types.Method, in []interface{}, blockNumber int64) ([]interface{}, error) {
+	if len(m.Return) == 1 {
+		var out interface{}
+		err := p.bc.FetchContractData(p.contract.Abi, p.contract.Address, m.Name, in, &out, blockNumber)
+		return []interface{}{out}, err
 	}
 
-	for addr1 := range p.contract.TknHolderAddrs {
-		for addr2 := range p.contract.TknHolderAddrs {
-			for i := p.contract.StartingBlock; i <= p.contract.LastBlock; i++ {
-				hashArgs := []common.Address{common.HexToAddress(addr1), common.HexToAddress(addr2)}
-				in := make([]interface{}, len(hashArgs))
-				strIn := make([]interface{}, len(hashArgs))
-				for i, s := range hashArgs {
-					in[i] = s
-					strIn[i] = s.String()
-				}
-
-				var out interface{}
-				err := p.bc.FetchContractData(p.contract.Abi, p.contract.Address, m.Name, in, &out, i)
-				if err != nil {
-					return errors.New(fmt.Sprintf("poller error calling 2 argument method\r\nblock: %d, method: %s, contract: %s\r\nerr: %v", i, m.Name, p.contract.Address, err))
-				}
-
-				strOut, err := stringify(out)
-				if err != nil {
-					return err
-				}
-
-				result.Output = strOut
-				result.Block = i
-				result.Inputs = strIn
-
-				err = p.PersistResult(result, p.contract.Address, p.contract.Name)
-				if err != nil {
-					return errors.New(fmt.Sprintf("poller error persisting 2 argument method result\r\nblock: %d, method: %s, contract: %s\r\nerr: %v", i, m.Name, p.contract.Address, err))
-				}
-			}
-
-		}
+	// Multiple return values are unpacked into pointers to values of their go types
+	out := make([]interface{}, len(m.Return))
+	for i, ret := range m.Return {
+		out[i] = reflect.New(ret.Type.Type).Interface()
+	}
+	err := p.bc.FetchContractData(p.contract.Abi, p.contract.Address, m.Name, in, &out, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	for i := range out {
+		out[i] = reflect.ValueOf(out[i]).Elem().Interface()
 	}
 
-	return nil
+	return out, nil
 }
 
 // This is just a wrapper around the poller blockchain's FetchContractData method
 func (p *poller) FetchContractData(contractAbi, contractAddress, method string, methodArgs []interface{}, result interface{}, blockNumber int64) error {
 	return p.bc.FetchContractData(contractAbi, contractAddress, method, methodArgs, result, blockNumber)
 }
-
-func stringify(input interface{}) (string, error) {
-	switch input.(type) {
-	case *big.Int:
-		var b *big.Int
-		b = input.(*big.Int)
-		return b.String(), nil
-	case common.Address:
-		var a common.Address
-		a = input.(common.Address)
-		return a.String(), nil
-	case common.Hash:
-		var h common.Hash
-		h = input.(common.Hash)
-		return h.String(), nil
-	case string:
-		return input.(string), nil
-	case bool:
-		return strconv.FormatBool(input.(bool)), nil
-	default:
-		return "", errors.New("error: unhandled return type")
-	}
-}
@@ -18,6 +18,7 @@ package poller_test
 
 import (
 	"fmt"
+	"strings"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -85,6 +86,35 @@ var _ = Describe("Poller", func() {
 				Expect(scanStruct.TokenName).To(Equal("TrueUSD"))
 			})
 
+			It("Polls methods with their configured argument sets instead of token holder addresses", func() {
+				con = test_helpers.SetupTusdContract(nil, []string{"balanceOf", "allowance"})
+				con.StartingBlock = 6707322
+				con.LastBlock = 6707322
+				con.TknHolderAddrs = map[string]bool{
+					"0x3f5CE5FBFe3E9af3971dD833D26bA9b5C936f0bE": true,
+				}
+				con.MethodArgs = map[string]types.MethodArgs{
+					"balanceOf": {Sets: [][]string{{"0xfE9e8709d3215310075d67E3ed32A380CCf451C8"}}},
+				}
+
+				err := p.PollContract(*con)
+				Expect(err).ToNot(HaveOccurred())
+
+				scanStruct := test_helpers.BalanceOf{}
+				err = db.QueryRowx(fmt.Sprintf("SELECT * FROM full_%s.balanceof_method WHERE who_ = '0xfE9e8709d3215310075d67E3ed32A380CCf451C8' AND block = '6707322'", constants.TusdContractAddress)).StructScan(&scanStruct)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(scanStruct.Balance).To(Equal("66386309548896882859581786"))
+
+				err = db.QueryRowx(fmt.Sprintf("SELECT * FROM full_%s.balanceof_method WHERE who_ = '0x3f5CE5FBFe3E9af3971dD833D26bA9b5C936f0bE' AND block = '6707322'", constants.TusdContractAddress)).StructScan(&scanStruct)
+				Expect(err).To(HaveOccurred())
+
+				// Methods taking several addresses are not polled for every pair of token holders
+				var count int
+				err = db.Get(&count, fmt.Sprintf("SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = 'full_%s' AND table_name = 'allowance_method'", strings.ToLower(constants.TusdContractAddress)))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(count).To(Equal(0))
+			})
+
 			It("Does not poll and persist any methods if none are specified", func() {
 				con = test_helpers.SetupTusdContract(nil, nil)
 				Expect(con.Abi).To(Equal(constants.TusdAbiString))
@@ -115,3 +145,22 @@ var _ = Describe("Poller", func() {
 		})
 	})
 })
+
+var _ = Describe("UnpolledMethods", func() {
+	It("Reports wanted methods that will never be polled", func() {
+		con := test_helpers.SetupTusdContract(nil, []string{"balanceOf", "allowance"})
+
+		unpolled := poller.UnpolledMethods(*con, []string{"balanceOf", "allowance", "notAMethod"})
+		Expect(len(unpolled)).To(Equal(2))
+		Expect(unpolled).To(HaveKey("allowance"))
+		Expect(unpolled).To(HaveKey("notAMethod"))
+	})
+
+	It("Does not report methods with configured arguments", func() {
+		con := test_helpers.SetupTusdContract(nil, []string{"allowance"})
+		con.MethodArgs = map[string]types.MethodArgs{"allowance": {}}
+
+		unpolled := poller.UnpolledMethods(*con, []string{"allowance"})
+		Expect(unpolled).To(BeEmpty())
+	})
+})
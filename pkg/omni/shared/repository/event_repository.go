@@ -232,14 +232,16 @@ func (r *eventRepository) newEventTable(tableID string, event types.Event) error
 
 		// Iterate over event fields, using their name and pgType to grow the string
 		for _, field := range event.Fields {
-			pgStr = pgStr + fmt.Sprintf(" %s_ %s NOT NULL,", strings.ToLower(field.Name), field.PgType)
+			pgStr = pgStr + fmt.Sprintf(" %s_ %s NOT NULL,", strings.ToLower(field.ColumnName()), field.PgType)
 		}
-		pgStr = pgStr + " CONSTRAINT log_index_fk FOREIGN KEY (vulcanize_log_id) REFERENCES logs (id) ON DELETE CASCADE)"
+		// logs is partitioned and cannot be referenced by a foreign key, so rows are
+		// instead removed by a trigger on logs for every table registered in log_references
+		pgStr = strings.TrimSuffix(pgStr, ",") + ")"
 	case types.LightSync:
 		pgStr = pgStr + "(id SERIAL, header_id INTEGER NOT NULL REFERENCES headers (id) ON DELETE CASCADE, token_name CHARACTER VARYING(66) NOT NULL, raw_log JSONB, log_idx INTEGER NOT NULL, tx_idx INTEGER NOT NULL,"
 
 		for _, field := range event.Fields {
-			pgStr = pgStr + fmt.Sprintf(" %s_ %s NOT NULL,", strings.ToLower(field.Name), field.PgType)
+			pgStr = pgStr + fmt.Sprintf(" %s_ %s NOT NULL,", strings.ToLower(field.ColumnName()), field.PgType)
 		}
 		pgStr = pgStr + " UNIQUE (header_id, tx_idx, log_idx))"
 	default:
@@ -247,6 +249,12 @@ func (r *eventRepository) newEventTable(tableID string, event types.Event) error
 	}
 
 	_, err = r.db.Exec(pgStr)
+	if err != nil || r.mode != types.FullSync {
+		return err
+	}
+
+	_, err = r.db.Exec(`INSERT INTO log_references (table_name, column_name) VALUES ($1, 'vulcanize_log_id')
+		ON CONFLICT (table_name) DO NOTHING`, tableID)
 
 	return err
 }
@@ -31,6 +31,7 @@ const methodCacheSize = 1000
 
 type MethodRepository interface {
 	PersistResult(method types.Result, contractAddr, contractName string) error
+	EventArgs(contractAddr, eventName string, columns []string) ([][]string, error)
 	CreateMethodTable(contractAddr string, method types.Result) (bool, error)
 	CreateContractSchema(contractAddr string) (bool, error)
 	CheckSchemaCache(key string) (interface{}, bool)
@@ -59,7 +60,7 @@ func (r *methodRepository) PersistResult(method types.Result, contractAddr, cont
 	if len(method.Args) != len(method.Inputs) {
 		return errors.New("error: given number of inputs does not match number of method arguments")
 	}
-	if len(method.Return) != 1 {
+	if len(method.Return) == 0 || len(method.Return) != len(method.Outputs) {
 		return errors.New("error: given number of outputs does not match number of method return values")
 	}
 
@@ -84,23 +85,26 @@ func (r *methodRepository) persistResult(method types.Result, contractAddr, cont
 	ml := len(method.Args)
 
 	// Preallocate slice of needed size and proceed to pack variables into it in same order they appear in string
-	data := make([]interface{}, 0, 3+ml)
+	data := make([]interface{}, 0, 2+ml+len(method.Return))
 	data = append(data,
 		contractName,
 		method.Block)
 
-	// Iterate over method args and return value, adding names
+	// Iterate over method args and return values, adding names
 	// to the string and pushing values to the slice
 	for i, arg := range method.Args {
-		pgStr = pgStr + fmt.Sprintf(", %s_", strings.ToLower(arg.Name)) // Add underscore after to avoid any collisions with reserved pg words
+		pgStr = pgStr + fmt.Sprintf(", %s", argColumn(i, arg))
 		data = append(data, method.Inputs[i])
 	}
-	pgStr = pgStr + ", returned) VALUES ($1, $2"
-	data = append(data, method.Output)
+	for i := range method.Return {
+		pgStr = pgStr + fmt.Sprintf(", %s", returnColumn(i, method.Method))
+		data = append(data, method.Outputs[i])
+	}
+	pgStr = pgStr + ") VALUES ($1, $2"
 
-	// For each input entry we created we add its postgres command variable to the string
-	for i := 0; i <= ml; i++ {
-		pgStr = pgStr + fmt.Sprintf(", $%d", i+3)
+	// For each input and output entry we created we add its postgres command variable to the string
+	for i := 3; i <= len(data); i++ {
+		pgStr = pgStr + fmt.Sprintf(", $%d", i)
 	}
 	pgStr = pgStr + ")"
 
@@ -145,17 +149,76 @@ func (r *methodRepository) newMethodTable(tableID string, method types.Result) e
 	pgStr = pgStr + "(id SERIAL, token_name CHARACTER VARYING(66) NOT NULL, block INTEGER NOT NULL,"
 
 	// Iterate over method inputs and outputs, using their name and pgType to grow the string
-	for _, arg := range method.Args {
-		pgStr = pgStr + fmt.Sprintf(" %s_ %s NOT NULL,", strings.ToLower(arg.Name), arg.PgType)
+	for i, arg := range method.Args {
+		pgStr = pgStr + fmt.Sprintf(" %s %s NOT NULL,", argColumn(i, arg), arg.PgType)
 	}
-
-	pgStr = pgStr + fmt.Sprintf(" returned %s NOT NULL)", method.Return[0].PgType)
+	for i, ret := range method.Return {
+		pgStr = pgStr + fmt.Sprintf(" %s %s NOT NULL,", returnColumn(i, method.Method), ret.PgType)
+	}
+	pgStr = strings.TrimSuffix(pgStr, ",") + ")"
 
 	_, err := r.DB.Exec(pgStr)
 
 	return err
 }
 
+// Column for a method argument; underscore added after to avoid any collisions with reserved pg words
+func argColumn(i int, arg types.Field) string {
+	if arg.Name == "" {
+		return fmt.Sprintf("arg%d_", i)
+	}
+	return strings.ToLower(arg.Name) + "_"
+}
+
+// Column for a method return value; a single value is stored as returned, several by name or position
+func returnColumn(i int, method types.Method) string {
+	if len(method.Return) == 1 {
+		return "returned"
+	}
+	if method.Return[i].Name == "" {
+		return fmt.Sprintf("returned_%d", i)
+	}
+	return "returned_" + strings.ToLower(method.Return[i].Name)
+}
+
+// Returns the distinct values of the given columns of an event's table, as text, to poll methods with
+// No argument sets are returned until the event's table has been created
+func (r *methodRepository) EventArgs(contractAddr, eventName string, columns []string) ([][]string, error) {
+	var exists bool
+	pgStr := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM information_schema.tables WHERE table_schema = '%s_%s' AND table_name = '%s_event')", r.mode.String(), strings.ToLower(contractAddr), strings.ToLower(eventName))
+	err := r.DB.Get(&exists, pgStr)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	selects := make([]string, len(columns))
+	for i, column := range columns {
+		selects[i] = fmt.Sprintf("%s_::TEXT", column)
+	}
+	pgStr = fmt.Sprintf("SELECT DISTINCT %s FROM %s_%s.%s_event", strings.Join(selects, ", "), r.mode.String(), strings.ToLower(contractAddr), strings.ToLower(eventName))
+	rows, err := r.DB.Query(pgStr)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sets [][]string
+	for rows.Next() {
+		set := make([]string, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range set {
+			dest[i] = &set[i]
+		}
+		err = rows.Scan(dest...)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, set)
+	}
+
+	return sets, rows.Err()
+}
+
 // Checks if a table already exists for the given contract and event
 func (r *methodRepository) checkForTable(contractAddr string, methodName string) (bool, error) {
 	pgStr := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM information_schema.tables WHERE table_schema = '%s_%s' AND table_name = '%s_method')", r.mode.String(), strings.ToLower(contractAddr), strings.ToLower(methodName))
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -43,14 +44,11 @@ var _ = Describe("Repository", func() {
 		con = test_helpers.SetupTusdContract([]string{}, []string{"balanceOf"})
 		method = con.Methods["balanceOf"]
 		mockResult = types.Result{
-			Method: method,
-			PgType: method.Return[0].PgType,
-			Inputs: make([]interface{}, 1),
-			Output: new(interface{}),
-			Block:  6707323,
+			Method:  method,
+			Inputs:  []interface{}{"0xfE9e8709d3215310075d67E3ed32A380CCf451C8"},
+			Outputs: []interface{}{"66386309548896882859581786"},
+			Block:   6707323,
 		}
-		mockResult.Inputs[0] = "0xfE9e8709d3215310075d67E3ed32A380CCf451C8"
-		mockResult.Output = "66386309548896882859581786"
 		db, _ = test_helpers.SetupDBandBC()
 		dataStore = repository.NewMethodRepository(db, types.FullSync)
 	})
@@ -141,10 +139,72 @@ var _ = Describe("Repository", func() {
 				Expect(scanStruct).To(Equal(expectedLog))
 			})
 
+			It("Persists each of several return values in its own column", func() {
+				uint112, err := abi.NewType("uint112")
+				Expect(err).ToNot(HaveOccurred())
+				uint32, err := abi.NewType("uint32")
+				Expect(err).ToNot(HaveOccurred())
+				reserves := types.Result{
+					Method: types.Method{
+						Name:  "getReserves",
+						Const: true,
+						Return: []types.Field{
+							types.NewField(abi.Argument{Name: "_reserve0", Type: uint112}),
+							types.NewField(abi.Argument{Name: "_reserve1", Type: uint112}),
+							types.NewField(abi.Argument{Type: uint32}),
+						},
+					},
+					Outputs: []interface{}{"1000", "2000", "1539000000"},
+					Block:   6707323,
+				}
+
+				err = dataStore.PersistResult(reserves, con.Address, con.Name)
+				Expect(err).ToNot(HaveOccurred())
+
+				var returned struct {
+					Reserve0  string `db:"returned__reserve0"`
+					Reserve1  string `db:"returned__reserve1"`
+					Timestamp string `db:"returned_2"`
+				}
+				err = db.Get(&returned, fmt.Sprintf("SELECT returned__reserve0, returned__reserve1, returned_2 FROM full_%s.getreserves_method", strings.ToLower(con.Address)))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(returned.Reserve0).To(Equal("1000"))
+				Expect(returned.Reserve1).To(Equal("2000"))
+				Expect(returned.Timestamp).To(Equal("1539000000"))
+			})
+
 			It("Fails with empty result", func() {
 				err = dataStore.PersistResult(types.Result{}, con.Address, con.Name)
 				Expect(err).To(HaveOccurred())
 			})
+
+			It("Fails when the number of outputs does not match the method's return values", func() {
+				mockResult.Outputs = nil
+				err = dataStore.PersistResult(mockResult, con.Address, con.Name)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Describe("EventArgs", func() {
+			It("Returns no argument sets before the event's table exists", func() {
+				sets, err := dataStore.EventArgs(con.Address, "Transfer", []string{"to"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sets).To(BeEmpty())
+			})
+
+			It("Returns the distinct values of the event's columns", func() {
+				_, err = dataStore.CreateContractSchema(con.Address)
+				Expect(err).ToNot(HaveOccurred())
+				table := fmt.Sprintf("full_%s.transfer_event", strings.ToLower(con.Address))
+				_, err = db.Exec(fmt.Sprintf("CREATE TABLE %s (from_ CHARACTER VARYING(66), to_ CHARACTER VARYING(66), value_ NUMERIC)", table))
+				Expect(err).ToNot(HaveOccurred())
+				_, err = db.Exec(fmt.Sprintf("INSERT INTO %s VALUES ('0x1', '0x2', 10), ('0x1', '0x2', 20), ('0x2', '0x3', 10)", table))
+				Expect(err).ToNot(HaveOccurred())
+
+				sets, err := dataStore.EventArgs(con.Address, "Transfer", []string{"from", "to"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sets).To(ConsistOf([]string{"0x1", "0x2"}, []string{"0x2", "0x3"}))
+			})
 		})
 	})
 
@@ -27,11 +27,14 @@ type Event struct {
 	Name      string
 	Anonymous bool
 	Fields    []Field
+	Signature string // Signature from the original ABI, when its tuple or fixed point inputs were rewritten into Fields
 }
 
 type Field struct {
-	abi.Argument        // Name, Type, Indexed
-	PgType       string // Holds type used when committing data held in this field to postgres
+	abi.Argument            // Name, Type, Indexed
+	PgType       string     // Holds type used when committing data held in this field to postgres
+	Decimals     int        // Decimal places of a fixed point field, whose values are decoded as integers
+	Tuple        *Component // Tuple array or dynamic tuple in place of which Type is decoded, see Component.DecodeJSON
 }
 
 // Struct to hold instance of an event log data
@@ -53,29 +56,7 @@ type Log struct {
 func NewEvent(e abi.Event) Event {
 	fields := make([]Field, len(e.Inputs))
 	for i, input := range e.Inputs {
-		fields[i] = Field{}
-		fields[i].Name = input.Name
-		fields[i].Type = input.Type
-		fields[i].Indexed = input.Indexed
-		// Fill in pg type based on abi type
-		switch fields[i].Type.T {
-		case abi.StringTy, abi.HashTy, abi.AddressTy:
-			fields[i].PgType = "CHARACTER VARYING(66)"
-		case abi.IntTy, abi.UintTy:
-			fields[i].PgType = "DECIMAL"
-		case abi.BoolTy:
-			fields[i].PgType = "BOOLEAN"
-		case abi.BytesTy, abi.FixedBytesTy:
-			fields[i].PgType = "BYTEA"
-		case abi.ArrayTy:
-			fields[i].PgType = "TEXT[]"
-		case abi.FixedPointTy:
-			fields[i].PgType = "MONEY" // use shopspring/decimal for fixed point numbers in go and money type in postgres?
-		case abi.FunctionTy:
-			fields[i].PgType = "TEXT"
-		default:
-			fields[i].PgType = "TEXT"
-		}
+		fields[i] = NewField(input)
 	}
 
 	return Event{
@@ -86,6 +67,9 @@ func NewEvent(e abi.Event) Event {
 }
 
 func (e Event) Sig() string {
+	if e.Signature != "" {
+		return e.Signature
+	}
 	types := make([]string, len(e.Fields))
 
 	for i, input := range e.Fields {
@@ -94,3 +78,41 @@ func (e Event) Sig() string {
 
 	return fmt.Sprintf("%v(%v)", e.Name, strings.Join(types, ","))
 }
+
+// Number of topics a log of this event has, including the signature topic if it is not anonymous
+func (e Event) TopicCount() int {
+	count := 0
+	if !e.Anonymous {
+		count++
+	}
+	for _, field := range e.Fields {
+		if field.Indexed {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Returns true if the field is indexed and of a dynamic type, in which case
+// its log topic holds the keccak hash of the value rather than the value itself
+func (f Field) Hashed() bool {
+	if !f.Indexed {
+		return false
+	}
+	switch f.Type.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy:
+		return true
+	default:
+		return false
+	}
+}
+
+// Name of the log value and postgres column holding this field; hashed fields are suffixed with _hash
+func (f Field) ColumnName() string {
+	if f.Hashed() {
+		return f.Name + "_hash"
+	}
+
+	return f.Name
+}
@@ -33,64 +33,28 @@ type Method struct {
 // Struct to hold instance of result from method call with given inputs and block
 type Result struct {
 	Method
-	Inputs []interface{} // Will only use addresses
-	Output interface{}
-	PgType string // Holds output pg type
-	Block  int64
+	Inputs  []interface{} // Argument values, formatted for the args' pg types
+	Outputs []interface{} // Return values, formatted for the returns' pg types
+	Block   int64
+}
+
+// Argument sets to poll a method with
+// Methods taking only addresses are polled with the contract's token holder addresses when neither is given
+type MethodArgs struct {
+	Sets        [][]string // Argument values, as accepted by Field.ArgValue
+	EventFields []string   // Fields of one event, as Event.field, whose values in each of its logs form an argument set
 }
 
 // Unpack abi.Method into our custom Method struct
 func NewMethod(m abi.Method) Method {
 	inputs := make([]Field, len(m.Inputs))
 	for i, input := range m.Inputs {
-		inputs[i] = Field{}
-		inputs[i].Name = input.Name
-		inputs[i].Type = input.Type
-		inputs[i].Indexed = input.Indexed
-		switch inputs[i].Type.T {
-		case abi.StringTy, abi.HashTy, abi.AddressTy:
-			inputs[i].PgType = "CHARACTER VARYING(66)"
-		case abi.IntTy, abi.UintTy:
-			inputs[i].PgType = "DECIMAL"
-		case abi.BoolTy:
-			inputs[i].PgType = "BOOLEAN"
-		case abi.BytesTy, abi.FixedBytesTy:
-			inputs[i].PgType = "BYTEA"
-		case abi.ArrayTy:
-			inputs[i].PgType = "TEXT[]"
-		case abi.FixedPointTy:
-			inputs[i].PgType = "MONEY" // use shopspring/decimal for fixed point numbers in go and money type in postgres?
-		case abi.FunctionTy:
-			inputs[i].PgType = "TEXT"
-		default:
-			inputs[i].PgType = "TEXT"
-		}
+		inputs[i] = NewField(input)
 	}
 
 	outputs := make([]Field, len(m.Outputs))
 	for i, output := range m.Outputs {
-		outputs[i] = Field{}
-		outputs[i].Name = output.Name
-		outputs[i].Type = output.Type
-		outputs[i].Indexed = output.Indexed
-		switch outputs[i].Type.T {
-		case abi.StringTy, abi.HashTy, abi.AddressTy:
-			outputs[i].PgType = "CHARACTER VARYING(66)"
-		case abi.IntTy, abi.UintTy:
-			outputs[i].PgType = "DECIMAL"
-		case abi.BoolTy:
-			outputs[i].PgType = "BOOLEAN"
-		case abi.BytesTy, abi.FixedBytesTy:
-			outputs[i].PgType = "BYTEA"
-		case abi.ArrayTy:
-			outputs[i].PgType = "TEXT[]"
-		case abi.FixedPointTy:
-			outputs[i].PgType = "MONEY" // use shopspring/decimal for fixed point numbers in go and money type in postgres?
-		case abi.FunctionTy:
-			outputs[i].PgType = "TEXT"
-		default:
-			outputs[i].PgType = "TEXT"
-		}
+		outputs[i] = NewField(output)
 	}
 
 	return Method{
@@ -0,0 +1,302 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var pgArrayEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// Unpack abi.Argument into a Field, with the postgres type its values are stored as
+func NewField(arg abi.Argument) Field {
+	field := Field{Argument: arg}
+	field.PgType = PgType(arg.Type)
+	// Indexed dynamic values are only logged as the keccak hash of their encoding
+	if field.Hashed() {
+		field.PgType = "CHARACTER VARYING(66)"
+	}
+
+	return field
+}
+
+// PgType returns the postgres type used to store values of the given abi type
+// Numbers are stored exactly as NUMERIC, arrays as postgres arrays of their element
+// type, and nested arrays, which postgres arrays cannot hold, as JSONB
+func PgType(t abi.Type) string {
+	switch t.T {
+	case abi.IntTy, abi.UintTy, abi.FixedPointTy:
+		return "NUMERIC"
+	case abi.BoolTy:
+		return "BOOLEAN"
+	case abi.AddressTy, abi.HashTy:
+		return "CHARACTER VARYING(66)"
+	case abi.StringTy:
+		return "TEXT"
+	case abi.BytesTy, abi.FixedBytesTy, abi.FunctionTy:
+		return "BYTEA"
+	case abi.SliceTy, abi.ArrayTy:
+		if t.Elem.T == abi.SliceTy || t.Elem.T == abi.ArrayTy {
+			return "JSONB"
+		}
+		return PgType(*t.Elem) + "[]"
+	default:
+		return "JSONB"
+	}
+}
+
+// PgValue formats a value decoded for this field as postgres input for the field's PgType
+func (f Field) PgValue(value interface{}) (string, error) {
+	if f.Hashed() {
+		hash, ok := value.(common.Hash)
+		if !ok {
+			return "", fmt.Errorf("expected hash for indexed %s %s, got %T", f.Type, f.Name, value)
+		}
+		return hash.Hex(), nil
+	}
+	if f.Tuple != nil {
+		j, err := json.Marshal(value)
+		return string(j), err
+	}
+
+	return pgValue(f.Type, f.Decimals, value)
+}
+
+func pgValue(t abi.Type, decimals int, value interface{}) (string, error) {
+	if value == nil {
+		return "", fmt.Errorf("no value for abi type %s", t)
+	}
+	if PgType(t) == "JSONB" {
+		j, err := json.Marshal(jsonValue(reflect.ValueOf(value), &t, decimals))
+		return string(j), err
+	}
+	if t.T != abi.SliceTy && t.T != abi.ArrayTy {
+		return scalarValue(value, decimals)
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return "", fmt.Errorf("expected array for abi type %s, got %T", t, value)
+	}
+	elements := make([]string, v.Len())
+	for i := range elements {
+		element, err := pgValue(*t.Elem, decimals, v.Index(i).Interface())
+		if err != nil {
+			return "", err
+		}
+		elements[i] = `"` + pgArrayEscaper.Replace(element) + `"`
+	}
+
+	return "{" + strings.Join(elements, ",") + "}", nil
+}
+
+func scalarValue(value interface{}, decimals int) (string, error) {
+	switch v := value.(type) {
+	case *big.Int:
+		return formatDecimal(v, decimals), nil
+	case common.Address:
+		return v.Hex(), nil
+	case common.Hash:
+		return v.Hex(), nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	}
+
+	// Integers of up to 64 bits are decoded as the matching go type, and fixed size bytes as byte arrays
+	if n, ok := integer(reflect.ValueOf(value)); ok {
+		return formatDecimal(n, decimals), nil
+	}
+	if b, ok := byteSlice(reflect.ValueOf(value)); ok {
+		return `\x` + hex.EncodeToString(b), nil
+	}
+
+	return "", fmt.Errorf("unhandled abi type %T", value)
+}
+
+// jsonValue converts nested arrays to values that marshal to JSON, keeping numbers exact
+// The abi type is nil for elements without one, whose type is then inferred from their go type
+func jsonValue(v reflect.Value, t *abi.Type, decimals int) interface{} {
+	if v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		if _, ok := v.Interface().(*big.Int); !ok {
+			return jsonValue(v.Elem(), t, decimals)
+		}
+	}
+	if t != nil && (t.T == abi.SliceTy || t.T == abi.ArrayTy) {
+		elements := make([]interface{}, v.Len())
+		for i := range elements {
+			elements[i] = jsonValue(v.Index(i), t.Elem, decimals)
+		}
+		return elements
+	}
+
+	switch value := v.Interface().(type) {
+	case *big.Int:
+		return json.Number(formatDecimal(value, decimals))
+	case common.Address:
+		return value.Hex()
+	case common.Hash:
+		return value.Hex()
+	case string, bool:
+		return value
+	}
+	if n, ok := integer(v); ok {
+		return json.Number(formatDecimal(n, decimals))
+	}
+	if b, ok := byteSlice(v); ok {
+		return hexutil.Encode(b)
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		elements := make([]interface{}, v.Len())
+		for i := range elements {
+			elements[i] = jsonValue(v.Index(i), nil, decimals)
+		}
+		return elements
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+func integer(v reflect.Value) (*big.Int, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(v.Uint()), true
+	default:
+		return nil, false
+	}
+}
+
+func byteSlice(v reflect.Value) ([]byte, bool) {
+	if (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) || v.Type().Elem().Kind() != reflect.Uint8 {
+		return nil, false
+	}
+	b := make([]byte, v.Len())
+	reflect.Copy(reflect.ValueOf(b), v)
+
+	return b, true
+}
+
+// formatDecimal formats n scaled down by 10^decimals, exactly
+func formatDecimal(n *big.Int, decimals int) string {
+	if decimals <= 0 {
+		return n.String()
+	}
+	digits := new(big.Int).Abs(n).String()
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+	point := len(digits) - decimals
+	sign := ""
+	if n.Sign() < 0 {
+		sign = "-"
+	}
+
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// ArgValue parses a method argument for this field from a string, accepting decimal or hex
+// numbers and hex encoded bytes, prefixed with 0x or postgres' \x
+func (f Field) ArgValue(s string) (interface{}, error) {
+	switch f.Type.T {
+	case abi.AddressTy:
+		if !common.IsHexAddress(s) {
+			return nil, fmt.Errorf("invalid address %q for %s", s, f.Name)
+		}
+		return common.HexToAddress(s), nil
+	case abi.HashTy:
+		return common.HexToHash(s), nil
+	case abi.StringTy:
+		return s, nil
+	case abi.BoolTy:
+		return strconv.ParseBool(s)
+	case abi.IntTy, abi.UintTy:
+		return f.intArg(s)
+	case abi.BytesTy, abi.FixedBytesTy:
+		b, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(s, "0x"), `\x`))
+		if err != nil {
+			return nil, fmt.Errorf("invalid bytes %q for %s: %v", s, f.Name, err)
+		}
+		if f.Type.T == abi.BytesTy {
+			return b, nil
+		}
+		if len(b) > f.Type.Size {
+			return nil, fmt.Errorf("%q is longer than %s %s", s, f.Type, f.Name)
+		}
+		array := reflect.New(f.Type.Type).Elem()
+		reflect.Copy(array, reflect.ValueOf(b))
+		return array.Interface(), nil
+	default:
+		return nil, fmt.Errorf("unsupported argument type %s for %s", f.Type, f.Name)
+	}
+}
+
+// Integers of up to 64 bits are packed from the matching go type, larger ones from *big.Int
+func (f Field) intArg(s string) (interface{}, error) {
+	n, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return nil, fmt.Errorf("invalid integer %q for %s", s, f.Name)
+	}
+	if f.Type.T == abi.UintTy && n.Sign() < 0 {
+		return nil, fmt.Errorf("negative value %q for %s %s", s, f.Type, f.Name)
+	}
+	if f.Type.Type == reflect.TypeOf(n) {
+		return n, nil
+	}
+
+	value := reflect.New(f.Type.Type).Elem()
+	if f.Type.T == abi.UintTy {
+		if !n.IsUint64() || value.OverflowUint(n.Uint64()) {
+			return nil, fmt.Errorf("%q overflows %s %s", s, f.Type, f.Name)
+		}
+		value.SetUint(n.Uint64())
+	} else {
+		if !n.IsInt64() || value.OverflowInt(n.Int64()) {
+			return nil, fmt.Errorf("%q overflows %s %s", s, f.Type, f.Name)
+		}
+		value.SetInt(n.Int64())
+	}
+
+	return value.Interface(), nil
+}
+
+// Returns true if method arguments for this field can be parsed by ArgValue
+func (f Field) Arg() bool {
+	switch f.Type.T {
+	case abi.AddressTy, abi.HashTy, abi.StringTy, abi.BoolTy, abi.IntTy, abi.UintTy, abi.BytesTy, abi.FixedBytesTy:
+		return true
+	default:
+		return false
+	}
+}
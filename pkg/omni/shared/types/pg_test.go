@@ -0,0 +1,125 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package types_test
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/omni/shared/types"
+)
+
+func newField(name, abiType string, indexed bool) types.Field {
+	t, err := abi.NewType(abiType)
+	Expect(err).NotTo(HaveOccurred())
+	return types.NewField(abi.Argument{Name: name, Type: t, Indexed: indexed})
+}
+
+var _ = Describe("Postgres types", func() {
+	It("maps abi types to postgres types", func() {
+		Expect(newField("a", "uint256", false).PgType).To(Equal("NUMERIC"))
+		Expect(newField("a", "int8", false).PgType).To(Equal("NUMERIC"))
+		Expect(newField("a", "address", false).PgType).To(Equal("CHARACTER VARYING(66)"))
+		Expect(newField("a", "string", false).PgType).To(Equal("TEXT"))
+		Expect(newField("a", "bytes32", false).PgType).To(Equal("BYTEA"))
+		Expect(newField("a", "uint256[]", false).PgType).To(Equal("NUMERIC[]"))
+		Expect(newField("a", "bytes32[3]", false).PgType).To(Equal("BYTEA[]"))
+		Expect(newField("a", "address[][]", false).PgType).To(Equal("JSONB"))
+		Expect(newField("a", "string", true).PgType).To(Equal("CHARACTER VARYING(66)"))
+	})
+
+	It("formats values as postgres input", func() {
+		value, err := newField("a", "uint8", false).PgValue(uint8(18))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal("18"))
+
+		value, err = newField("a", "int256", false).PgValue(big.NewInt(-42))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal("-42"))
+
+		value, err = newField("a", "bytes4", false).PgValue([4]byte{0xa9, 0x05, 0x9c, 0xbb})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal(`\xa9059cbb`))
+
+		value, err = newField("a", "uint256[]", false).PgValue([]*big.Int{big.NewInt(1), big.NewInt(2)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal(`{"1","2"}`))
+
+		value, err = newField("a", "bytes[]", false).PgValue([][]byte{{1}, {2, 3}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal(`{"\\x01","\\x0203"}`))
+
+		value, err = newField("a", "string[]", false).PgValue([]string{`say "hi"`})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal(`{"say \"hi\""}`))
+
+		value, err = newField("a", "uint8[2][]", false).PgValue([][2]uint8{{1, 2}, {3, 4}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(MatchJSON(`[[1,2],[3,4]]`))
+
+		hash := common.HexToHash("0x1234")
+		value, err = newField("a", "string", true).PgValue(hash)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal(hash.Hex()))
+	})
+
+	It("scales fixed point values exactly", func() {
+		field := newField("a", "int128", false)
+		field.Decimals = 18
+
+		value, err := field.PgValue(big.NewInt(1500000000000000000))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal("1.500000000000000000"))
+
+		value, err = field.PgValue(big.NewInt(-5))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal("-0.000000000000000005"))
+	})
+
+	It("fails for values it cannot format", func() {
+		_, err := newField("a", "uint256", false).PgValue(nil)
+		Expect(err).To(HaveOccurred())
+		_, err = newField("a", "uint256", false).PgValue(struct{}{})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("parses method arguments", func() {
+		Expect(newField("owner", "address", false).ArgValue("0xfE9e8709d3215310075d67E3ed32A380CCf451C8")).To(Equal(common.HexToAddress("0xfE9e8709d3215310075d67E3ed32A380CCf451C8")))
+		Expect(newField("id", "uint256", false).ArgValue("0x10")).To(Equal(big.NewInt(16)))
+		Expect(newField("id", "int64", false).ArgValue("-5")).To(Equal(int64(-5)))
+		Expect(newField("id", "uint8", false).ArgValue("255")).To(Equal(uint8(255)))
+		Expect(newField("flag", "bool", false).ArgValue("true")).To(Equal(true))
+		Expect(newField("data", "bytes", false).ArgValue(`\x0102`)).To(Equal([]byte{1, 2}))
+		Expect(newField("key", "bytes4", false).ArgValue("0x01ffc9a7")).To(Equal([4]byte{0x01, 0xff, 0xc9, 0xa7}))
+
+		for _, arg := range []struct{ abiType, value string }{
+			{"address", "0x1234"},
+			{"uint256", "-1"},
+			{"uint8", "256"},
+			{"int256", "ten"},
+			{"bytes4", "0x0102030405"},
+			{"uint256[]", "{1,2}"},
+		} {
+			_, err := newField("a", arg.abiType, false).ArgValue(arg.value)
+			Expect(err).To(HaveOccurred(), arg.abiType+" "+arg.value)
+		}
+	})
+})
@@ -0,0 +1,263 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const wordSize = 32
+
+var (
+	sizedType = regexp.MustCompile(`^(u?int|bytes|u?fixed)([0-9]*)(x([0-9]+))?$`)
+	tt256     = new(big.Int).Lsh(big.NewInt(1), 256)
+)
+
+// Component is an argument, or a component of one, as described in ABI JSON. Tuple arrays and
+// dynamic tuples, which the abi package cannot decode, are kept as Components and decoded into JSON.
+type Component struct {
+	Name       string      `json:"name"`
+	Type       string      `json:"type"`
+	Indexed    bool        `json:"indexed,omitempty"`
+	Components []Component `json:"components,omitempty"`
+}
+
+// Dynamic is true for components encoded out of place, with an offset in their place
+func (c Component) Dynamic() bool {
+	base, suffix := c.splitArray()
+	if strings.HasSuffix(suffix, "[]") || base == "string" || base == "bytes" {
+		return true
+	}
+	if suffix != "" {
+		return c.elem().Dynamic()
+	}
+	for _, component := range c.Components {
+		if component.Dynamic() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Words is the number of 32 byte words a component takes up in place: one for dynamic components
+func (c Component) Words() int {
+	if c.Dynamic() {
+		return 1
+	}
+	base, suffix := c.splitArray()
+	if suffix != "" {
+		length, _ := strconv.Atoi(suffix[strings.LastIndex(suffix, "[")+1 : len(suffix)-1])
+		return length * c.elem().Words()
+	}
+	if base != "tuple" {
+		return 1
+	}
+	words := 0
+	for _, component := range c.Components {
+		words += component.Words()
+	}
+
+	return words
+}
+
+// DecodeJSON decodes a value of this component from the placeholder the abi package unpacked in its
+// place: the offset of a dynamic value within data, or the words of a static one. The result marshals
+// to JSON, with tuples as objects keyed by component name (or index) and numbers kept exact.
+func (c Component) DecodeJSON(placeholder interface{}, data []byte) (interface{}, error) {
+	if offset, ok := placeholder.(*big.Int); ok && c.Dynamic() {
+		if !offset.IsUint64() || offset.Uint64() > uint64(len(data)) {
+			return nil, fmt.Errorf("offset %s of %s out of range", offset, c.Name)
+		}
+		return c.decode(data[offset.Uint64():])
+	}
+	v := reflect.ValueOf(placeholder)
+	if v.Kind() != reflect.Array || c.Dynamic() {
+		return nil, fmt.Errorf("unexpected %T in place of %s %s", placeholder, c.Type, c.Name)
+	}
+	words := make([]byte, 0, v.Len()*wordSize)
+	for i := 0; i < v.Len(); i++ {
+		word, ok := byteSlice(v.Index(i))
+		if !ok {
+			return nil, fmt.Errorf("unexpected %T in place of %s %s", placeholder, c.Type, c.Name)
+		}
+		words = append(words, word...)
+	}
+
+	return c.decode(words)
+}
+
+// decode decodes the component's encoding found at the start of enc
+func (c Component) decode(enc []byte) (interface{}, error) {
+	base, suffix := c.splitArray()
+	if suffix != "" {
+		length := 0
+		if strings.HasSuffix(suffix, "[]") {
+			n, err := word(enc, 0)
+			if err != nil {
+				return nil, err
+			}
+			if !n.IsInt64() || n.Int64() > int64(len(enc)) {
+				return nil, fmt.Errorf("length %s of %s out of range", n, c.Name)
+			}
+			length = int(n.Int64())
+			enc = enc[wordSize:]
+		} else {
+			length, _ = strconv.Atoi(suffix[strings.LastIndex(suffix, "[")+1 : len(suffix)-1])
+		}
+		elems := make([]Component, length)
+		for i := range elems {
+			elems[i] = c.elem()
+		}
+		return decodeSequence(elems, enc)
+	}
+
+	switch base {
+	case "tuple":
+		values, err := decodeSequence(c.Components, enc)
+		if err != nil {
+			return nil, err
+		}
+		tuple := make(map[string]interface{}, len(values))
+		for i, value := range values {
+			name := c.Components[i].Name
+			if name == "" {
+				name = strconv.Itoa(i)
+			}
+			tuple[name] = value
+		}
+		return tuple, nil
+	case "bytes", "string":
+		n, err := word(enc, 0)
+		if err != nil {
+			return nil, err
+		}
+		if !n.IsInt64() || n.Int64() > int64(len(enc)-wordSize) {
+			return nil, fmt.Errorf("length %s of %s out of range", n, c.Name)
+		}
+		b := enc[wordSize : wordSize+int(n.Int64())]
+		if base == "string" {
+			return string(b), nil
+		}
+		return hexutil.Encode(b), nil
+	}
+
+	return c.decodeWord(base, enc)
+}
+
+func (c Component) decodeWord(base string, enc []byte) (interface{}, error) {
+	if len(enc) < wordSize {
+		return nil, fmt.Errorf("missing data for %s %s", c.Type, c.Name)
+	}
+	w := enc[:wordSize]
+	switch base {
+	case "address":
+		return common.BytesToAddress(w).Hex(), nil
+	case "bool":
+		return w[wordSize-1] == 1, nil
+	case "function":
+		return hexutil.Encode(w[:24]), nil
+	}
+	match := sizedType.FindStringSubmatch(base)
+	if match == nil {
+		return nil, fmt.Errorf("unsupported abi type %s", c.Type)
+	}
+	if match[1] == "bytes" {
+		size, _ := strconv.Atoi(match[2])
+		if size < 1 || size > wordSize {
+			return nil, fmt.Errorf("unsupported abi type %s", c.Type)
+		}
+		return hexutil.Encode(w[:size]), nil
+	}
+	n := new(big.Int).SetBytes(w)
+	if !strings.HasPrefix(match[1], "u") && w[0]&0x80 != 0 {
+		n.Sub(n, tt256)
+	}
+	decimals := 0
+	if strings.HasSuffix(match[1], "fixed") {
+		decimals = 18
+		if match[4] != "" {
+			decimals, _ = strconv.Atoi(match[4])
+		}
+	}
+
+	return json.Number(formatDecimal(n, decimals)), nil
+}
+
+// decodeSequence decodes components encoded one after another, as tuples and arrays are, with
+// dynamic components found at offsets from the start of the sequence
+func decodeSequence(components []Component, enc []byte) ([]interface{}, error) {
+	values := make([]interface{}, len(components))
+	head := 0
+	for i, component := range components {
+		at := head
+		if component.Dynamic() {
+			offset, err := word(enc, head)
+			if err != nil {
+				return nil, err
+			}
+			if !offset.IsInt64() || offset.Int64() > int64(len(enc)) {
+				return nil, fmt.Errorf("offset %s of %s out of range", offset, component.Name)
+			}
+			at = int(offset.Int64())
+		}
+		if at > len(enc) {
+			return nil, fmt.Errorf("missing data for %s %s", component.Type, component.Name)
+		}
+		value, err := component.decode(enc[at:])
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+		head += component.Words() * wordSize
+	}
+
+	return values, nil
+}
+
+func word(enc []byte, at int) (*big.Int, error) {
+	if at+wordSize > len(enc) {
+		return nil, fmt.Errorf("missing data at byte %d", at)
+	}
+
+	return new(big.Int).SetBytes(enc[at : at+wordSize]), nil
+}
+
+func (c Component) splitArray() (string, string) {
+	if i := strings.Index(c.Type, "["); i >= 0 {
+		return c.Type[:i], c.Type[i:]
+	}
+
+	return c.Type, ""
+}
+
+// elem is the element component of an array, whose outermost dimension is its last
+func (c Component) elem() Component {
+	elem := c
+	elem.Type = c.Type[:strings.LastIndex(c.Type, "[")]
+
+	return elem
+}
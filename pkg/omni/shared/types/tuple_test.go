@@ -0,0 +1,82 @@
+// VulcanizeDB
+// Copyright © 2018 Vulcanize
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package types_test
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vulcanize/vulcanizedb/pkg/omni/shared/types"
+)
+
+func encodeWords(words ...int64) []byte {
+	var data []byte
+	for _, w := range words {
+		data = append(data, common.LeftPadBytes(big.NewInt(w).Bytes(), 32)...)
+	}
+	return data
+}
+
+var _ = Describe("Tuples", func() {
+	holders := types.Component{Name: "holders", Type: "tuple[]", Components: []types.Component{
+		{Name: "amounts", Type: "ufixed64x2[]"},
+		{Name: "flags", Type: "bool[2]"},
+	}}
+
+	It("tells dynamic tuples from static ones", func() {
+		Expect(holders.Dynamic()).To(BeTrue())
+		Expect(holders.Words()).To(Equal(1))
+		static := types.Component{Type: "tuple[3]", Components: []types.Component{{Type: "uint8"}, {Type: "bytes32[2]"}}}
+		Expect(static.Dynamic()).To(BeFalse())
+		Expect(static.Words()).To(Equal(9))
+	})
+
+	It("decodes nested dynamic values from their offset", func() {
+		// holders at 0x20: one element, whose tuple is at 0x20 past the element offsets
+		data := encodeWords(0, 1, 0x20, 0x60, 1, 0, 2, 150, 5)
+
+		value, err := holders.DecodeJSON(big.NewInt(0x20), data)
+
+		Expect(err).NotTo(HaveOccurred())
+		j, err := json.Marshal(value)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(j).To(MatchJSON(`[{"amounts": [1.50, 0.05], "flags": [true, false]}]`))
+	})
+
+	It("decodes static values from their words", func() {
+		pair := types.Component{Type: "tuple", Components: []types.Component{{Name: "a", Type: "address"}, {Name: "b", Type: "bytes2"}}}
+		var words [2][32]byte
+		copy(words[0][:], common.LeftPadBytes([]byte{1}, 32))
+		copy(words[1][:], []byte{0xab, 0xcd})
+
+		value, err := pair.DecodeJSON(words, nil)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal(map[string]interface{}{"a": "0x0000000000000000000000000000000000000001", "b": "0xabcd"}))
+	})
+
+	It("fails for offsets and lengths outside the data", func() {
+		_, err := holders.DecodeJSON(big.NewInt(0x40), encodeWords(0))
+		Expect(err).To(HaveOccurred())
+		_, err = holders.DecodeJSON(big.NewInt(0), encodeWords(5))
+		Expect(err).To(HaveOccurred())
+	})
+})
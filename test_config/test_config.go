@@ -74,7 +74,10 @@ func setInfuraConfig() {
 		log.Fatal(err)
 	}
 	InfuraClient = config.Client{
-		IPCPath: ipc,
+		IPCPath:  ipc,
+		RPCURL:   Infura.GetString("client.rpcurl"),
+		Headers:  Infura.GetStringMapString("client.headers"),
+		NodeType: Infura.GetString("client.nodetype"),
 	}
 }
 
@@ -92,10 +95,14 @@ func CleanTestDB(db *postgres.DB) {
 	db.MustExec("DELETE FROM blocks")
 	db.MustExec("DELETE FROM headers")
 	db.MustExec("DELETE FROM checked_headers")
+	db.MustExec("DELETE FROM cold_import_checkpoints")
+	db.MustExec("DELETE FROM contract_abis")
 	db.MustExec("DELETE FROM log_filters")
 	db.MustExec("DELETE FROM logs")
 	db.MustExec("DELETE FROM receipts")
+	db.MustExec("DELETE FROM reorgs")
 	db.MustExec("DELETE FROM transactions")
+	db.MustExec("DELETE FROM uncles")
 	db.MustExec("DELETE FROM watched_contracts")
 }
 
@@ -22,13 +22,17 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/jmoiron/sqlx"
+
 	"github.com/vulcanize/vulcanizedb/pkg/config"
 	"github.com/vulcanize/vulcanizedb/pkg/core"
 	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres"
+	"github.com/vulcanize/vulcanizedb/pkg/datastore/postgres/migrations"
 	"github.com/vulcanize/vulcanizedb/pkg/geth"
 )
 
 func LoadPostgres(database config.Database, node core.Node) postgres.DB {
+	checkSchemaVersion(database)
 	db, err := postgres.NewDB(database, node)
 	if err != nil {
 		log.Fatalf("Error loading postgres\n%v", err)
@@ -36,6 +40,30 @@ func LoadPostgres(database config.Database, node core.Node) postgres.DB {
 	return *db
 }
 
+// LoadPostgresWithoutNode connects for commands that only work with stored data and
+// so are not associated with an ethereum node
+func LoadPostgresWithoutNode(database config.Database) postgres.DB {
+	checkSchemaVersion(database)
+	db, err := sqlx.Connect("postgres", config.DbConnectionString(database))
+	if err != nil {
+		log.Fatalf("Error loading postgres\n%v", err)
+	}
+	return postgres.DB{DB: db}
+}
+
+// checkSchemaVersion refuses to run against a schema missing any of the migrations compiled into the binary
+func checkSchemaVersion(database config.Database) {
+	db, err := sqlx.Connect("postgres", config.DbConnectionString(database))
+	if err != nil {
+		log.Fatalf("Error loading postgres\n%v", err)
+	}
+	defer db.Close()
+	err = migrations.CheckVersions(db)
+	if err != nil {
+		log.Fatalf("Database schema check failed\n%v", err)
+	}
+}
+
 func ReadAbiFile(abiFilepath string) string {
 	abiFilepath = AbsFilePath(abiFilepath)
 	abi, err := geth.ReadAbiFile(abiFilepath)